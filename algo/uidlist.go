@@ -148,6 +148,34 @@ func MergeSorted(lists []*task.List) *task.List {
 	return &task.List{Uids: output}
 }
 
+// Difference returns the UIDs in u which aren't in v. Both are expected to be
+// sorted, and neither is modified.
+func Difference(u, v *task.List) *task.List {
+	out := make([]uint64, 0, len(u.Uids))
+	m := len(v.Uids)
+	var k int
+	for _, uid := range u.Uids {
+		for ; k < m && v.Uids[k] < uid; k++ {
+		}
+		if k < m && v.Uids[k] == uid {
+			continue
+		}
+		out = append(out, uid)
+	}
+	return &task.List{Uids: out}
+}
+
+type uint64Slice []uint64
+
+func (s uint64Slice) Len() int           { return len(s) }
+func (s uint64Slice) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+func (s uint64Slice) Less(i, j int) bool { return s[i] < s[j] }
+
+// Sort sorts the UIDs in u in increasing order.
+func Sort(u *task.List) {
+	sort.Sort(uint64Slice(u.Uids))
+}
+
 // IndexOf performs a binary search on the uids slice and returns the index at
 // which it finds the uid, else returns -1
 func IndexOf(u *task.List, uid uint64) int {
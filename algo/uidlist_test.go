@@ -179,6 +179,22 @@ func TestUIDListIntersect5(t *testing.T) {
 	require.Equal(t, u.Uids, []uint64{3})
 }
 
+func TestDifference(t *testing.T) {
+	u := newList([]uint64{1, 2, 3, 4, 5, 8})
+	v := newList([]uint64{0, 2, 4, 6, 7, 8, 9})
+	require.Equal(t, []uint64{1, 3, 5}, Difference(u, v).Uids)
+	require.Equal(t, []uint64{1, 2, 3, 4, 5, 8}, u.Uids)
+	require.Equal(t, []uint64{1, 2, 3, 4, 5, 8}, Difference(u, newList(nil)).Uids)
+	require.Empty(t, Difference(newList(nil), v).Uids)
+	require.Empty(t, Difference(u, u).Uids)
+}
+
+func TestSort(t *testing.T) {
+	u := newList([]uint64{5, 1, 3, 1})
+	Sort(u)
+	require.Equal(t, []uint64{1, 1, 3, 5}, u.Uids)
+}
+
 func TestApplyFilterUint(t *testing.T) {
 	u := newList([]uint64{1, 2, 3, 4, 5})
 	ApplyFilter(u, func(a uint64, idx int) bool { return (a % 2) == 1 })
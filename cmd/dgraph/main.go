@@ -256,28 +256,43 @@ func typeValueFromNQuad(nq *graph.NQuad) (types.Value, error) {
 }
 
 func convertAndApply(ctx context.Context, set []rdf.NQuad, del []rdf.NQuad) (map[string]uint64, error) {
+	m, allocIds, err := convertToMutations(ctx, set, del)
+	if err != nil {
+		return nil, err
+	}
+	if err := applyMutations(ctx, m); err != nil {
+		return nil, x.Wrap(err)
+	}
+	return allocIds, nil
+}
+
+// convertToMutations converts the N-Quads to the mutations to apply, and
+// returns the UIDs allocated for the new nodes.
+func convertToMutations(ctx context.Context, set []rdf.NQuad,
+	del []rdf.NQuad) (*task.Mutations, map[string]uint64, error) {
 	var allocIds map[string]uint64
 	var m task.Mutations
 	var err error
 	var mr mutationResult
 
 	if *nomutations {
-		return nil, fmt.Errorf("Mutations are forbidden on this server.")
+		return nil, nil, fmt.Errorf("Mutations are forbidden on this server.")
+	}
+	for _, nq := range set {
+		if nq.Predicate == x.Star || string(nq.ObjectValue) == x.Star {
+			return nil, nil, x.Errorf("* can only be used in a deletion: %+v", nq)
+		}
 	}
 
 	if mr, err = convertToEdges(ctx, set); err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	m.Set, allocIds = mr.edges, mr.newUids
 	if mr, err = convertToEdges(ctx, del); err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	m.Del = mr.edges
-
-	if err := applyMutations(ctx, &m); err != nil {
-		return nil, x.Wrap(err)
-	}
-	return allocIds, nil
+	return &m, allocIds, nil
 }
 
 // This function is used to run mutations for the requests from different
@@ -298,34 +313,59 @@ func runMutations(ctx context.Context, mu *graph.Mutation) (map[string]uint64, e
 // This function is used to run mutations for the requests received from the
 // http client.
 func mutationHandler(ctx context.Context, mu *gql.Mutation) (map[string]uint64, error) {
+	set, del, err := parseMutation(ctx, mu)
+	if err != nil {
+		return nil, err
+	}
+	return convertAndApply(ctx, set, del)
+}
+
+// handleMutation runs the mutation of res. If it uses the variables of the
+// query, it's run as an upsert, and the results of the query are returned.
+func handleMutation(ctx context.Context, res gql.Result) ([]*query.SubGraph,
+	map[string]uint64, error) {
+	set, del, err := parseMutation(ctx, res.Mutation)
+	if err != nil {
+		return nil, nil, err
+	}
+	if isUpsert(set, del) {
+		return upsertHandler(ctx, res, set, del)
+	}
+	allocIds, err := convertAndApply(ctx, set, del)
+	return nil, allocIds, err
+}
+
+// parseMutation returns the N-Quads to set and delete in mu, once their types
+// are validated.
+func parseMutation(ctx context.Context, mu *gql.Mutation) ([]rdf.NQuad, []rdf.NQuad, error) {
 	var set []rdf.NQuad
 	var del []rdf.NQuad
-	var allocIds map[string]uint64
 	var err error
 
 	if set, err = convertToNQuad(ctx, mu.Set); err != nil {
-		return nil, x.Wrap(err)
+		return nil, nil, x.Wrap(err)
 	}
 	if del, err = convertToNQuad(ctx, mu.Del); err != nil {
-		return nil, x.Wrap(err)
+		return nil, nil, x.Wrap(err)
 	}
 	m := set
 	for _, nquad := range del {
 		m = append(m, nquad)
 	}
 	if err = validateTypes(m); err != nil {
-		return nil, x.Wrap(err)
+		return nil, nil, x.Wrap(err)
 	}
-	if allocIds, err = convertAndApply(ctx, set, del); err != nil {
-		return nil, err
-	}
-	return allocIds, nil
+	return set, del, nil
 }
 
 // validateTypes checks for predicate types present in the schema and validates if the
 // input value is of the correct type
 func validateTypes(nquads []rdf.NQuad) error {
 	for _, nquad := range nquads {
+		if string(nquad.ObjectValue) == x.Star {
+			// A deletion of all the values, which has no type.
+			continue
+		}
 		if t := schema.TypeOf(nquad.Predicate); t != nil && t.IsScalar() {
 			schemaType := t.(types.Scalar)
 			typeID := types.TypeID(nquad.ObjectType)
@@ -357,7 +397,12 @@ func validateTypes(nquads []rdf.NQuad) error {
 	return nil
 }
 
-func queryHandler(w http.ResponseWriter, r *http.Request) {
+// readQuery does what queryHandler and explainHandler have in common before
+// running the query: it checks the method, sets up the context, and reads and
+// parses the query in the body. If the request can't go on, the status is set
+// and ok is false. Otherwise, cancel must be called once done.
+func readQuery(w http.ResponseWriter, r *http.Request, l *query.Latency) (
+	ctx context.Context, cancel context.CancelFunc, res gql.Result, ok bool) {
 	addCorsHeaders(w)
 	if r.Method == "OPTIONS" {
 		return
@@ -367,16 +412,17 @@ func queryHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
-	defer cancel()
-
+	ctx, cancel = context.WithTimeout(context.Background(), time.Minute)
 	if rand.Float64() < *tracing {
 		tr := trace.New("Dgraph", "Query")
-		defer tr.Finish()
 		ctx = trace.NewContext(ctx, tr)
+		cancelCtx := cancel
+		cancel = func() {
+			cancelCtx()
+			tr.Finish()
+		}
 	}
 
-	var l query.Latency
 	l.Start = time.Now()
 	defer r.Body.Close()
 	req, err := ioutil.ReadAll(r.Body)
@@ -384,23 +430,68 @@ func queryHandler(w http.ResponseWriter, r *http.Request) {
 	if err != nil || len(q) == 0 {
 		x.TraceError(ctx, x.Wrapf(err, "Error while reading query"))
 		x.SetStatus(w, x.ErrorInvalidRequest, "Invalid request encountered.")
+		cancel()
 		return
 	}
 
 	x.Trace(ctx, "Query received: %v", q)
-	gq, mu, err := gql.Parse(q)
-
-	if err != nil {
+	if res, err = gql.Parse(q); err != nil {
 		x.TraceError(ctx, x.Wrapf(err, "Error while parsing query"))
 		x.SetStatus(w, x.ErrorInvalidRequest, err.Error())
+		cancel()
 		return
 	}
+	return ctx, cancel, res, true
+}
+
+// processQuery runs the query of res, unless sgl already holds its results.
+// If it fails, the status is set and ok is false.
+func processQuery(ctx context.Context, w http.ResponseWriter, res gql.Result,
+	sgl []*query.SubGraph, l *query.Latency) (out []*query.SubGraph, ok bool) {
+	l.Parsing = time.Since(l.Start)
+	x.Trace(ctx, "Query parsed")
+
+	if sgl == nil {
+		var err error
+		if sgl, err = query.ProcessQuery(ctx, res); err != nil {
+			x.TraceError(ctx, x.Wrapf(err, "Error while executing query"))
+			x.SetStatus(w, x.Error, err.Error())
+			return nil, false
+		}
+	}
+	l.Processing = time.Since(l.Start) - l.Parsing
+	x.Trace(ctx, "Graph processed")
+	return sgl, true
+}
 
+// writeJSON writes js as the response, or the error from producing it.
+func writeJSON(ctx context.Context, w http.ResponseWriter, js []byte, err error) {
+	if err != nil {
+		x.TraceError(ctx, x.Wrapf(err, "Error while converting to Json"))
+		x.SetStatus(w, x.Error, err.Error())
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(js)
+}
+
+func queryHandler(w http.ResponseWriter, r *http.Request) {
+	var l query.Latency
+	ctx, cancel, res, ok := readQuery(w, r, &l)
+	if !ok {
+		return
+	}
+	defer cancel()
+
+	var err error
 	var allocIds map[string]uint64
 	var allocIdsStr map[string]string
-	// If we have mutations, run them first.
-	if mu != nil && (len(mu.Set) > 0 || len(mu.Del) > 0) {
-		if allocIds, err = mutationHandler(ctx, mu); err != nil {
+	var sgl []*query.SubGraph
+	// If we have mutations, run them first, unless they use the variables of
+	// the query.
+	if mu := res.Mutation; mu != nil && (len(mu.Set) > 0 || len(mu.Del) > 0) {
+		sgl, allocIds, err = handleMutation(ctx, res)
+		if err != nil {
 			x.TraceError(ctx, x.Wrapf(err, "Error while handling mutations"))
 			x.SetStatus(w, x.Error, err.Error())
 			return
@@ -412,7 +503,7 @@ func queryHandler(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	if gq == nil || (gq.UID == 0 && gq.Func == nil && len(gq.XID) == 0) {
+	if len(res.Query) == 0 {
 		mp := map[string]interface{}{
 			"code":    x.ErrorOk,
 			"message": "Done",
@@ -426,48 +517,54 @@ func queryHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	sg, err := query.ToSubGraph(ctx, gq)
-	if err != nil {
-		x.TraceError(ctx, x.Wrapf(err, "Error while conversion o internal format"))
-		x.SetStatus(w, x.ErrorInvalidRequest, err.Error())
-		return
-	}
-	l.Parsing = time.Since(l.Start)
-	x.Trace(ctx, "Query parsed")
-
-	rch := make(chan error)
-	go query.ProcessGraph(ctx, sg, nil, rch)
-	err = <-rch
-	if err != nil {
-		x.TraceError(ctx, x.Wrapf(err, "Error while executing query"))
-		x.SetStatus(w, x.Error, err.Error())
+	if sgl, ok = processQuery(ctx, w, res, sgl, &l); !ok {
 		return
 	}
-	l.Processing = time.Since(l.Start) - l.Parsing
-	x.Trace(ctx, "Graph processed")
 
 	if len(*dumpSubgraph) > 0 {
-		x.Checkf(os.MkdirAll(*dumpSubgraph, 0700), *dumpSubgraph)
-		s := time.Now().Format("20060102.150405.000000.gob")
-		filename := path.Join(*dumpSubgraph, s)
-		f, err := os.Create(filename)
-		x.Checkf(err, filename)
-		enc := gob.NewEncoder(f)
-		x.Check(enc.Encode(sg))
-		x.Checkf(f.Close(), filename)
+		x.Checkf(os.MkdirAll(*dumpSubgraph, 0700), "%s", *dumpSubgraph)
+		for i, sg := range sgl {
+			s := time.Now().Format("20060102.150405.000000")
+			filename := path.Join(*dumpSubgraph, fmt.Sprintf("%s.%d.gob", s, i))
+			f, err := os.Create(filename)
+			x.Checkf(err, "%s", filename)
+			enc := gob.NewEncoder(f)
+			x.Check(enc.Encode(sg))
+			x.Checkf(f.Close(), "%s", filename)
+		}
 	}
 
-	js, err := sg.ToJSON(&l)
-	if err != nil {
-		x.TraceError(ctx, x.Wrapf(err, "Error while converting to Json"))
-		x.SetStatus(w, x.Error, err.Error())
-		return
-	}
+	js, err := query.ToJSON(&l, sgl)
 	x.Trace(ctx, "Latencies: Total: %v Parsing: %v Process: %v Json: %v",
 		time.Since(l.Start), l.Parsing, l.Processing, l.Json)
+	writeJSON(ctx, w, js, err)
+}
 
-	w.Header().Set("Content-Type", "application/json")
-	w.Write(js)
+// explainHandler processes the query like queryHandler, but responds with the
+// plan of how it was processed instead of the results, to find out what makes
+// a query slow. Mutations aren't allowed.
+func explainHandler(w http.ResponseWriter, r *http.Request) {
+	var l query.Latency
+	ctx, cancel, res, ok := readQuery(w, r, &l)
+	if !ok {
+		return
+	}
+	defer cancel()
+
+	if mu := res.Mutation; mu != nil && (len(mu.Set) > 0 || len(mu.Del) > 0) {
+		x.SetStatus(w, x.ErrorInvalidRequest, "Mutations cannot be explained.")
+		return
+	}
+	if len(res.Query) == 0 {
+		x.SetStatus(w, x.ErrorInvalidRequest, "No query to explain.")
+		return
+	}
+	sgl, ok := processQuery(ctx, w, res, nil, &l)
+	if !ok {
+		return
+	}
+	js, err := query.Explain(&l, sgl)
+	writeJSON(ctx, w, js, err)
 }
 
 // storeStatsHandler outputs some basic stats for data store.
@@ -542,7 +639,7 @@ func (s *grpcServer) Query(ctx context.Context,
 	var l query.Latency
 	l.Start = time.Now()
 	x.Trace(ctx, "Query received: %v", req.Query)
-	gq, mu, err := gql.Parse(req.Query)
+	res, err := gql.Parse(req.Query)
 	if err != nil {
 		x.TraceError(ctx, x.Wrapf(err, "Error while parsing query"))
 		return resp, err
@@ -550,8 +647,10 @@ func (s *grpcServer) Query(ctx context.Context,
 
 	// If mutations are part of the query, we run them through the mutation handler
 	// same as the http client.
-	if mu != nil && (len(mu.Set) > 0 || len(mu.Del) > 0) {
-		if allocIds, err = mutationHandler(ctx, mu); err != nil {
+	var sgl []*query.SubGraph
+	if mu := res.Mutation; mu != nil && (len(mu.Set) > 0 || len(mu.Del) > 0) {
+		sgl, allocIds, err = handleMutation(ctx, res)
+		if err != nil {
 			x.TraceError(ctx, x.Wrapf(err, "Error while handling mutations"))
 			return resp, err
 		}
@@ -567,29 +666,23 @@ func (s *grpcServer) Query(ctx context.Context,
 	}
 	resp.AssignedUids = allocIds
 
-	if gq == nil || (gq.UID == 0 && len(gq.XID) == 0) {
+	if len(res.Query) == 0 {
 		return resp, err
 	}
 
-	sg, err := query.ToSubGraph(ctx, gq)
-	if err != nil {
-		x.TraceError(ctx, x.Wrapf(err, "Error while conversion to internal format"))
-		return resp, err
-	}
 	l.Parsing = time.Since(l.Start)
 	x.Trace(ctx, "Query parsed")
 
-	rch := make(chan error)
-	go query.ProcessGraph(ctx, sg, nil, rch)
-	err = <-rch
-	if err != nil {
-		x.TraceError(ctx, x.Wrapf(err, "Error while executing query"))
-		return resp, err
+	if sgl == nil {
+		if sgl, err = query.ProcessQuery(ctx, res); err != nil {
+			x.TraceError(ctx, x.Wrapf(err, "Error while executing query"))
+			return resp, err
+		}
 	}
 	l.Processing = time.Since(l.Start) - l.Parsing
 	x.Trace(ctx, "Graph processed")
 
-	node, err := sg.ToProtocolBuffer(&l)
+	node, err := query.ToProtocolBuffer(&l, sgl)
 	if err != nil {
 		x.TraceError(ctx, x.Wrapf(err, "Error while converting to ProtocolBuffer"))
 		return resp, err
@@ -647,6 +740,7 @@ func setupServer(che chan error) {
 	http2 := tcpm.Match(cmux.HTTP2())
 
 	http.HandleFunc("/query", queryHandler)
+	http.HandleFunc("/explain", explainHandler)
 	http.HandleFunc("/debug/store", storeStatsHandler)
 	http.HandleFunc("/admin/shutdown", shutDownHandler)
 	http.HandleFunc("/admin/backup", backupHandler)
@@ -18,9 +18,13 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"strings"
 	"testing"
 	"time"
 
@@ -31,7 +35,9 @@ import (
 	"github.com/dgraph-io/dgraph/loader"
 	"github.com/dgraph-io/dgraph/posting"
 	"github.com/dgraph-io/dgraph/query"
+	"github.com/dgraph-io/dgraph/schema"
 	"github.com/dgraph-io/dgraph/store"
+	"github.com/dgraph-io/dgraph/task"
 	"github.com/dgraph-io/dgraph/worker"
 	"github.com/dgraph-io/dgraph/x"
 )
@@ -108,8 +114,9 @@ func TestQuery(t *testing.T) {
 	defer closeAll(dir1, dir2)
 
 	// Parse GQL into internal query representation.
-	gq, _, err := gql.Parse(q0)
+	res, err := gql.Parse(q0)
 	require.NoError(t, err)
+	gq := res.Query[0]
 
 	ctx := context.Background()
 	g, err := query.ToSubGraph(ctx, gq)
@@ -130,6 +137,33 @@ func TestQuery(t *testing.T) {
 	fmt.Println(string(js))
 }
 
+func TestQueryHandlers(t *testing.T) {
+	dir1, dir2, _, err := prepare()
+	require.NoError(t, err)
+	defer closeAll(dir1, dir2)
+
+	run := func(h http.HandlerFunc, method, body string) map[string]interface{} {
+		w := httptest.NewRecorder()
+		h(w, httptest.NewRequest(method, "/", strings.NewReader(body)))
+		var out map[string]interface{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &out))
+		return out
+	}
+
+	out := run(queryHandler, "POST", q0)
+	require.Contains(t, out, "user")
+	out = run(explainHandler, "POST", q0)
+	require.Contains(t, out, "explain")
+
+	for _, h := range []http.HandlerFunc{queryHandler, explainHandler} {
+		require.Equal(t, x.ErrorInvalidMethod, run(h, "GET", q0)["code"])
+		require.Equal(t, x.ErrorInvalidRequest, run(h, "POST", "")["code"])
+		require.Equal(t, x.ErrorInvalidRequest, run(h, "POST", "{ me(")["code"])
+	}
+	require.Equal(t, x.ErrorInvalidRequest,
+		run(explainHandler, "POST", `mutation { set { <a> <b> <c> . } }`)["code"])
+}
+
 var qm = `
 	mutation {
 		set {
@@ -148,11 +182,11 @@ func TestAssignUid(t *testing.T) {
 	time.Sleep(5 * time.Second) // Wait for ME to become leader.
 
 	// Parse GQL into internal query representation.
-	_, mu, err := gql.Parse(qm)
+	res, err := gql.Parse(qm)
 	require.NoError(t, err)
 
 	ctx := context.Background()
-	allocIds, err := mutationHandler(ctx, mu)
+	allocIds, err := mutationHandler(ctx, res.Mutation)
 	require.NoError(t, err)
 
 	require.EqualValues(t, len(allocIds), 2, "Expected two UIDs to be allocated")
@@ -174,6 +208,208 @@ func TestConvertToEdges(t *testing.T) {
 	require.EqualValues(t, len(mr.edges), 2)
 }
 
+var qu = `
+	mutation {
+		set {
+			<_var_:u> <upsert.email> "alice@example.com" .
+			<_var_:u> <upsert.name> "Alice" .
+		}
+	}
+	query {
+		u as me(has(upsert.email)) {
+			upsert.name
+		}
+	}
+`
+
+func TestUpsert(t *testing.T) {
+	dir1, dir2, _, err := prepare()
+	require.NoError(t, err)
+	defer closeAll(dir1, dir2)
+	time.Sleep(5 * time.Second) // Wait for ME to become leader.
+
+	res, err := gql.Parse(qu)
+	require.NoError(t, err)
+	ctx := context.Background()
+	set, del, err := parseMutation(ctx, res.Mutation)
+	require.NoError(t, err)
+	require.True(t, isUpsert(set, del))
+
+	// Variables are only taken from the subjects and objects.
+	plain, err := convertToNQuad(ctx, `<alice> <upsert.name> "<_var_:u>" .`)
+	require.NoError(t, err)
+	require.False(t, isUpsert(plain, nil))
+
+	// The node is created by the first upsert, as the query finds nothing.
+	sgl, allocIds, err := upsertHandler(ctx, res, set, del)
+	require.NoError(t, err)
+	require.Len(t, allocIds, 1)
+	uid, ok := allocIds["u"]
+	require.True(t, ok)
+	var l query.Latency
+	js, err := query.ToJSON(&l, sgl)
+	require.NoError(t, err)
+	require.JSONEq(t, `{}`, string(js))
+
+	// The second one finds and updates it.
+	sgl, allocIds, err = upsertHandler(ctx, res, set, del)
+	require.NoError(t, err)
+	require.Empty(t, allocIds)
+	js, err = query.ToJSON(&l, sgl)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"me":[{"upsert.name":"Alice"}]}`, string(js))
+
+	res, err = gql.Parse(`{ me(has(upsert.email)) { _uid_ } }`)
+	require.NoError(t, err)
+	sgl, err = query.ProcessQuery(ctx, res)
+	require.NoError(t, err)
+	js, err = query.ToJSON(&l, sgl)
+	require.NoError(t, err)
+	require.JSONEq(t, fmt.Sprintf(`{"me":[{"_uid_":"%#x"}]}`, uid), string(js))
+}
+
+func TestUpsertConcurrent(t *testing.T) {
+	dir1, dir2, _, err := prepare()
+	require.NoError(t, err)
+	defer closeAll(dir1, dir2)
+	time.Sleep(5 * time.Second) // Wait for ME to become leader.
+
+	res, err := gql.Parse(qu)
+	require.NoError(t, err)
+	ctx := context.Background()
+	set, del, err := parseMutation(ctx, res.Mutation)
+	require.NoError(t, err)
+
+	// Either both upserts find no node, and one of them is aborted, or one
+	// runs after the other and finds the node it created.
+	errs := make(chan error, 2)
+	for i := 0; i < 2; i++ {
+		go func() {
+			_, _, err := upsertHandler(ctx, res, set, del)
+			errs <- err
+		}()
+	}
+	var ok int
+	for i := 0; i < 2; i++ {
+		if err := <-errs; err != nil {
+			require.Contains(t, err.Error(), "aborted because of a conflict")
+			continue
+		}
+		ok++
+	}
+	require.NotZero(t, ok)
+
+	res, err = gql.Parse(`{ me(has(upsert.email)) { _uid_ } }`)
+	require.NoError(t, err)
+	sgl, err := query.ProcessQuery(ctx, res)
+	require.NoError(t, err)
+	require.Len(t, sgl, 1)
+	require.Len(t, sgl[0].DestUIDs.Uids, 1)
+}
+
+func TestVarLookups(t *testing.T) {
+	res, err := gql.Parse(`
+		query {
+			me(anyof("email", "alice@example.com"), orderasc: age, first: 1) {
+				f as friend(orderdesc: name, first: 2) @filter(has(alive))
+			}
+		}`)
+	require.NoError(t, err)
+	lookups, ok := varLookups(res.Query[0], true)
+	require.True(t, ok)
+	var got []string
+	for _, q := range lookups {
+		got = append(got, strings.Join(append([]string{q.Attr}, q.SrcFunc...), " "))
+	}
+	require.Equal(t, []string{"friend", "alive has", "name",
+		"email anyof alice@example.com", "age"}, got)
+}
+
+func TestExpandVars(t *testing.T) {
+	nquads, err := convertToNQuad(context.Background(), `
+		<_var_:u> <friend> <_var_:f> .
+		<_var_:u> <name> "Alice" .
+		<_var_:e> <name> "Bob" .`)
+	require.NoError(t, err)
+	vars := map[string]*task.List{
+		"u": &task.List{Uids: []uint64{1}},
+		"f": &task.List{Uids: []uint64{2, 3}},
+		"e": new(task.List),
+	}
+
+	out, err := expandVars(nquads, vars, true)
+	require.NoError(t, err)
+	var got []string
+	for _, nq := range out {
+		got = append(got, nq.Subject+" "+nq.Predicate+" "+nq.ObjectId)
+	}
+	require.Equal(t, []string{
+		"_uid_:0x1 friend _uid_:0x2",
+		"_uid_:0x1 friend _uid_:0x3",
+		"_uid_:0x1 name ",
+		"_new_:e name ",
+	}, got)
+
+	// Nothing is deleted for an empty variable.
+	out, err = expandVars(nquads, vars, false)
+	require.NoError(t, err)
+	require.Len(t, out, 3)
+
+	delete(vars, "f")
+	_, err = expandVars(nquads, vars, true)
+	require.Error(t, err)
+}
+
+func TestDeleteAll(t *testing.T) {
+	schema.ParseBytes([]byte("scalar del_name: string @index"))
+	dir1, dir2, _, err := prepare()
+	require.NoError(t, err)
+	defer closeAll(dir1, dir2)
+	time.Sleep(5 * time.Second) // Wait for ME to become leader.
+
+	ctx := context.Background()
+	mutate := func(m string) {
+		res, err := gql.Parse(m)
+		require.NoError(t, err)
+		_, err = mutationHandler(ctx, res.Mutation)
+		require.NoError(t, err)
+	}
+	check := func(q, expected string) {
+		res, err := gql.Parse(q)
+		require.NoError(t, err)
+		sgl, err := query.ProcessQuery(ctx, res)
+		require.NoError(t, err)
+		var l query.Latency
+		js, err := query.ToJSON(&l, sgl)
+		require.NoError(t, err)
+		require.JSONEq(t, expected, string(js))
+	}
+	byName := `{ me(anyof("del_name", "Alice")) { _uid_ } }`
+
+	mutate(`mutation { set {
+		<_uid_:0x100> <del_name> "Alice" .
+		<_uid_:0x100> <del_friend> <_uid_:0x101> .
+		<_uid_:0x100> <del_friend> <_uid_:0x102> .
+	} }`)
+	check(`{ me(_uid_:0x100) { del_name del_friend { _uid_ } } }`,
+		`{"me":[{"del_name":"Alice","del_friend":[{"_uid_":"0x101"},{"_uid_":"0x102"}]}]}`)
+	check(byName, `{"me":[{"_uid_":"0x100"}]}`)
+
+	mutate(`mutation { delete { <_uid_:0x100> <del_friend> * . } }`)
+	check(`{ me(_uid_:0x100) { del_name del_friend { _uid_ } } }`,
+		`{"me":[{"del_name":"Alice"}]}`)
+
+	// The index entries of the values are removed too.
+	mutate(`mutation { delete { <_uid_:0x100> * * . } }`)
+	check(`{ me(_uid_:0x100) { del_name del_friend { _uid_ } } }`, `{"me":[{}]}`)
+	check(byName, `{}`)
+
+	res, err := gql.Parse(`mutation { set { <_uid_:0x100> <del_name> * . } }`)
+	require.NoError(t, err)
+	_, err = mutationHandler(ctx, res.Mutation)
+	require.Error(t, err)
+}
+
 var q1 = `
 {
 	al(_xid_: alice) {
@@ -207,13 +443,13 @@ func BenchmarkQuery(b *testing.B) {
 
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		gq, _, err := gql.Parse(q1)
+		res, err := gql.Parse(q1)
 		if err != nil {
 			b.Error(err)
 			return
 		}
 		ctx := context.Background()
-		g, err := query.ToSubGraph(ctx, gq)
+		g, err := query.ToSubGraph(ctx, res.Query[0])
 		if err != nil {
 			b.Error(err)
 			return
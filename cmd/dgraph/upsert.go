@@ -0,0 +1,206 @@
+/*
+ * Copyright 2016 Dgraph Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * 		http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/net/context"
+
+	"github.com/dgraph-io/dgraph/gql"
+	"github.com/dgraph-io/dgraph/query"
+	"github.com/dgraph-io/dgraph/rdf"
+	"github.com/dgraph-io/dgraph/task"
+	"github.com/dgraph-io/dgraph/worker"
+	"github.com/dgraph-io/dgraph/x"
+)
+
+// varPrefix starts the subjects and objects in a mutation which stand for the
+// UIDs in a variable of the query, like <_var_:u> for u.
+const varPrefix = "_var_:"
+
+// isUpsert returns true if any of the N-Quads uses the variables of the query.
+func isUpsert(set, del []rdf.NQuad) bool {
+	for _, nquads := range [][]rdf.NQuad{set, del} {
+		for _, nq := range nquads {
+			if strings.HasPrefix(nq.Subject, varPrefix) ||
+				strings.HasPrefix(nq.ObjectId, varPrefix) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// upsertHandler runs the query of res and then the mutation, whose N-Quads
+// set and del use the UID variables of the query, as in
+//
+//	mutation {
+//		set {
+//			<_var_:u> <email> "alice@example.com" .
+//		}
+//	}
+//	query {
+//		u as me(anyof("email", "alice@example.com")) {
+//			name
+//		}
+//	}
+//
+// If u is empty, a new node is created for it, whose UID is returned under u,
+// and the N-Quads deleting its edges are left out. The results of the query
+// are from before the mutation.
+//
+// The query and the mutation run in one transaction, which carries the
+// lookups the variables were found with. It's aborted if another transaction
+// writes to what they read in between, like the email term above, so that
+// two upserts can't both create the node. Only the lookups are guarded: the
+// functions at the root of the blocks defining variables, their filters, the
+// predicates they're sorted by, which decide what first and offset keep, and
+// the edges walked to the variables.
+func upsertHandler(ctx context.Context, res gql.Result, set,
+	del []rdf.NQuad) ([]*query.SubGraph, map[string]uint64, error) {
+	startTs, err := worker.StartTs(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	sgl, vars, err := query.ProcessQueryWithVars(ctx, res)
+	if err != nil {
+		return nil, nil, err
+	}
+	if set, err = expandVars(set, vars, true); err != nil {
+		return nil, nil, err
+	}
+	if del, err = expandVars(del, vars, false); err != nil {
+		return nil, nil, err
+	}
+	m, allocIds, err := convertToMutations(ctx, set, del)
+	if err != nil {
+		return nil, nil, err
+	}
+	m.StartTs = startTs
+	for _, gq := range res.Query {
+		lookups, _ := varLookups(gq, true)
+		m.Lookups = append(m.Lookups, lookups...)
+	}
+	if err := applyMutations(ctx, m); err != nil {
+		return nil, nil, x.Wrap(err)
+	}
+	return sgl, allocIds, nil
+}
+
+// varLookups returns the lookups which the variables defined in gq and its
+// children depend on, and whether there are any such variables. The root of
+// a block is looked up by its function or UIDs, instead of by an edge.
+func varLookups(gq *gql.GraphQuery, root bool) ([]*task.Query, bool) {
+	var out []*task.Query
+	definesVar := len(gq.Var) > 0
+	for _, child := range gq.Children {
+		lookups, ok := varLookups(child, false)
+		if ok {
+			out = append(out, lookups...)
+			definesVar = true
+		}
+	}
+	if !definesVar {
+		return nil, false
+	}
+	if !root {
+		// The UIDs are found by walking the edges of gq.Attr.
+		out = append(out, &task.Query{Attr: gq.Attr})
+	}
+	if f := gq.Func; f != nil && len(f.Attr) > 0 {
+		out = append(out, funcLookup(f))
+	}
+	out = append(out, filterLookups(gq.Filter)...)
+	for _, o := range gq.Order {
+		out = append(out, &task.Query{Attr: o.Attr})
+	}
+	return out, true
+}
+
+func filterLookups(ft *gql.FilterTree) []*task.Query {
+	if ft == nil {
+		return nil
+	}
+	var out []*task.Query
+	if f := ft.Func; f != nil && len(f.Attr) > 0 {
+		out = append(out, funcLookup(f))
+	}
+	for _, child := range ft.Child {
+		out = append(out, filterLookups(child)...)
+	}
+	return out
+}
+
+func funcLookup(f *gql.Function) *task.Query {
+	return &task.Query{
+		Attr:    f.Attr,
+		SrcFunc: append([]string{f.Name}, f.Args...),
+	}
+}
+
+// expandVars replaces each of nquads using variables with one N-Quad for each
+// of their UIDs. For an empty variable, a new node is used if create is set,
+// and else the N-Quad is left out.
+func expandVars(nquads []rdf.NQuad, vars map[string]*task.List,
+	create bool) ([]rdf.NQuad, error) {
+	ids := func(id string) ([]string, error) {
+		if !strings.HasPrefix(id, varPrefix) {
+			return []string{id}, nil
+		}
+		name := id[len(varPrefix):]
+		l, ok := vars[name]
+		if !ok {
+			return nil, x.Errorf("Variable %s used in the mutation isn't defined in the query",
+				name)
+		}
+		if len(l.Uids) == 0 {
+			if create {
+				return []string{"_new_:" + name}, nil
+			}
+			return nil, nil
+		}
+		out := make([]string, 0, len(l.Uids))
+		for _, uid := range l.Uids {
+			out = append(out, fmt.Sprintf("_uid_:%#x", uid))
+		}
+		return out, nil
+	}
+
+	var out []rdf.NQuad
+	for _, nq := range nquads {
+		subjects, err := ids(nq.Subject)
+		if err != nil {
+			return nil, err
+		}
+		objects := []string{nq.ObjectId}
+		if len(nq.ObjectId) > 0 {
+			if objects, err = ids(nq.ObjectId); err != nil {
+				return nil, err
+			}
+		}
+		for _, s := range subjects {
+			for _, o := range objects {
+				enq := nq
+				enq.Subject, enq.ObjectId = s, o
+				out = append(out, enq)
+			}
+		}
+	}
+	return out, nil
+}
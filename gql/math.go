@@ -0,0 +1,322 @@
+/*
+ * Copyright 2016 Dgraph Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * 		http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gql
+
+import (
+	"bytes"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/dgraph-io/dgraph/x"
+)
+
+// MathTree is the parsed form of an expression given with math(), like
+// math(likes / (views + 1)). An inner node applies the operator or function
+// in Fn to its children. A leaf holds either the name of a predicate in Var,
+// whose value is taken for each node, or a constant.
+type MathTree struct {
+	Fn    string
+	Var   string
+	Const float64
+	Child []*MathTree
+}
+
+// Precedence of the binary operators in math expressions. The unary - and !
+// bind tighter than all of them.
+var mathBinaryPrec = map[string]int{
+	"||": 1,
+	"&&": 2,
+	"==": 3, "!=": 3,
+	"<": 4, "<=": 4, ">": 4, ">=": 4,
+	"+": 5, "-": 5,
+	"*": 6, "/": 6, "%": 6,
+}
+
+// mathFuncArgs holds the number of arguments of each math function, with -1
+// for any number of at least one.
+var mathFuncArgs = map[string]int{
+	"ln":    1,
+	"exp":   1,
+	"sqrt":  1,
+	"abs":   1,
+	"floor": 1,
+	"ceil":  1,
+	"pow":   2,
+	"min":   -1,
+	"max":   -1,
+}
+
+// IsBool returns true if the expression results in a boolean, which is when
+// its outermost operator is a comparison or a logical one.
+func (t *MathTree) IsBool() bool {
+	switch t.Fn {
+	case "||", "&&", "==", "!=", "<", "<=", ">", ">=", "!":
+		return true
+	}
+	return false
+}
+
+// Vars returns the predicates used in the expression, sorted and without
+// duplicates.
+func (t *MathTree) Vars() []string {
+	seen := make(map[string]bool)
+	var walk func(t *MathTree)
+	walk = func(t *MathTree) {
+		if len(t.Var) > 0 {
+			seen[t.Var] = true
+		}
+		for _, c := range t.Child {
+			walk(c)
+		}
+	}
+	walk(t)
+	out := make([]string, 0, len(seen))
+	for v := range seen {
+		out = append(out, v)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// debugString converts the expression to prefix form, like (+ a 1). Good for
+// testing, debugging.
+func (t *MathTree) debugString() string {
+	if len(t.Fn) == 0 {
+		if len(t.Var) > 0 {
+			return t.Var
+		}
+		return strconv.FormatFloat(t.Const, 'g', -1, 64)
+	}
+	var buf bytes.Buffer
+	buf.WriteRune('(')
+	buf.WriteString(t.Fn)
+	for _, c := range t.Child {
+		buf.WriteRune(' ')
+		buf.WriteString(c.debugString())
+	}
+	buf.WriteRune(')')
+	return buf.String()
+}
+
+const (
+	mathNumber = iota
+	mathName
+	mathOp
+	mathLeftRound
+	mathRightRound
+	mathComma
+)
+
+type mathToken struct {
+	typ int
+	val string
+}
+
+// mathTokens splits the expression s into numbers, names, operators,
+// parentheses and commas.
+func mathTokens(s string) ([]mathToken, error) {
+	var out []mathToken
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '(':
+			out = append(out, mathToken{mathLeftRound, "("})
+			i++
+		case c == ')':
+			out = append(out, mathToken{mathRightRound, ")"})
+			i++
+		case c == ',':
+			out = append(out, mathToken{mathComma, ","})
+			i++
+		case c >= '0' && c <= '9' || c == '.':
+			j := i
+			for j < len(s) && (s[j] >= '0' && s[j] <= '9' || s[j] == '.') {
+				j++
+			}
+			out = append(out, mathToken{mathNumber, s[i:j]})
+			i = j
+		case isNameBegin(rune(c)) || c == '~':
+			j := i + 1
+			for j < len(s) && (isNameBegin(rune(s[j])) || s[j] >= '0' && s[j] <= '9' ||
+				s[j] == '.') {
+				j++
+			}
+			out = append(out, mathToken{mathName, s[i:j]})
+			i = j
+		default:
+			// Operators are one or two characters long. The longest match wins.
+			if i+1 < len(s) {
+				if _, ok := mathBinaryPrec[s[i:i+2]]; ok {
+					out = append(out, mathToken{mathOp, s[i : i+2]})
+					i += 2
+					continue
+				}
+			}
+			if _, ok := mathBinaryPrec[s[i:i+1]]; ok || c == '!' {
+				out = append(out, mathToken{mathOp, s[i : i+1]})
+				i++
+				continue
+			}
+			return nil, x.Errorf("Unexpected character %q in math expression: %s", c, s)
+		}
+	}
+	return out, nil
+}
+
+type mathParser struct {
+	tokens []mathToken
+	pos    int
+	expr   string
+}
+
+func (p *mathParser) peek() *mathToken {
+	if p.pos >= len(p.tokens) {
+		return nil
+	}
+	return &p.tokens[p.pos]
+}
+
+func (p *mathParser) next() *mathToken {
+	t := p.peek()
+	if t != nil {
+		p.pos++
+	}
+	return t
+}
+
+// parseBinary parses the operators with a precedence of at least minPrec.
+// Operators of the same precedence are left associative.
+func (p *mathParser) parseBinary(minPrec int) (*MathTree, error) {
+	lhs, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		t := p.peek()
+		if t == nil || t.typ != mathOp {
+			return lhs, nil
+		}
+		prec, ok := mathBinaryPrec[t.val]
+		if !ok || prec < minPrec {
+			return lhs, nil
+		}
+		p.next()
+		rhs, err := p.parseBinary(prec + 1)
+		if err != nil {
+			return nil, err
+		}
+		lhs = &MathTree{Fn: t.val, Child: []*MathTree{lhs, rhs}}
+	}
+}
+
+func (p *mathParser) parseUnary() (*MathTree, error) {
+	if t := p.peek(); t != nil && t.typ == mathOp && (t.val == "-" || t.val == "!") {
+		p.next()
+		c, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &MathTree{Fn: t.val, Child: []*MathTree{c}}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *mathParser) parsePrimary() (*MathTree, error) {
+	t := p.next()
+	if t == nil {
+		return nil, x.Errorf("Unexpected end of math expression: %s", p.expr)
+	}
+	switch t.typ {
+	case mathNumber:
+		f, err := strconv.ParseFloat(t.val, 64)
+		if err != nil {
+			return nil, x.Errorf("Invalid number %q in math expression: %s", t.val, p.expr)
+		}
+		return &MathTree{Const: f}, nil
+
+	case mathName:
+		if n := p.peek(); n == nil || n.typ != mathLeftRound {
+			return &MathTree{Var: t.val}, nil
+		}
+		p.next()
+		return p.parseFunc(t.val)
+
+	case mathLeftRound:
+		e, err := p.parseBinary(1)
+		if err != nil {
+			return nil, err
+		}
+		if n := p.next(); n == nil || n.typ != mathRightRound {
+			return nil, x.Errorf("Expected ) in math expression: %s", p.expr)
+		}
+		return e, nil
+	}
+	return nil, x.Errorf("Unexpected %q in math expression: %s", t.val, p.expr)
+}
+
+// parseFunc parses the arguments of the function name, after its (.
+func (p *mathParser) parseFunc(name string) (*MathTree, error) {
+	nargs, ok := mathFuncArgs[name]
+	if !ok {
+		return nil, x.Errorf("Unknown function %s in math expression: %s", name, p.expr)
+	}
+	f := &MathTree{Fn: name}
+	for {
+		arg, err := p.parseBinary(1)
+		if err != nil {
+			return nil, err
+		}
+		f.Child = append(f.Child, arg)
+		n := p.next()
+		if n != nil && n.typ == mathComma {
+			continue
+		}
+		if n == nil || n.typ != mathRightRound {
+			return nil, x.Errorf("Expected ) after the arguments of %s in math expression: %s",
+				name, p.expr)
+		}
+		break
+	}
+	if nargs > 0 && len(f.Child) != nargs {
+		return nil, x.Errorf("Function %s requires %d arguments, but got %d", name, nargs,
+			len(f.Child))
+	}
+	return f, nil
+}
+
+// parseMath parses the expression given inside math(), like likes / (views + 1).
+func parseMath(expr string) (*MathTree, error) {
+	tokens, err := mathTokens(expr)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, x.Errorf("Empty math expression")
+	}
+	p := &mathParser{tokens: tokens, expr: strings.TrimSpace(expr)}
+	t, err := p.parseBinary(1)
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, x.Errorf("Unexpected %q in math expression: %s", p.tokens[p.pos].val, p.expr)
+	}
+	return t, nil
+}
@@ -0,0 +1,60 @@
+/*
+ * Copyright 2016 Dgraph Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * 		http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMathExpr(t *testing.T) {
+	tests := []struct {
+		in, out string
+	}{
+		{"1 + 2 * 3", "(+ 1 (* 2 3))"},
+		{"(1 + 2) * 3", "(* (+ 1 2) 3)"},
+		{"a - b - c", "(- (- a b) c)"},
+		{"-a * 2", "(* (- a) 2)"},
+		{"a + 1 > b && !(c == 2) || d", "(|| (&& (> (+ a 1) b) (! (== c 2))) d)"},
+		{"max(a, b, 3) <= ln(c)", "(<= (max a b 3) (ln c))"},
+		{"pow(a, 0.5) % 2", "(% (pow a 0.5) 2)"},
+	}
+	for _, test := range tests {
+		tree, err := parseMath(test.in)
+		require.NoError(t, err, test.in)
+		require.Equal(t, test.out, tree.debugString(), test.in)
+	}
+}
+
+func TestParseMathExprError(t *testing.T) {
+	for _, in := range []string{"", "a +", "(a", "a b", "pow(a)", "foo(a)", "a $ b", "1.2.3"} {
+		_, err := parseMath(in)
+		require.Error(t, err, in)
+	}
+}
+
+func TestMathVars(t *testing.T) {
+	tree, err := parseMath("likes / (views + 1) + likes * max(age, 1)")
+	require.NoError(t, err)
+	require.Equal(t, []string{"age", "likes", "views"}, tree.Vars())
+	require.False(t, tree.IsBool())
+
+	tree, err = parseMath("age >= 18")
+	require.NoError(t, err)
+	require.True(t, tree.IsBool())
+}
@@ -19,6 +19,7 @@ package gql
 import (
 	"bytes"
 	"encoding/json"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -39,11 +40,124 @@ type GraphQuery struct {
 	Children []*GraphQuery
 	Filter   *FilterTree
 
+	// Langs are the languages to get the value in, in order of preference,
+	// like fr and en for name@fr:en. "." stands for the value without a
+	// language.
+	Langs []string
+
+	// Var is the name of the variable which the UIDs of this node are stored
+	// in, like f in f as friend.
+	Var string
+	// NeedsVar is the name of the variable whose UIDs are used as the root of
+	// this query block, like f in me(id: var(f)).
+	NeedsVar string
+
+	// Recurse is set by the @recurse directive. The children of this node are
+	// then expanded repeatedly, up to Args["depth"] levels if it is specified.
+	Recurse bool
+
+	// Cascade is set by the @cascade directive. Nodes missing any of their
+	// children in the result are then removed, at this level and below.
+	Cascade bool
+
+	// Normalize is set by the @normalize directive on a query block. The
+	// results are then flattened, keeping only the aliased fields.
+	Normalize bool
+
+	// GroupBy is the predicate given with @groupby. The UIDs of this node are
+	// then grouped by its value, or by the UIDs it points to, and only the
+	// aggregations over each group are returned.
+	GroupBy string
+
+	// Facets is set by the @facets directive, to get the facets on the edges
+	// of this predicate. FacetsFilter keeps only the edges whose facets match
+	// it, as in @facets(eq("close", "true")).
+	Facets       *Facets
+	FacetsFilter *FilterTree
+
+	// Order holds the predicates to sort by, given with orderasc or orderdesc
+	// (or order, for ascending), in the order they were given. The later ones
+	// break ties between the earlier ones.
+	Order []*Order
+
+	// MathExpr is the expression given with math(), like age * 12 in
+	// age_in_months: math(age * 12). The value of the node is computed from
+	// it, and Alias is its name.
+	MathExpr *MathTree
+
+	// ShortestPath is set for a shortest path query block, like
+	// shortest(from: 0x01, to: 0x02). Its children are the predicates which
+	// can be followed along the path.
+	ShortestPath *ShortestPath
+
 	// Internal fields below.
 	// If gq.fragment is nonempty, then it is a fragment reference / spread.
 	fragment string
 }
 
+// shortestPathBlock is the name of the query blocks finding the shortest path
+// between two nodes.
+const shortestPathBlock = "shortest"
+
+// ShortestPath holds the arguments of a shortest path query block.
+type ShortestPath struct {
+	From, To uint64
+	// Depth is the maximum number of edges in the path, and MaxVisited the
+	// maximum number of nodes reached while searching for it. Zero means the
+	// default.
+	Depth      int
+	MaxVisited int
+	// Weight is the facet holding the weight of each edge, like distance in
+	// shortest(from: 0x01, to: 0x02, weight: distance). Without it, each edge
+	// weighs one.
+	Weight string
+}
+
+// parseShortestPath returns the arguments of a shortest path query block.
+func parseShortestPath(args []pair) (*ShortestPath, error) {
+	sp := new(ShortestPath)
+	for _, p := range args {
+		var err error
+		switch p.Key {
+		case "from":
+			sp.From, err = strconv.ParseUint(p.Val, 0, 64)
+		case "to":
+			sp.To, err = strconv.ParseUint(p.Val, 0, 64)
+		case "depth", "maxvisited":
+			var n int64
+			if n, err = strconv.ParseInt(p.Val, 0, 32); err == nil && n <= 0 {
+				err = x.Errorf("It should be positive")
+			}
+			if p.Key == "depth" {
+				sp.Depth = int(n)
+			} else {
+				sp.MaxVisited = int(n)
+			}
+		case "weight":
+			sp.Weight = p.Val
+		default:
+			return nil, x.Errorf("Unexpected argument %s in shortest path query", p.Key)
+		}
+		if err != nil {
+			return nil, x.Wrapf(err, "Invalid %s in shortest path query", p.Key)
+		}
+	}
+	if sp.From == 0 {
+		return nil, x.Errorf("Expecting from in shortest path query")
+	}
+	if sp.To == 0 {
+		return nil, x.Errorf("Expecting to in shortest path query")
+	}
+	return sp, nil
+}
+
+// Result struct contains the query blocks and the mutation, if any, parsed
+// from the request.
+type Result struct {
+	Query    []*GraphQuery
+	Mutation *Mutation
+}
+
 // Mutation stores the strings corresponding to set and delete operations.
 type Mutation struct {
 	Set string
@@ -77,16 +191,71 @@ type varMap map[string]varInfo
 
 // FilterTree is the result of parsing the filter directive.
 type FilterTree struct {
-	Op    string
+	Op    string // & or |, or ! for not which has a single child.
 	Func  *Function
 	Child []*FilterTree
 }
 
+// Facets holds the arguments of the @facets directive, like
+// @facets(since, orderdesc: weight).
+type Facets struct {
+	Keys      []string // Sorted. All the facets are returned if empty.
+	OrderKey  string   // Facet to sort the edges by, if any.
+	OrderDesc bool
+}
+
+// Order is a predicate to sort by, like in friend(orderdesc: age).
+type Order struct {
+	Attr string
+	Desc bool
+}
+
 // Function holds the information about gql functions.
 type Function struct {
-	Attr string
-	Name string   // Specifies the name of the function.
-	Args []string // Contains the arguments of the function.
+	Attr    string
+	Name    string   // Specifies the name of the function.
+	Args    []string // Contains the arguments of the function.
+	IsCount bool     // Set if the function is on the number of edges, like gt(count(friend), 10).
+
+	// MathExpr is the expression of a math() filter, like
+	// math(age * 12 > 200). Such a filter keeps the nodes for which it is
+	// true, or not zero.
+	MathExpr *MathTree
+
+	// NeedsVar is the name of the variable holding the UIDs for a uid_in
+	// filter, like f in uid_in(friend, var(f)).
+	NeedsVar string
+}
+
+// addArg adds an argument of f. The first one is the attribute, which is
+// given as count(attr) to compare the number of edges of attr.
+func (f *Function) addArg(arg string) {
+	if len(f.Attr) > 0 {
+		f.Args = append(f.Args, arg)
+		return
+	}
+	if strings.HasPrefix(arg, "count(") && strings.HasSuffix(arg, ")") {
+		f.IsCount = true
+		arg = strings.TrimSpace(arg[len("count(") : len(arg)-1])
+	}
+	f.Attr = arg
+}
+
+// parseUidIn checks the arguments of uid_in, which are a predicate and either
+// a UID or a variable, like uid_in(friend, 0x01) or uid_in(friend, var(f)).
+func (f *Function) parseUidIn() error {
+	if len(f.Attr) == 0 || len(f.Args) != 1 {
+		return x.Errorf("Function uid_in requires a predicate and a UID or variable")
+	}
+	if name, ok := parseVarRef(f.Args[0]); ok {
+		f.NeedsVar = name
+		f.Args = nil
+		return nil
+	}
+	if _, err := strconv.ParseUint(f.Args[0], 0, 64); err != nil {
+		return x.Errorf("Invalid UID %q for uid_in", f.Args[0])
+	}
+	return nil
 }
 
 // filterOpPrecedence is a map from filterOp (a string) to its precedence.
@@ -94,6 +263,7 @@ var filterOpPrecedence map[string]int
 
 func init() {
 	filterOpPrecedence = map[string]int{
+		"!": 3,
 		"&": 2,
 		"|": 1,
 	}
@@ -289,11 +459,11 @@ func substituteVariables(gq *GraphQuery, vmap varMap) error {
 
 // Parse initializes and runs the lexer. It also constructs the GraphQuery subgraph
 // from the lexed items.
-func Parse(input string) (gq *GraphQuery, mu *Mutation, rerr error) {
+func Parse(input string) (res Result, rerr error) {
 	l := &lex.Lexer{}
 	query, vmap, err := parseQueryWithVariables(input)
 	if err != nil {
-		return nil, nil, err
+		return res, err
 	}
 
 	l.Init(query)
@@ -303,63 +473,126 @@ func Parse(input string) (gq *GraphQuery, mu *Mutation, rerr error) {
 	for item := range l.Items {
 		switch item.Typ {
 		case lex.ItemError:
-			return nil, nil, x.Errorf(item.Val)
+			return res, x.Errorf("%s", item.Val)
 		case itemText:
 			continue
 
 		case itemOpType:
 			if item.Val == "mutation" {
-				if mu != nil {
-					return nil, nil, x.Errorf("Only one mutation block allowed.")
+				if res.Mutation != nil {
+					return res, x.Errorf("Only one mutation block allowed.")
 				}
-				if mu, rerr = getMutation(l); rerr != nil {
-					return nil, nil, rerr
+				if res.Mutation, rerr = getMutation(l); rerr != nil {
+					return res, rerr
 				}
 			} else if item.Val == "fragment" {
 				// TODO(jchiu0): This is to be done in ParseSchema once it is ready.
 				fnode, rerr := getFragment(l)
 				if rerr != nil {
-					return nil, nil, rerr
+					return res, rerr
 				}
 				fmap[fnode.Name] = fnode
 			} else if item.Val == "query" {
-				if gq, rerr = getVariablesAndQuery(l, vmap); rerr != nil {
-					return nil, nil, rerr
+				if res.Query, rerr = getVariablesAndQuery(l, vmap); rerr != nil {
+					return res, rerr
 				}
 			}
 		case itemLeftCurl:
-			if gq, rerr = getQuery(l); rerr != nil {
-				return nil, nil, rerr
+			if res.Query, rerr = getQueries(l); rerr != nil {
+				return res, rerr
 			}
 		}
 	}
 
-	if gq != nil {
+	for _, gq := range res.Query {
 		// Try expanding fragments using fragment map.
 		if err := gq.expandFragments(fmap); err != nil {
-			return nil, nil, err
+			return res, err
 		}
 
 		// Substitute all variables with corresponding values
 		if err := substituteVariables(gq, vmap); err != nil {
-			return nil, nil, err
+			return res, err
 		}
 	}
 
-	return gq, mu, nil
+	if err := checkUIDVars(res.Query); err != nil {
+		return res, err
+	}
+	return res, nil
+}
+
+// collectVars adds the names of the UID variables defined in gq and its
+// children to vars.
+func collectVars(gq *GraphQuery, vars map[string]bool) {
+	if len(gq.Var) > 0 {
+		vars[gq.Var] = true
+	}
+	for _, child := range gq.Children {
+		collectVars(child, vars)
+	}
+}
+
+// collectFilterVars adds the names of the UID variables used by the filters in
+// gq and its children to vars.
+func collectFilterVars(gq *GraphQuery, vars map[string]bool) {
+	var walk func(ft *FilterTree)
+	walk = func(ft *FilterTree) {
+		if ft == nil {
+			return
+		}
+		if ft.Func != nil && len(ft.Func.NeedsVar) > 0 {
+			vars[ft.Func.NeedsVar] = true
+		}
+		for _, c := range ft.Child {
+			walk(c)
+		}
+	}
+	walk(gq.Filter)
+	for _, child := range gq.Children {
+		collectFilterVars(child, vars)
+	}
+}
+
+// checkUIDVars checks that every query block using a UID variable comes after
+// the block defining it.
+func checkUIDVars(queries []*GraphQuery) error {
+	defined := make(map[string]bool)
+	for _, gq := range queries {
+		if len(gq.NeedsVar) > 0 && !defined[gq.NeedsVar] {
+			return x.Errorf("Variable %s should be defined in an earlier block",
+				gq.NeedsVar)
+		}
+		needs := make(map[string]bool)
+		collectFilterVars(gq, needs)
+		for v := range needs {
+			if !defined[v] {
+				return x.Errorf("Variable %s should be defined in an earlier block", v)
+			}
+		}
+		vars := make(map[string]bool)
+		collectVars(gq, vars)
+		for v := range vars {
+			if defined[v] {
+				return x.Errorf("Variable %s is defined more than once", v)
+			}
+			defined[v] = true
+		}
+	}
+	return nil
 }
 
 // getVariablesAndQuery checks if the query has a variable list and stores it in
 // vmap. For variable list to be present, the query should have a name which is
 // also checked for. It also calls getQuery to create the GraphQuery object tree.
-func getVariablesAndQuery(l *lex.Lexer, vmap varMap) (gq *GraphQuery,
+func getVariablesAndQuery(l *lex.Lexer, vmap varMap) (queries []*GraphQuery,
 	rerr error) {
 	var name string
 L2:
 	for item := range l.Items {
 		switch item.Typ {
 		case lex.ItemError:
-			return nil, x.Errorf(item.Val)
+			return nil, x.Errorf("%s", item.Val)
 		case itemText:
 			v := strings.TrimSpace(item.Val)
 			if len(v) > 0 {
@@ -381,13 +614,28 @@ L2:
 				return nil, rerr
 			}
 		case itemLeftCurl:
-			if gq, rerr = getQuery(l); rerr != nil {
+			if queries, rerr = getQueries(l); rerr != nil {
 				return nil, rerr
 			}
 			break L2
 		}
 	}
-	return gq, nil
+	return queries, nil
+}
+
+// getQueries parses the query blocks inside the outermost {}, until the
+// closing }.
+func getQueries(l *lex.Lexer) (queries []*GraphQuery, rerr error) {
+	for {
+		gq, rerr := getQuery(l)
+		if rerr != nil {
+			return nil, rerr
+		}
+		if gq == nil {
+			return queries, nil
+		}
+		queries = append(queries, gq)
+	}
 }
 
 // getQuery creates a GraphQuery object tree by calling getRoot
@@ -395,21 +643,32 @@ L2:
 func getQuery(l *lex.Lexer) (gq *GraphQuery, rerr error) {
 	// First, get the root
 	gq, rerr = getRoot(l)
-	if rerr != nil {
+	if rerr != nil || gq == nil {
 		return nil, rerr
 	}
 
-	// Recurse to deeper levels through godeep.
-	item := <-l.Items
-	if item.Typ == itemLeftCurl {
-		if rerr = godeep(l, gq); rerr != nil {
-			return nil, rerr
+	// Directives on the root come before the {. Recurse to deeper levels
+	// through godeep.
+	for item := range l.Items {
+		switch item.Typ {
+		case itemDirectiveName:
+			if rerr = parseDirective(l, gq, item.Val); rerr != nil {
+				return nil, rerr
+			}
+		case itemLeftRound:
+			if rerr = parseQueryArgs(l, gq); rerr != nil {
+				return nil, rerr
+			}
+		case itemLeftCurl:
+			if rerr = godeep(l, gq); rerr != nil {
+				return nil, rerr
+			}
+			return gq, nil
+		default:
+			return nil, x.Errorf("Malformed Query. Missing {")
 		}
-	} else {
-		return nil, x.Errorf("Malformed Query. Missing {")
 	}
-
-	return gq, nil
+	return nil, x.Errorf("Malformed Query. Missing {")
 }
 
 // getFragment parses a fragment definition (not reference).
@@ -622,9 +881,15 @@ func (t *FilterTree) stringHelper(buf *bytes.Buffer) {
 		_, err = buf.WriteString(t.Func.Name)
 		x.Check(err)
 
-		if len(t.Func.Attr) > 0 {
+		if t.Func.MathExpr != nil {
+			_, err = buf.WriteString(" " + t.Func.MathExpr.debugString())
+			x.Check(err)
+		} else if len(t.Func.Attr) > 0 {
 			args := make([]string, len(t.Func.Args)+1)
 			args[0] = t.Func.Attr
+			if t.Func.IsCount {
+				args[0] = "count(" + t.Func.Attr + ")"
+			}
 			copy(args[1:], t.Func.Args)
 
 			for _, arg := range args {
@@ -648,6 +913,8 @@ func (t *FilterTree) stringHelper(buf *bytes.Buffer) {
 		_, err = buf.WriteString("AND")
 	case "|":
 		_, err = buf.WriteString("OR")
+	case "!":
+		_, err = buf.WriteString("NOT")
 	default:
 		err = x.Errorf("Unknown operator: %q", t.Op)
 	}
@@ -680,43 +947,62 @@ func (s *filterTreeStack) peek() *FilterTree {
 	return s.a[len(s.a)-1]
 }
 
-func evalStack(opStack, valueStack *filterTreeStack) {
+// evalStack applies the operator at the top of opStack to the filters at the
+// top of valueStack. The not operator takes one filter, and the others two.
+func evalStack(opStack, valueStack *filterTreeStack) error {
 	topOp := opStack.pop()
-	topVal1 := valueStack.pop()
-	topVal2 := valueStack.pop()
-	topOp.Child = []*FilterTree{topVal2, topVal1}
+	if topOp.Op == "!" {
+		if valueStack.empty() {
+			return x.Errorf("Expected a filter after not")
+		}
+		topOp.Child = []*FilterTree{valueStack.pop()}
+	} else {
+		if valueStack.size() < 2 {
+			return x.Errorf("Expected filters on both sides of %s", topOp.Op)
+		}
+		topVal1 := valueStack.pop()
+		topVal2 := valueStack.pop()
+		topOp.Child = []*FilterTree{topVal2, topVal1}
+	}
 	valueStack.push(topOp)
+	return nil
 }
 
-func parseFunction(l *lex.Lexer) (*Function, error) {
+func parseFunction(l *lex.Lexer) (*Function, []pair, error) {
 	var g *Function
+	var args []pair
 	for item := range l.Items {
 		if item.Typ == itemFilterFunc { // Value.
 			g = &Function{Name: item.Val}
 			itemInFunc := <-l.Items
 			if itemInFunc.Typ != itemLeftRound {
-				return nil, x.Errorf("Expected ( after func name [%s]", g.Name)
+				return nil, nil, x.Errorf("Expected ( after func name [%s]", g.Name)
 			}
 			for itemInFunc = range l.Items {
 				if itemInFunc.Typ == itemRightRound {
 					break
 				} else if itemInFunc.Typ != itemFilterFuncArg {
-					return nil, x.Errorf("Expected arg after func [%s], but got item %v",
+					return nil, nil, x.Errorf("Expected arg after func [%s], but got item %v",
 						g.Name, itemInFunc)
 				}
-				if len(g.Attr) == 0 {
-					g.Attr = itemInFunc.Val
-				} else {
-					g.Args = append(g.Args, itemInFunc.Val)
-				}
+				g.addArg(itemInFunc.Val)
+			}
+		} else if item.Typ == itemArgument {
+			// Arguments like first: 10 follow the function, up to the ).
+			var err error
+			if args, err = parseArguments(l); err != nil {
+				return nil, nil, err
 			}
+			break
 		} else if item.Typ == itemRightRound {
 			break
+		} else if item.Typ == lex.ItemError {
+			return nil, nil, x.Errorf("%s", item.Val)
 		} else {
-			return nil, x.Errorf("Expected a function but got %q", item.Val)
+			return nil, nil, x.Errorf("Expected a function but got %q", item.Val)
 		}
 	}
-	return g, nil
+	return g, args, nil
 }
 
 // parseFilter parses the filter directive to produce a QueryFilter / parse tree.
@@ -725,12 +1011,16 @@ func parseFilter(l *lex.Lexer) (*FilterTree, error) {
 	if item.Typ != itemLeftRound {
 		return nil, x.Errorf("Expected ( after filter directive")
 	}
+	return parseFilterInside(l)
+}
 
+// parseFilterInside parses a filter after its opening (, up to the matching ).
+func parseFilterInside(l *lex.Lexer) (*FilterTree, error) {
 	opStack := new(filterTreeStack)
 	opStack.push(&FilterTree{Op: "("}) // Push ( onto operator stack.
 	valueStack := new(filterTreeStack)
 
-	for item = range l.Items {
+	for item := range l.Items {
 		if item.Typ == itemFilterFunc { // Value.
 			f := &Function{}
 			leaf := &FilterTree{Func: f}
@@ -748,15 +1038,27 @@ func parseFilter(l *lex.Lexer) (*FilterTree, error) {
 					return nil, x.Errorf("Expected arg after func [%s], but got item %v",
 						leaf.Func.Name, itemInFunc)
 				}
-				if len(f.Attr) == 0 {
-					f.Attr = itemInFunc.Val
-				} else {
-					f.Args = append(f.Args, itemInFunc.Val)
-				}
+				f.addArg(itemInFunc.Val)
 			}
 			if !terminated {
 				return nil, x.Errorf("Expected ) to terminate func definition")
 			}
+			if f.Name == "math" {
+				// The only argument is the expression, not an attribute.
+				expr, err := parseMath(f.Attr)
+				if err != nil {
+					return nil, err
+				}
+				if len(f.Args) > 0 {
+					return nil, x.Errorf("Function math takes only one expression")
+				}
+				f.MathExpr, f.Attr = expr, ""
+			}
+			if f.Name == "uid_in" {
+				if err := f.parseUidIn(); err != nil {
+					return nil, err
+				}
+			}
 			valueStack.push(leaf)
 
 		} else if item.Typ == itemLeftRound { // Just push to op stack.
@@ -768,7 +1070,9 @@ func parseFilter(l *lex.Lexer) (*FilterTree, error) {
 				if topOp.Op == "(" {
 					break
 				}
-				evalStack(opStack, valueStack)
+				if err := evalStack(opStack, valueStack); err != nil {
+					return nil, err
+				}
 			}
 			opStack.pop() // Pop away the (.
 			if opStack.empty() {
@@ -789,9 +1093,22 @@ func parseFilter(l *lex.Lexer) (*FilterTree, error) {
 				if filterOpPrecedence[topOp.Op] < opPred {
 					break
 				}
-				evalStack(opStack, valueStack)
+				if err := evalStack(opStack, valueStack); err != nil {
+					return nil, err
+				}
 			}
 			opStack.push(&FilterTree{Op: op}) // Push current operator.
+
+		} else if item.Typ == itemFilterNot {
+			// Not applies to the filter right after it, so nothing is evaluated
+			// yet.
+			opStack.push(&FilterTree{Op: "!"})
+
+		} else if item.Typ == itemArgument {
+			return nil, x.Errorf("Arguments can only follow the function at the root")
+
+		} else if item.Typ == lex.ItemError {
+			return nil, x.Errorf("%s", item.Val)
 		}
 	}
 
@@ -821,31 +1138,63 @@ func getRoot(l *lex.Lexer) (gq *GraphQuery, rerr error) {
 		Args: make(map[string]string),
 	}
 	item := <-l.Items
+	if item.Typ == itemRightCurl {
+		// No more query blocks.
+		return nil, nil
+	}
 	if item.Typ != itemName {
 		return nil, x.Errorf("Expected some name. Got: %v", item)
 	}
 
 	gq.Alias = item.Val
 	item = <-l.Items
+	if item.Typ == itemName && item.Val == "as" {
+		// The UIDs of this block are stored in a variable, like f as me(...).
+		gq.Var = gq.Alias
+		item = <-l.Items
+		if item.Typ != itemName {
+			return nil, x.Errorf("Expected some name after as. Got: %v", item)
+		}
+		gq.Alias = item.Val
+		item = <-l.Items
+	}
 	if item.Typ != itemLeftRound {
 		return nil, x.Errorf("Expected variable start. Got: %v", item)
 	}
 
 	item = <-l.Items
-	if item.Typ == itemGenerator {
+	if gq.Alias == shortestPathBlock {
+		if item.Typ != itemArgument {
+			return nil, x.Errorf("Expecting from and to in shortest path query")
+		}
+		args, err := parseArguments(l)
+		if err != nil {
+			return nil, err
+		}
+		if gq.ShortestPath, err = parseShortestPath(args); err != nil {
+			return nil, err
+		}
+	} else if item.Typ == itemGenerator {
 		// Store the generator function.
-		gen, err := parseFunction(l)
+		gen, args, err := parseFunction(l)
 		if err != nil {
 			return nil, err
 		}
 		gq.Func = gen
+		// The results of the function can be sorted and paginated, like
+		// me(anyof(name, "Rick"), first: 10, orderasc: name).
+		if err := addQueryArgs(gq, args); err != nil {
+			return nil, err
+		}
 	} else if item.Typ == itemArgument {
 		args, err := parseArguments(l)
 		if err != nil {
 			return nil, err
 		}
 		for _, p := range args {
-			if p.Key == "_uid_" {
+			if name, ok := parseVarRef(p.Val); ok && (p.Key == "_uid_" || p.Key == "id") {
+				gq.NeedsVar = name
+			} else if p.Key == "_uid_" || p.Key == "id" {
 				gq.UID, rerr = strconv.ParseUint(p.Val, 0, 64)
 				if rerr != nil {
 					return nil, rerr
@@ -853,7 +1202,7 @@ func getRoot(l *lex.Lexer) (gq *GraphQuery, rerr error) {
 			} else if p.Key == "_xid_" {
 				gq.XID = p.Val
 			} else {
-				return nil, x.Errorf("Expecting _uid_ or _xid_. Got: %+v", p)
+				return nil, x.Errorf("Expecting _uid_, id or _xid_. Got: %+v", p)
 			}
 		}
 	} else {
@@ -866,9 +1215,25 @@ func getRoot(l *lex.Lexer) (gq *GraphQuery, rerr error) {
 // godeep constructs the subgraph from the lexed items and a GraphQuery node.
 func godeep(l *lex.Lexer, gq *GraphQuery) error {
 	curp := gq // Used to track current node, for nesting.
+	var varName string
+	// Set right after @facets, whose arguments are optional.
+	var facets bool
 	for item := range l.Items {
+		if facets {
+			facets = false
+			if item.Typ == itemLeftRound {
+				if err := parseFacets(l, curp); err != nil {
+					return err
+				}
+				continue
+			}
+			if curp.Facets == nil {
+				curp.Facets = new(Facets)
+			}
+		}
+
 		if item.Typ == lex.ItemError {
-			return x.Errorf(item.Val)
+			return x.Errorf("%s", item.Val)
 		}
 
 		if item.Typ == lex.ItemEOF {
@@ -889,50 +1254,255 @@ func godeep(l *lex.Lexer, gq *GraphQuery) error {
 			// Unlike itemName, there is no nesting, so do not change "curp".
 
 		} else if item.Typ == itemName {
+			if item.Val == "as" && curp != gq && len(curp.Var) == 0 &&
+				curp.Children == nil && len(curp.Args) == 0 && curp.Func == nil {
+				// The previous name is a variable for the predicate which
+				// follows, like f as friend.
+				varName = curp.Attr
+				gq.Children = gq.Children[:len(gq.Children)-1]
+				curp = gq
+				continue
+			}
 			child := &GraphQuery{
 				Args: make(map[string]string),
 				Attr: item.Val,
+				Var:  varName,
 			}
+			varName = ""
 			gq.Children = append(gq.Children, child)
 			curp = child
 
 		} else if item.Typ == itemAlias {
 			curp.Alias = curp.Attr
 			curp.Attr = item.Val
+		} else if item.Typ == itemMathExpr {
+			if curp == gq || curp.Attr != "math" || len(curp.Alias) == 0 {
+				return x.Errorf("math() needs an alias, like score: math(%s)", item.Val)
+			}
+			expr, err := parseMath(item.Val)
+			if err != nil {
+				return err
+			}
+			curp.MathExpr = expr
 		} else if item.Typ == itemLeftCurl {
 			if err := godeep(l, curp); err != nil {
 				return err
 			}
 
-		} else if item.Typ == itemLeftRound {
-			item = <-l.Items
-			if item.Typ == itemArgument {
-				args, err := parseArguments(l)
-				if err != nil {
-					return err
+		} else if item.Typ == itemLanguage {
+			if curp == gq || len(curp.Langs) > 0 {
+				return x.Errorf("Unexpected languages: %s", item.Val)
+			}
+			curp.Langs = strings.Split(item.Val, ":")
+			for _, lang := range curp.Langs {
+				if len(lang) == 0 {
+					return x.Errorf("Empty language in: %s@%s", curp.Attr, item.Val)
 				}
-				// Stores args in GraphQuery, will be used later while retrieving results.
-				for _, p := range args {
-					if p.Val == "" {
-						return x.Errorf("Got empty argument")
-					}
+			}
 
-					curp.Args[p.Key] = p.Val
+		} else if item.Typ == itemLeftRound {
+			if (isAggregator(curp.Attr) || curp.Attr == "expand") && curp.Func == nil {
+				if err := parseAggregator(l, curp); err != nil {
+					return err
 				}
+			} else if err := parseQueryArgs(l, curp); err != nil {
+				return err
+			}
+		} else if item.Typ == itemDirectiveName && item.Val == "@facets" {
+			facets = true
+		} else if item.Typ == itemDirectiveName && item.Val == "@groupby" {
+			if err := parseGroupBy(l, curp); err != nil {
+				return err
 			}
+		} else if item.Typ == itemDirectiveName && item.Val == "@normalize" {
+			return x.Errorf("@normalize can only be used on query blocks")
 		} else if item.Typ == itemDirectiveName {
-			switch item.Val {
-			case "@filter":
-				filter, err := parseFilter(l)
-				if err != nil {
-					return err
-				}
-				curp.Filter = filter
+			if err := parseDirective(l, curp, item.Val); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
 
-			default:
-				return x.Errorf("Unknown directive [%s]", item.Val)
+// parseFacets parses the arguments of @facets after the (. These are either a
+// filter on the facets, or the facets to return along with the order of the
+// edges, like @facets(since, orderasc: since).
+func parseFacets(l *lex.Lexer, gq *GraphQuery) error {
+	item := <-l.Items
+	if item.Typ == itemFacetsFilter {
+		if gq.FacetsFilter != nil {
+			return x.Errorf("Only one filter is allowed on the facets of %s", gq.Attr)
+		}
+		filter, err := parseFilterInside(l)
+		if err != nil {
+			return err
+		}
+		gq.FacetsFilter = filter
+		return nil
+	}
+	if item.Typ != itemArgument {
+		return x.Errorf("Expecting arguments for @facets. Got: %v", item)
+	}
+	if gq.Facets == nil {
+		gq.Facets = new(Facets)
+	}
+	f := gq.Facets
+	var name string
+	for item := range l.Items {
+		switch item.Typ {
+		case itemArgName:
+			if len(name) > 0 {
+				f.Keys = append(f.Keys, name)
+			}
+			name = item.Val
+		case itemArgVal:
+			if name != "orderasc" && name != "orderdesc" {
+				return x.Errorf("Unknown argument for @facets: %s", name)
+			}
+			if len(f.OrderKey) > 0 {
+				return x.Errorf("Edges can be sorted by only one facet")
 			}
+			f.OrderKey = item.Val
+			f.OrderDesc = name == "orderdesc"
+			name = ""
+		case itemRightRound:
+			if len(name) > 0 {
+				f.Keys = append(f.Keys, name)
+			}
+			sort.Strings(f.Keys)
+			return nil
+		default:
+			return x.Errorf("Unexpected item in @facets: %v", item)
+		}
+	}
+	return x.Errorf("Unclosed @facets")
+}
+
+// parseGroupBy parses the predicate given with @groupby, like @groupby(genre).
+func parseGroupBy(l *lex.Lexer, gq *GraphQuery) error {
+	if len(gq.GroupBy) > 0 {
+		return x.Errorf("Only one @groupby is allowed on %s", gq.Attr)
+	}
+	for _, typ := range []lex.ItemType{itemLeftRound, itemArgument} {
+		if item := <-l.Items; item.Typ != typ {
+			return x.Errorf("Expecting predicate for @groupby. Got: %v", item)
+		}
+	}
+	item := <-l.Items
+	if item.Typ != itemArgName {
+		return x.Errorf("Expecting predicate for @groupby. Got: %v", item)
+	}
+	gq.GroupBy = item.Val
+	item = <-l.Items
+	if item.Typ != itemRightRound {
+		return x.Errorf("Expecting ) after predicate for @groupby. Got: %v", item)
+	}
+	return nil
+}
+
+// parseVarRef returns the variable name if val refers to a UID variable, like
+// var(f).
+func parseVarRef(val string) (string, bool) {
+	if !strings.HasPrefix(val, "var(") || !strings.HasSuffix(val, ")") {
+		return "", false
+	}
+	name := strings.TrimSpace(val[len("var(") : len(val)-1])
+	return name, len(name) > 0
+}
+
+// parseQueryArgs parses the argument list following a (, and stores the
+// arguments in gq.Args. Arguments of directives like @recurse(depth: 5) are
+// stored the same way. The sort orders are kept in gq.Order instead.
+func parseQueryArgs(l *lex.Lexer, gq *GraphQuery) error {
+	item := <-l.Items
+	if item.Typ != itemArgument {
+		return nil
+	}
+	args, err := parseArguments(l)
+	if err != nil {
+		return err
+	}
+	return addQueryArgs(gq, args)
+}
+
+// addQueryArgs stores args in gq.Args, to be used later while retrieving
+// results. The sort orders are kept in gq.Order instead.
+func addQueryArgs(gq *GraphQuery, args []pair) error {
+	for _, p := range args {
+		if p.Val == "" {
+			return x.Errorf("Got empty argument")
 		}
+		switch p.Key {
+		case "order", "orderasc", "orderdesc":
+			gq.Order = append(gq.Order, &Order{Attr: p.Val, Desc: p.Key == "orderdesc"})
+		default:
+			gq.Args[p.Key] = p.Val
+		}
+	}
+	return nil
+}
+
+// isAggregator returns true if name is one of the aggregation functions, which
+// can be used inside a query block like min(age). It is also true for count,
+// as in count(friend) for the number of edges.
+func isAggregator(name string) bool {
+	switch name {
+	case "min", "max", "sum", "avg", "count":
+		return true
+	}
+	return false
+}
+
+// parseAggregator parses the predicate of an aggregation function like
+// min(age), or of expand(_all_). gq.Func is set to the function, and gq.Attr
+// to the predicate it is applied to.
+func parseAggregator(l *lex.Lexer, gq *GraphQuery) error {
+	item := <-l.Items
+	if item.Typ != itemArgument {
+		return x.Errorf("Expecting predicate for %s. Got: %v", gq.Attr, item)
+	}
+	item = <-l.Items
+	if item.Typ != itemArgName {
+		return x.Errorf("Expecting predicate for %s. Got: %v", gq.Attr, item)
+	}
+	gq.Func = &Function{Name: gq.Attr, Attr: item.Val}
+	gq.Attr = item.Val
+	item = <-l.Items
+	if item.Typ != itemRightRound {
+		return x.Errorf("Expecting ) after predicate for %s. Got: %v",
+			gq.Func.Name, item)
+	}
+	return nil
+}
+
+// parseDirective handles the directive with the given name for gq.
+func parseDirective(l *lex.Lexer, gq *GraphQuery, name string) error {
+	switch name {
+	case "@filter":
+		filter, err := parseFilter(l)
+		if err != nil {
+			return err
+		}
+		gq.Filter = filter
+
+	case "@recurse":
+		gq.Recurse = true
+
+	case "@cascade":
+		gq.Cascade = true
+
+	case "@normalize":
+		gq.Normalize = true
+
+	case "@facets":
+		return x.Errorf("@facets can only be used on predicates")
+
+	case "@groupby":
+		return x.Errorf("@groupby can only be used on predicates")
+
+	default:
+		return x.Errorf("Unknown directive [%s]", name)
 	}
 	return nil
 }
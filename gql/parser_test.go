@@ -43,8 +43,9 @@ func TestParse(t *testing.T) {
 		}
 	}
 `
-	gq, _, err := Parse(query)
+	res, err := Parse(query)
 	require.NoError(t, err)
+	gq := res.Query[0]
 	require.NotNil(t, gq)
 	require.Equal(t, childAttrs(gq), []string{"friends", "gender", "age", "hometown"})
 	require.Equal(t, childAttrs(gq.Children[0]), []string{"name"})
@@ -61,7 +62,7 @@ func TestParseError(t *testing.T) {
 		}
 	}
 `
-	_, _, err := Parse(query)
+	_, err := Parse(query)
 	require.Error(t, err)
 }
 
@@ -74,8 +75,9 @@ func TestParseXid(t *testing.T) {
 			type.object.name
 		}
 	}`
-	gq, _, err := Parse(query)
+	res, err := Parse(query)
 	require.NoError(t, err)
+	gq := res.Query[0]
 	require.NotNil(t, gq)
 	require.Equal(t, childAttrs(gq), []string{"type.object.name"})
 }
@@ -89,8 +91,9 @@ func TestParseFirst(t *testing.T) {
 			}
 		}
 	}`
-	gq, _, err := Parse(query)
+	res, err := Parse(query)
 	require.NoError(t, err)
+	gq := res.Query[0]
 	require.NotNil(t, gq)
 	require.Equal(t, childAttrs(gq), []string{"type.object.name", "friends"})
 	require.Equal(t, gq.Children[1].Args["first"], "10")
@@ -105,7 +108,7 @@ func TestParseFirst_error(t *testing.T) {
 			}
 		}
 	}`
-	_, _, err := Parse(query)
+	_, err := Parse(query)
 	require.Error(t, err)
 }
 
@@ -118,8 +121,9 @@ func TestParseAfter(t *testing.T) {
 			}
 		}
 	}`
-	gq, _, err := Parse(query)
+	res, err := Parse(query)
 	require.NoError(t, err)
+	gq := res.Query[0]
 	require.NotNil(t, gq)
 	require.Equal(t, childAttrs(gq), []string{"type.object.name", "friends"})
 	require.Equal(t, gq.Children[1].Args["first"], "10")
@@ -135,8 +139,9 @@ func TestParseOffset(t *testing.T) {
 			}
 		}
 	}`
-	gq, _, err := Parse(query)
+	res, err := Parse(query)
 	require.NoError(t, err)
+	gq := res.Query[0]
 	require.NotNil(t, gq)
 	require.Equal(t, childAttrs(gq), []string{"type.object.name", "friends"})
 	require.Equal(t, gq.Children[1].Args["first"], "10")
@@ -152,7 +157,7 @@ func TestParseOffset_error(t *testing.T) {
 			}
 		}
 	}`
-	_, _, err := Parse(query)
+	_, err := Parse(query)
 	require.Error(t, err)
 }
 
@@ -164,7 +169,7 @@ func TestParse_error2(t *testing.T) {
 			}
 		}
 	`
-	_, _, err := Parse(query)
+	_, err := Parse(query)
 	require.Error(t, err)
 }
 
@@ -178,8 +183,9 @@ func TestParse_pass1(t *testing.T) {
 			}
 		}
 	`
-	gq, _, err := Parse(query)
+	res, err := Parse(query)
 	require.NoError(t, err)
+	gq := res.Query[0]
 	require.NotNil(t, gq)
 	require.Equal(t, childAttrs(gq), []string{"name", "friends"})
 	require.Empty(t, childAttrs(gq.Children[1]))
@@ -196,8 +202,9 @@ func TestParse_alias(t *testing.T) {
 			}
 		}
 	`
-	gq, _, err := Parse(query)
+	res, err := Parse(query)
 	require.NoError(t, err)
+	gq := res.Query[0]
 	require.NotNil(t, gq)
 	require.Equal(t, childAttrs(gq), []string{"name", "friends"})
 	require.Equal(t, gq.Children[1].Alias, "bestFriend")
@@ -212,8 +219,9 @@ func TestParse_block(t *testing.T) {
 			}
 		}
 	`
-	gq, _, err := Parse(query)
+	res, err := Parse(query)
 	require.NoError(t, err)
+	gq := res.Query[0]
 	require.NotNil(t, gq)
 	require.Equal(t, childAttrs(gq), []string{"type.object.name.es-419"})
 }
@@ -230,8 +238,9 @@ func TestParseMutation(t *testing.T) {
 			}
 		}
 	`
-	_, mu, err := Parse(query)
+	res, err := Parse(query)
 	require.NoError(t, err)
+	mu := res.Mutation
 	require.NotEqual(t, strings.Index(mu.Set, "<name> <is> <something> ."), -1)
 	require.NotEqual(t, strings.Index(mu.Set, "<hometown> <is> <san francisco> ."), -1)
 	require.NotEqual(t, strings.Index(mu.Del, "<name> <is> <something-else> ."), -1)
@@ -248,7 +257,7 @@ func TestParseMutation_error(t *testing.T) {
 				<name> <is> <something-else> .
 		}
 	`
-	_, _, err := Parse(query)
+	_, err := Parse(query)
 	require.Error(t, err)
 }
 
@@ -270,7 +279,7 @@ func TestParseMutation_error2(t *testing.T) {
 		}
 
 	`
-	_, _, err := Parse(query)
+	_, err := Parse(query)
 	require.Error(t, err)
 }
 
@@ -292,13 +301,15 @@ func TestParseMutationAndQuery(t *testing.T) {
 			}
 		}
 	`
-	gq, mu, err := Parse(query)
+	res, err := Parse(query)
 	require.NoError(t, err)
+	mu := res.Mutation
 	require.NotNil(t, mu)
 	require.NotEqual(t, strings.Index(mu.Set, "<name> <is> <something> ."), -1)
 	require.NotEqual(t, strings.Index(mu.Set, "<hometown> <is> <san francisco> ."), -1)
 	require.NotEqual(t, strings.Index(mu.Del, "<name> <is> <something-else> ."), -1)
 
+	gq := res.Query[0]
 	require.NotNil(t, gq)
 	require.Equal(t, gq.XID, "tomhanks")
 	require.Equal(t, childAttrs(gq), []string{"name", "hometown"})
@@ -334,8 +345,9 @@ func TestParseFragmentNoNesting(t *testing.T) {
 		id
 	}
 `
-	gq, _, err := Parse(query)
+	res, err := Parse(query)
 	require.NoError(t, err)
+	gq := res.Query[0]
 	require.NotNil(t, gq)
 	require.Equal(t, childAttrs(gq), []string{"name", "id", "friends", "name", "hobbies", "id"})
 }
@@ -360,8 +372,9 @@ func TestParseFragmentNest1(t *testing.T) {
 		hobbies
 	}
 `
-	gq, _, err := Parse(query)
+	res, err := Parse(query)
 	require.NoError(t, err)
+	gq := res.Query[0]
 	require.NotNil(t, gq)
 	require.Equal(t, childAttrs(gq), []string{"id", "hobbies", "friends"})
 }
@@ -383,8 +396,9 @@ func TestParseFragmentNest2(t *testing.T) {
 		nickname
 	}
 `
-	gq, _, err := Parse(query)
+	res, err := Parse(query)
 	require.NoError(t, err)
+	gq := res.Query[0]
 	require.NotNil(t, gq)
 	require.Equal(t, childAttrs(gq), []string{"friends"})
 	require.Equal(t, childAttrs(gq.Children[0]), []string{"name", "nickname"})
@@ -409,7 +423,7 @@ func TestParseFragmentCycle(t *testing.T) {
 		...fragmenta
 	}
 `
-	_, _, err := Parse(query)
+	_, err := Parse(query)
 	require.Error(t, err, "Expected error with cycle")
 }
 
@@ -428,7 +442,7 @@ func TestParseFragmentMissing(t *testing.T) {
 		...fragmenta
 	}
 `
-	_, _, err := Parse(query)
+	_, err := Parse(query)
 	require.Error(t, err, "Expected error with missing fragment")
 }
 
@@ -437,7 +451,7 @@ func TestParseVariables(t *testing.T) {
 		"query": "query testQuery( $a  : int   , $b: int){root(_uid_: 0x0a) {name(first: $b, after: $a){english}}}", 
 		"variables": {"$a": "6", "$b": "5" } 
 	}`
-	_, _, err := Parse(query)
+	_, err := Parse(query)
 	require.NoError(t, err)
 }
 
@@ -446,7 +460,7 @@ func TestParseVariables1(t *testing.T) {
 		"query": "query testQuery($a: int , $b: int!){root(_uid_: 0x0a) {name(first: $b){english}}}", 
 		"variables": {"$b": "5" } 
 	}`
-	_, _, err := Parse(query)
+	_, err := Parse(query)
 	require.NoError(t, err)
 }
 
@@ -455,7 +469,7 @@ func TestParseVariables2(t *testing.T) {
 		"query": "query testQuery($a: float , $b: bool!){root(_uid_: 0x0a) {name{english}}}", 
 		"variables": {"$b": "false", "$a": "3.33" } 
 	}`
-	_, _, err := Parse(query)
+	_, err := Parse(query)
 	require.NoError(t, err)
 }
 
@@ -464,7 +478,7 @@ func TestParseVariables3(t *testing.T) {
 		"query": "query testQuery($a: int , $b: int! ){root(_uid_: 0x0a) {name(first: $b){english}}}", 
 		"variables": {"$a": "5", "$b": "3"} 
 	}`
-	_, _, err := Parse(query)
+	_, err := Parse(query)
 	require.NoError(t, err)
 }
 
@@ -473,7 +487,7 @@ func TestParseVariablesStringfiedJSON(t *testing.T) {
 		"query": "query testQuery($a: int! , $b: int){root(_uid_: 0x0a) {name(first: $b){english}}}", 
 		"variables": "{\"$a\": \"5\" }" 
 	}`
-	_, _, err := Parse(query)
+	_, err := Parse(query)
 	require.NoError(t, err)
 }
 
@@ -482,7 +496,7 @@ func TestParseVariablesDefault1(t *testing.T) {
 		"query": "query testQuery($a: int = 3  , $b: int =  4 ,  $c : int = 3){root(_uid_: 0x0a) {name(first: $b, after: $a, offset: $c){english}}}", 
 		"variables": {"$b": "5" } 
 	}`
-	_, _, err := Parse(query)
+	_, err := Parse(query)
 	require.NoError(t, err)
 }
 func TestParseVariablesFragments(t *testing.T) {
@@ -490,8 +504,9 @@ func TestParseVariablesFragments(t *testing.T) {
 	"query": "query test($a: int){user(_uid_:0x0a) {...fragmentd,friends(first: $a, offset: $a) {name}}} fragment fragmentd {id(first: $a)}",
 	"variables": {"$a": "5"}
 }`
-	gq, _, err := Parse(query)
+	res, err := Parse(query)
 	require.NoError(t, err)
+	gq := res.Query[0]
 	require.NotNil(t, gq)
 	require.Equal(t, childAttrs(gq), []string{"id", "friends"})
 	require.Empty(t, childAttrs(gq.Children[0]))
@@ -507,7 +522,7 @@ func TestParseVariablesError1(t *testing.T) {
 			}
 		}
 	`
-	_, _, err := Parse(query)
+	_, err := Parse(query)
 	require.Error(t, err)
 }
 
@@ -518,7 +533,7 @@ func TestParseVariablesError2(t *testing.T) {
 		}", 
 		"variables": {"$a": "6", "$b": "5" } 
 	}`
-	_, _, err := Parse(query)
+	_, err := Parse(query)
 	require.Error(t, err, "Expected value for variable $c")
 }
 
@@ -529,7 +544,7 @@ func TestParseVariablesError3(t *testing.T) {
 		}", 
 		"variables": {"$a": "6", "$b": "5" } 
 	}`
-	_, _, err := Parse(query)
+	_, err := Parse(query)
 	require.Error(t, err, "Expected type for variable $b")
 }
 
@@ -538,7 +553,7 @@ func TestParseVariablesError4(t *testing.T) {
 		"query": "query testQuery($a: bool , $b: float! = 3){root(_uid_: 0x0a) {name(first: $b){english}}}", 
 		"variables": {"$a": "5" } 
 	}`
-	_, _, err := Parse(query)
+	_, err := Parse(query)
 	require.Error(t, err, "Expected type error")
 }
 
@@ -547,7 +562,7 @@ func TestParseVariablesError5(t *testing.T) {
 		"query": "query ($a: int, $b: int){root(_uid_: 0x0a) {name(first: $b, after: $a){english}}}", 
 		"variables": {"$a": "6", "$b": "5" } 
 	}`
-	_, _, err := Parse(query)
+	_, err := Parse(query)
 	require.Error(t, err, "Expected error: Query with variables should be named")
 }
 
@@ -556,7 +571,7 @@ func TestParseVariablesError6(t *testing.T) {
 		"query": "query ($a: int, $b: random){root(_uid_: 0x0a) {name(first: $b, after: $a){english}}}", 
 		"variables": {"$a": "6", "$b": "5" } 
 	}`
-	_, _, err := Parse(query)
+	_, err := Parse(query)
 	require.Error(t, err, "Expected error: Type random not supported")
 }
 
@@ -567,7 +582,7 @@ func TestParseVariablesError7(t *testing.T) {
 		}", 
 		"variables": {"$a": "6", "$b": "5", "$d": "abc" } 
 	}`
-	_, _, err := Parse(query)
+	_, err := Parse(query)
 	require.Error(t, err, "Expected type for variable $d")
 }
 
@@ -576,7 +591,7 @@ func TestParseVariablesiError8(t *testing.T) {
 		"query": "query testQuery($a: int = 3  , $b: int! =  4 ,  $c : int = 3){root(_uid_: 0x0a) {name(first: $b, after: $a, offset: $c){english}}}", 
 		"variables": {"$b": "5" } 
 	}`
-	_, _, err := Parse(query)
+	_, err := Parse(query)
 	require.Error(t, err, "Variables type ending with ! cant have default value")
 }
 
@@ -592,8 +607,9 @@ func TestParseFilter_simplest(t *testing.T) {
 		}
 	}
 `
-	gq, _, err := Parse(query)
+	res, err := Parse(query)
 	require.NoError(t, err)
+	gq := res.Query[0]
 	require.NotNil(t, gq)
 	require.Equal(t, childAttrs(gq), []string{"friends", "gender", "age", "hometown"})
 	require.Equal(t, childAttrs(gq.Children[0]), []string{"name"})
@@ -617,8 +633,9 @@ func TestParseFilter_op(t *testing.T) {
 		}
 	}
 `
-	gq, _, err := Parse(query)
+	res, err := Parse(query)
 	require.NoError(t, err)
+	gq := res.Query[0]
 	require.NotNil(t, gq)
 	require.Equal(t, childAttrs(gq), []string{"friends", "gender", "age", "hometown"})
 	require.Equal(t, childAttrs(gq.Children[0]), []string{"name"})
@@ -639,8 +656,9 @@ func TestParseFilter_op2(t *testing.T) {
 		}
 	}
 `
-	gq, _, err := Parse(query)
+	res, err := Parse(query)
 	require.NoError(t, err)
+	gq := res.Query[0]
 	require.NotNil(t, gq)
 	require.Equal(t, childAttrs(gq), []string{"friends", "gender", "age", "hometown"})
 	require.Equal(t, childAttrs(gq.Children[0]), []string{"name"})
@@ -660,8 +678,9 @@ func TestParseFilter_brac(t *testing.T) {
 		}
 	}
 `
-	gq, _, err := Parse(query)
+	res, err := Parse(query)
 	require.NoError(t, err)
+	gq := res.Query[0]
 	require.NotNil(t, gq)
 	require.Equal(t, childAttrs(gq), []string{"friends", "gender", "age", "hometown"})
 	require.Equal(t, childAttrs(gq.Children[0]), []string{"name"})
@@ -669,6 +688,29 @@ func TestParseFilter_brac(t *testing.T) {
 		`(OR (a "hello") (AND (AND (b "world" "is") (OR (c) (OR (d "haha") (e)))) (f)))`)
 }
 
+func TestParseFilter_not(t *testing.T) {
+	for filter, expected := range map[string]string{
+		`not a("x")`:                          `(NOT (a "x"))`,
+		`not(a("x"))`:                         `(NOT (a "x"))`,
+		`not a("x") && b()`:                   `(AND (NOT (a "x")) (b))`,
+		`a() || not b() && c()`:               `(OR (a) (AND (NOT (b)) (c)))`,
+		`not (a() || b()) && c()`:             `(AND (NOT (OR (a) (b))) (c))`,
+		`not not a()`:                         `(NOT (NOT (a)))`,
+		`nothing("x") && not anyof("n", "y")`: `(AND (nothing "x") (NOT (anyof "n" "y")))`,
+	} {
+		res, err := Parse(`{ me(_uid_:0x0a) { friends @filter(` + filter + `) { name } } }`)
+		require.NoError(t, err, filter)
+		require.Equal(t, expected, res.Query[0].Children[0].Filter.debugString(), filter)
+	}
+}
+
+func TestParseFilter_notError(t *testing.T) {
+	for _, filter := range []string{`not`, `a() && not`, `not && a()`, `a() not b()`} {
+		_, err := Parse(`{ me(_uid_:0x0a) { friends @filter(` + filter + `) { name } } }`)
+		require.Error(t, err, filter)
+	}
+}
+
 // Test if unbalanced brac will lead to errors.
 func TestParseFilter_unbalancedbrac(t *testing.T) {
 	query := `
@@ -682,7 +724,7 @@ func TestParseFilter_unbalancedbrac(t *testing.T) {
 		}
 	}
 `
-	_, _, err := Parse(query)
+	_, err := Parse(query)
 	require.Error(t, err)
 }
 
@@ -698,7 +740,39 @@ func TestParseFilter_unknowndirective(t *testing.T) {
 		}
 	}
 `
-	_, _, err := Parse(query)
+	_, err := Parse(query)
+	require.Error(t, err)
+}
+
+func TestParseFilter_regex(t *testing.T) {
+	query := `
+	query {
+		me(_uid_:0x0a) {
+			friends @filter(regexp("name", /^Ste(v|ph)en\/?$/i) && prefix("name", "Ste")) {
+				name
+			}
+		}
+	}
+`
+	res, err := Parse(query)
+	require.NoError(t, err)
+	gq := res.Query[0]
+	require.NotNil(t, gq)
+	require.Equal(t, `(AND (regexp "name" "/^Ste(v|ph)en\/?$/i") (prefix "name" "Ste"))`,
+		gq.Children[0].Filter.debugString())
+}
+
+func TestParseFilter_unclosedRegex(t *testing.T) {
+	query := `
+	query {
+		me(_uid_:0x0a) {
+			friends @filter(regexp("name", /^Ste)) {
+				name
+			}
+		}
+	}
+`
+	_, err := Parse(query)
 	require.Error(t, err)
 }
 
@@ -714,7 +788,7 @@ func TestParseGenerator(t *testing.T) {
 		}
 	}
 `
-	_, _, err := Parse(query)
+	_, err := Parse(query)
 	require.NoError(t, err)
 }
 
@@ -729,6 +803,647 @@ func TestParseGeoJson(t *testing.T) {
 		}
 	}
 	`
-	_, _, err := Parse(query)
+	_, err := Parse(query)
+	require.NoError(t, err)
+}
+
+func TestParseRecurse(t *testing.T) {
+	query := `
+	{
+		me(_uid_:0x0a) @recurse(depth: 3) {
+			friends
+			name
+		}
+	}
+`
+	res, err := Parse(query)
+	require.NoError(t, err)
+	gq := res.Query[0]
+	require.NotNil(t, gq)
+	require.True(t, gq.Recurse)
+	require.Equal(t, "3", gq.Args["depth"])
+	require.Equal(t, childAttrs(gq), []string{"friends", "name"})
+}
+
+func TestParseRecurseChild(t *testing.T) {
+	query := `
+	{
+		me(_uid_:0x0a) {
+			friends @recurse {
+				friends
+				name
+			}
+			gender
+		}
+	}
+`
+	res, err := Parse(query)
+	require.NoError(t, err)
+	gq := res.Query[0]
+	require.NotNil(t, gq)
+	require.False(t, gq.Recurse)
+	require.True(t, gq.Children[0].Recurse)
+	require.Equal(t, childAttrs(gq), []string{"friends", "gender"})
+	require.Equal(t, childAttrs(gq.Children[0]), []string{"friends", "name"})
+}
+
+func TestParseCascade(t *testing.T) {
+	query := `
+	{
+		me(_uid_:0x0a) @cascade {
+			friends @cascade {
+				name
+			}
+			gender
+		}
+	}
+`
+	res, err := Parse(query)
+	require.NoError(t, err)
+	gq := res.Query[0]
+	require.NotNil(t, gq)
+	require.True(t, gq.Cascade)
+	require.True(t, gq.Children[0].Cascade)
+	require.False(t, gq.Children[1].Cascade)
+	require.Equal(t, childAttrs(gq), []string{"friends", "gender"})
+}
+
+func TestParseNormalize(t *testing.T) {
+	query := `
+	{
+		me(_uid_:0x0a) @normalize {
+			friends {
+				n: name
+			}
+		}
+	}
+`
+	res, err := Parse(query)
+	require.NoError(t, err)
+	gq := res.Query[0]
+	require.True(t, gq.Normalize)
+	require.Equal(t, "n", gq.Children[0].Children[0].Alias)
+}
+
+func TestParseNormalizeChild(t *testing.T) {
+	query := `
+	{
+		me(_uid_:0x0a) {
+			friends @normalize {
+				n: name
+			}
+		}
+	}
+`
+	_, err := Parse(query)
+	require.Error(t, err)
+}
+
+func TestParseGroupBy(t *testing.T) {
+	query := `
+	{
+		me(_uid_:0x0a) {
+			friends @groupby(age) {
+				avg(rating)
+			}
+		}
+	}
+`
+	res, err := Parse(query)
+	require.NoError(t, err)
+	friends := res.Query[0].Children[0]
+	require.Equal(t, "age", friends.GroupBy)
+	require.Len(t, friends.Children, 1)
+	require.Equal(t, "avg", friends.Children[0].Func.Name)
+}
+
+func TestParseGroupByError(t *testing.T) {
+	for _, query := range []string{
+		`{ me(_uid_:0x0a) @groupby(age) { name } }`,
+		`{ me(_uid_:0x0a) { friends @groupby { count } } }`,
+		`{ me(_uid_:0x0a) { friends @groupby(age, name) { count } } }`,
+		`{ me(_uid_:0x0a) { friends @groupby(age) @groupby(name) { count } } }`,
+	} {
+		_, err := Parse(query)
+		require.Error(t, err, query)
+	}
+}
+
+func TestParseExpand(t *testing.T) {
+	query := `
+	{
+		me(_uid_:0x0a) {
+			name
+			expand(_all_)
+		}
+	}
+`
+	res, err := Parse(query)
+	require.NoError(t, err)
+	gq := res.Query[0]
+	require.Equal(t, childAttrs(gq), []string{"name", "_all_"})
+	require.Equal(t, "expand", gq.Children[1].Func.Name)
+}
+
+func TestParseCountFilter(t *testing.T) {
+	query := `
+	{
+		me(_uid_:0x0a) {
+			friends @filter(gt(count(friends), 10) && anyof("name", "Rick")) {
+				name
+			}
+		}
+	}
+`
+	res, err := Parse(query)
+	require.NoError(t, err)
+	ft := res.Query[0].Children[0].Filter
+	require.Equal(t, `(AND (gt "count(friends)" "10") (anyof "name" "Rick"))`,
+		ft.debugString())
+	f := ft.Child[0].Func
+	require.True(t, f.IsCount)
+	require.Equal(t, "friends", f.Attr)
+	require.Equal(t, []string{"10"}, f.Args)
+	require.False(t, ft.Child[1].Func.IsCount)
+}
+
+func TestParseCountFilterError(t *testing.T) {
+	for _, query := range []string{
+		`{ me(_uid_:0x0a) { friends @filter(gt(count(friends`,
+		`{ me(_uid_:0x0a) { friends @filter(gt(count(friends), 1+0)) { name } } }`,
+	} {
+		_, err := Parse(query)
+		require.Error(t, err, query)
+	}
+}
+
+func TestParseHas(t *testing.T) {
+	query := `
+	{
+		me(has(email)) {
+			friends @filter(has(email)) {
+				name
+			}
+		}
+	}
+`
+	res, err := Parse(query)
+	require.NoError(t, err)
+	gq := res.Query[0]
+	require.Equal(t, &Function{Name: "has", Attr: "email"}, gq.Func)
+	require.Equal(t, `(has "email")`, gq.Children[0].Filter.debugString())
+}
+
+func TestParseCount(t *testing.T) {
+	query := `
+	{
+		me(_uid_:0x0a) {
+			name
+			count(friends)
+			numFollows: count(follows)
+		}
+	}
+`
+	res, err := Parse(query)
+	require.NoError(t, err)
+	gq := res.Query[0]
+	require.Equal(t, []string{"name", "friends", "follows"}, childAttrs(gq))
+	require.Equal(t, "count", gq.Children[1].Func.Name)
+	require.Equal(t, "numFollows", gq.Children[2].Alias)
+	require.Equal(t, "count", gq.Children[2].Func.Name)
+}
+
+func TestParseShortestPath(t *testing.T) {
+	query := `
+	{
+		shortest(from: 0x0a, to: 0x0b) {
+			friends
+			neighbours
+		}
+	}
+`
+	res, err := Parse(query)
 	require.NoError(t, err)
+	gq := res.Query[0]
+	require.NotNil(t, gq)
+	require.Equal(t, &ShortestPath{From: 10, To: 11}, gq.ShortestPath)
+	require.Equal(t, childAttrs(gq), []string{"friends", "neighbours"})
+
+	query = `
+	{
+		shortest(from: 0x0a, to: 0x0b, depth: 4, maxvisited: 1000, weight: distance) {
+			road
+		}
+	}
+`
+	res, err = Parse(query)
+	require.NoError(t, err)
+	require.Equal(t, &ShortestPath{From: 10, To: 11, Depth: 4, MaxVisited: 1000,
+		Weight: "distance"}, res.Query[0].ShortestPath)
+
+	// Other blocks aren't shortest path queries.
+	res, err = Parse(`{ me(_uid_: 0x0a) { friends } }`)
+	require.NoError(t, err)
+	require.Nil(t, res.Query[0].ShortestPath)
+}
+
+func TestParseShortestPathError(t *testing.T) {
+	query := `
+	{
+		shortest(from: 0x0a) {
+			friends
+		}
+	}
+`
+	_, err := Parse(query)
+	require.Error(t, err)
+
+	query = `
+	{
+		me(from: 0x0a, to: 0x0b) {
+			friends
+		}
+	}
+`
+	_, err = Parse(query)
+	require.Error(t, err)
+
+	for _, args := range []string{
+		`anyof(name, "Rick")`,
+		`from: 0x0a, to: 0x0b, depth: 0`,
+		`from: 0x0a, to: 0x0b, maxvisited: many`,
+		`from: 0x0a, to: 0x0b, _uid_: 0x0c`,
+	} {
+		_, err = Parse(`{ shortest(` + args + `) { friends } }`)
+		require.Error(t, err, args)
+	}
+}
+
+func TestParseAggregator(t *testing.T) {
+	query := `
+	{
+		me(_uid_:0x0a) {
+			friends {
+				name
+				min(age)
+				avg(age)
+			}
+		}
+	}
+`
+	res, err := Parse(query)
+	require.NoError(t, err)
+	gq := res.Query[0]
+	require.NotNil(t, gq)
+	friends := gq.Children[0]
+	require.Equal(t, childAttrs(friends), []string{"name", "age", "age"})
+	require.Nil(t, friends.Children[0].Func)
+	require.Equal(t, "min", friends.Children[1].Func.Name)
+	require.Equal(t, "age", friends.Children[1].Func.Attr)
+	require.Equal(t, "avg", friends.Children[2].Func.Name)
+}
+
+func TestParseAggregatorError(t *testing.T) {
+	query := `
+	{
+		me(_uid_:0x0a) {
+			friends {
+				max(age: 10)
+			}
+		}
+	}
+`
+	_, err := Parse(query)
+	require.Error(t, err)
+}
+
+func TestParseMultipleBlocks(t *testing.T) {
+	query := `
+	{
+		me(_uid_:0x0a) {
+			name
+			f as friends {
+				name
+			}
+		}
+		you(id: var(f)) {
+			gender
+		}
+	}
+`
+	res, err := Parse(query)
+	require.NoError(t, err)
+	require.Len(t, res.Query, 2)
+	me, you := res.Query[0], res.Query[1]
+	require.Equal(t, "me", me.Alias)
+	require.Equal(t, childAttrs(me), []string{"name", "friends"})
+	require.Equal(t, "f", me.Children[1].Var)
+	require.Equal(t, childAttrs(me.Children[1]), []string{"name"})
+	require.Equal(t, "you", you.Alias)
+	require.Equal(t, "f", you.NeedsVar)
+	require.Equal(t, childAttrs(you), []string{"gender"})
+}
+
+func TestParseRootVar(t *testing.T) {
+	query := `
+	{
+		m as me(_uid_:0x0a) {
+			name
+		}
+		you(_uid_: var(m)) {
+			name
+		}
+	}
+`
+	res, err := Parse(query)
+	require.NoError(t, err)
+	require.Len(t, res.Query, 2)
+	require.Equal(t, "me", res.Query[0].Alias)
+	require.Equal(t, "m", res.Query[0].Var)
+	require.Equal(t, "m", res.Query[1].NeedsVar)
+}
+
+func TestParseVarNotDefined(t *testing.T) {
+	query := `
+	{
+		you(id: var(f)) {
+			gender
+		}
+		me(_uid_:0x0a) {
+			f as friends
+		}
+	}
+`
+	_, err := Parse(query)
+	require.Error(t, err)
+}
+
+func TestParseReverse(t *testing.T) {
+	query := `
+	{
+		me(_uid_:0x0a) {
+			~friends {
+				name
+			}
+		}
+	}
+`
+	res, err := Parse(query)
+	require.NoError(t, err)
+	gq := res.Query[0]
+	require.Equal(t, childAttrs(gq), []string{"~friends"})
+	require.Equal(t, childAttrs(gq.Children[0]), []string{"name"})
+}
+
+func TestParseFacets(t *testing.T) {
+	query := `
+	{
+		me(_uid_:0x0a) {
+			friends @facets {
+				name @facets(origin)
+			}
+			family(first: 2) @facets(since, close, orderdesc: since) {
+				name
+			}
+			hometown @facets
+		}
+	}
+`
+	res, err := Parse(query)
+	require.NoError(t, err)
+	gq := res.Query[0]
+	require.Equal(t, childAttrs(gq), []string{"friends", "family", "hometown"})
+	require.Equal(t, &Facets{}, gq.Children[0].Facets)
+	require.Equal(t, &Facets{Keys: []string{"origin"}},
+		gq.Children[0].Children[0].Facets)
+	require.Equal(t, &Facets{Keys: []string{"close", "since"}, OrderKey: "since",
+		OrderDesc: true}, gq.Children[1].Facets)
+	require.Equal(t, "2", gq.Children[1].Args["first"])
+	require.Equal(t, childAttrs(gq.Children[1]), []string{"name"})
+	require.Equal(t, &Facets{}, gq.Children[2].Facets)
+}
+
+func TestParseFacetsFilter(t *testing.T) {
+	query := `
+	{
+		me(_uid_:0x0a) {
+			friends @facets(eq("close", "true") && ge("since", "2010")) @facets(since) {
+				name
+			}
+			family @facets(eq("close", "true")) {
+				name
+			}
+		}
+	}
+`
+	res, err := Parse(query)
+	require.NoError(t, err)
+	gq := res.Query[0]
+	require.Equal(t, childAttrs(gq), []string{"friends", "family"})
+	friends := gq.Children[0]
+	require.Equal(t, `(AND (eq "close" "true") (ge "since" "2010"))`,
+		friends.FacetsFilter.debugString())
+	require.Equal(t, &Facets{Keys: []string{"since"}}, friends.Facets)
+	require.Equal(t, childAttrs(friends), []string{"name"})
+	// A filter alone doesn't ask for the facets.
+	require.Nil(t, gq.Children[1].Facets)
+	require.NotNil(t, gq.Children[1].FacetsFilter)
+}
+
+func TestParseFacetsError(t *testing.T) {
+	queries := []string{
+		`{me(_uid_:0x0a) { friends @facets(since: 2) { name } } }`,
+		`{me(_uid_:0x0a) { friends @facets(orderasc: since, orderdesc: close) { name } } }`,
+		`{me(_uid_:0x0a) @facets { friends } }`,
+	}
+	for _, q := range queries {
+		_, err := Parse(q)
+		require.Error(t, err, q)
+	}
+}
+
+func TestParseOrder(t *testing.T) {
+	query := `
+	{
+		me(_uid_:0x0a) {
+			friends(orderdesc: age, orderasc: name, first: 10) {
+				name
+			}
+			family(order: dob) {
+				name
+			}
+		}
+	}
+`
+	res, err := Parse(query)
+	require.NoError(t, err)
+	friends := res.Query[0].Children[0]
+	require.Equal(t, []*Order{{Attr: "age", Desc: true}, {Attr: "name"}}, friends.Order)
+	require.Equal(t, map[string]string{"first": "10"}, friends.Args)
+	family := res.Query[0].Children[1]
+	require.Equal(t, []*Order{{Attr: "dob"}}, family.Order)
+}
+
+func TestParseLangs(t *testing.T) {
+	query := `
+	{
+		me(_uid_:0x0a) {
+			name@fr:en:.
+			friends @filter(anyof("name", "Andrea")) {
+				name@en
+			}
+			alias@en
+		}
+	}
+`
+	res, err := Parse(query)
+	require.NoError(t, err)
+	me := res.Query[0]
+	require.Equal(t, []string{"name", "friends", "alias"}, childAttrs(me))
+	require.Equal(t, []string{"fr", "en", "."}, me.Children[0].Langs)
+	require.Nil(t, me.Children[1].Langs)
+	require.NotNil(t, me.Children[1].Filter)
+	require.Equal(t, []string{"en"}, me.Children[1].Children[0].Langs)
+	require.Equal(t, []string{"en"}, me.Children[2].Langs)
+}
+
+func TestParseDirectiveAfterName(t *testing.T) {
+	query := `
+	{
+		me(_uid_:0x0a) {
+			friends@filter(anyof("name", "Andrea")) {
+				name@en
+			}
+			friend@facets
+			name@fr
+		}
+	}
+`
+	res, err := Parse(query)
+	require.NoError(t, err)
+	me := res.Query[0]
+	require.Equal(t, []string{"friends", "friend", "name"}, childAttrs(me))
+	require.NotNil(t, me.Children[0].Filter)
+	require.Nil(t, me.Children[0].Langs)
+	require.Equal(t, []string{"en"}, me.Children[0].Children[0].Langs)
+	require.NotNil(t, me.Children[1].Facets)
+	require.Nil(t, me.Children[1].Langs)
+	require.Equal(t, []string{"fr"}, me.Children[2].Langs)
+}
+
+func TestParseLangsError(t *testing.T) {
+	for _, query := range []string{
+		`{ me(_uid_:0x0a) { name@ } }`,
+		`{ me(_uid_:0x0a) { name@fr::en } }`,
+	} {
+		_, err := Parse(query)
+		require.Error(t, err, query)
+	}
+}
+
+func TestParseMath(t *testing.T) {
+	query := `
+	{
+		me(_uid_:0x0a) {
+			friends(orderdesc: score) @filter(math(age * 12 > (200))) {
+				name
+				score: math(likes / (views + 1))
+				months : math (age*12)
+			}
+		}
+	}
+`
+	res, err := Parse(query)
+	require.NoError(t, err)
+	friends := res.Query[0].Children[0]
+	require.Equal(t, `(math (> (* age 12) 200))`, friends.Filter.debugString())
+	require.Equal(t, "", friends.Filter.Func.Attr)
+	require.Len(t, friends.Children, 3)
+
+	score := friends.Children[1]
+	require.Equal(t, "score", score.Alias)
+	require.Equal(t, "math", score.Attr)
+	require.Equal(t, "(/ likes (+ views 1))", score.MathExpr.debugString())
+	months := friends.Children[2]
+	require.Equal(t, "months", months.Alias)
+	require.Equal(t, "(* age 12)", months.MathExpr.debugString())
+}
+
+func TestParseMathError(t *testing.T) {
+	for _, query := range []string{
+		`{ me(_uid_:0x0a) { friends { math(age * 12) } } }`,
+		`{ me(_uid_:0x0a) { friends { score: math(age * ) } } }`,
+		`{ me(_uid_:0x0a) { friends { score: math(log(age)) } } }`,
+		`{ me(_uid_:0x0a) { friends { score: math((age * 12) } } }`,
+		`{ me(_uid_:0x0a) { friends @filter(math(age > )) { name } } }`,
+	} {
+		_, err := Parse(query)
+		require.Error(t, err, query)
+	}
+}
+
+func TestParseUidIn(t *testing.T) {
+	query := `
+	{
+		me(_uid_:0x0a) {
+			f as friends
+		}
+		you(_uid_:0x0b) {
+			friends @filter(uid_in(friends, 0x0a) || uid_in(friends, var(f))) {
+				name
+			}
+		}
+	}
+`
+	res, err := Parse(query)
+	require.NoError(t, err)
+	ft := res.Query[1].Children[0].Filter
+	require.Equal(t, `(OR (uid_in "friends" "0x0a") (uid_in "friends"))`, ft.debugString())
+	require.Equal(t, "", ft.Child[0].Func.NeedsVar)
+	require.Equal(t, "f", ft.Child[1].Func.NeedsVar)
+}
+
+func TestParseUidInError(t *testing.T) {
+	for _, query := range []string{
+		`{ me(_uid_:0x0a) { friends @filter(uid_in(friends)) { name } } }`,
+		`{ me(_uid_:0x0a) { friends @filter(uid_in(friends, rick)) { name } } }`,
+		`{ me(_uid_:0x0a) { friends @filter(uid_in(friends, var(f))) { name } } }`,
+	} {
+		_, err := Parse(query)
+		require.Error(t, err, query)
+	}
+}
+
+func TestParseRootFuncArgs(t *testing.T) {
+	query := `
+	{
+		me(anyof(name, "Rick"), first: 10, offset: 2, orderasc: name) @filter(has(age)) {
+			name
+		}
+	}
+`
+	res, err := Parse(query)
+	require.NoError(t, err)
+	gq := res.Query[0]
+	require.Equal(t, "anyof", gq.Func.Name)
+	require.Equal(t, []string{"Rick"}, gq.Func.Args)
+	require.Equal(t, "10", gq.Args["first"])
+	require.Equal(t, "2", gq.Args["offset"])
+	require.Equal(t, []*Order{{Attr: "name"}}, gq.Order)
+	require.Equal(t, `(has "age")`, gq.Filter.debugString())
+	require.Equal(t, []string{"name"}, childAttrs(gq))
+}
+
+func TestParseFilterArgsError(t *testing.T) {
+	query := `
+	{
+		me(_uid_:0x0a) {
+			friends @filter(anyof(name, "Rick"), first: 10) {
+				name
+			}
+		}
+	}
+`
+	_, err := Parse(query)
+	require.Error(t, err)
 }
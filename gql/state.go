@@ -19,6 +19,7 @@ package gql
 
 import (
 	"bytes"
+	"strings"
 
 	"github.com/dgraph-io/dgraph/lex"
 )
@@ -63,10 +64,14 @@ const (
 	itemDirectiveName                           // Name starting with @
 	itemFilterAnd                               // And inside a filter.
 	itemFilterOr                                // Or inside a filter.
+	itemFilterNot                               // Not inside a filter.
 	itemFilterFunc                              // Function inside a filter.
 	itemFilterFuncArg                           // Function args inside a filter.
 	itemGenerator                               // To specify its a generator.
 	itemArgument                                // To specify its a argument list.
+	itemFacetsFilter                            // To specify its a filter on facets.
+	itemLanguage                                // Languages of a predicate, like fr:en.
+	itemMathExpr                                // Expression inside math().
 )
 
 // lexText lexes the input string and calls other lex functions.
@@ -132,7 +137,8 @@ func lexInside(l *lex.Lexer) lex.StateFn {
 			return l.Errorf("Unclosed action")
 		case isSpace(r) || isEndOfLine(r) || r == comma:
 			l.Ignore()
-		case isNameBegin(r):
+		case isNameBegin(r) || r == '~':
+			// ~ denotes the reverse of a predicate, like ~friend.
 			return lexName
 		case r == '#':
 			l.Backup()
@@ -142,13 +148,12 @@ func lexInside(l *lex.Lexer) lex.StateFn {
 			l.AcceptRun(isSpace)
 			l.Ignore()
 			k := l.Next()
-			if k == '_' || l.Depth != 1 || l.Mode == fragmentMode {
-				l.Backup()
+			l.Backup()
+			if k == '_' || l.Depth != 1 || l.Mode == fragmentMode || isArgList(l) {
 				l.Emit(itemArgument)
 				return lexArgInside
 			}
 			// This is a generator function.
-			l.Backup()
 			l.Emit(itemGenerator)
 			l.FilterDepth++
 			return lexFilterInside
@@ -162,7 +167,50 @@ func lexInside(l *lex.Lexer) lex.StateFn {
 	}
 }
 
-// lexFilterFuncInside expects input to look like ("...", "...").
+// isArgList looks ahead to check if the input looks like name: value, as in
+// shortest(from: 0x01, to: 0x02), and not like a generator function.
+func isArgList(l *lex.Lexer) bool {
+	pos := l.Pos
+	defer func() { l.Pos = pos }()
+	l.AcceptRun(isNameSuffix)
+	l.AcceptRun(isSpace)
+	return l.Next() == ':'
+}
+
+// isFilterArg looks ahead to check if the input looks like a filter, as in
+// @facets(eq("close", "true")) or @facets(not eq("close", "true")), and not
+// like a list of names.
+func isFilterArg(l *lex.Lexer) bool {
+	pos, start := l.Pos, l.Start
+	defer func() { l.Pos, l.Start = pos, start }()
+	l.AcceptRun(isSpace)
+	l.Start = l.Pos
+	if isFilterNot(l) {
+		return true
+	}
+	l.AcceptRun(isNameSuffix)
+	l.AcceptRun(isSpace)
+	return l.Next() == leftRound
+}
+
+// isFilterNot checks if the name starting at l.Start is the not operator, as in
+// not anyof(...) or not (...), and not a function. If so, the operator is
+// consumed.
+func isFilterNot(l *lex.Lexer) bool {
+	const op = "not"
+	rest := l.Input[l.Start:]
+	if !strings.HasPrefix(rest, op) || len(rest) == len(op) {
+		return false
+	}
+	if r := rune(rest[len(op)]); !isSpace(r) && !isEndOfLine(r) && r != leftRound {
+		return false
+	}
+	l.Pos = l.Start + len(op)
+	return true
+}
+
+// lexFilterFuncInside expects input to look like ("...", "..."). Arguments
+// like 10 or count(friend) can also be given without quotes.
 func lexFilterFuncInside(l *lex.Lexer) lex.StateFn {
 	l.AcceptRun(isSpace)
 	l.Ignore() // Any spaces encountered.
@@ -182,14 +230,53 @@ func lexFilterFuncInside(l *lex.Lexer) lex.StateFn {
 			l.Emit(itemFilterFuncArg)
 			l.Next() // Consume " and ignore it.
 			l.Ignore()
+		} else if r == '/' {
+			// Regular expression, like /^Ste.*$/i. The slashes and flags are
+			// kept as part of the argument.
+			if !lexRegex(l) {
+				return l.Errorf("Unclosed regular expression")
+			}
+			l.Emit(itemFilterFuncArg)
+		} else if isNameSuffix(r) || r == '~' {
+			// An unquoted argument, like 10 or count(friend).
+			l.AcceptRun(isNameSuffix)
+			if l.Peek() == leftRound {
+				l.AcceptUntil(func(r rune) bool { return r == rightRound })
+				if l.Next() != rightRound {
+					return l.Errorf("Unclosed argument in lexFilterFuncArgs")
+				}
+			}
+			l.Emit(itemFilterFuncArg)
 		} else {
 			return l.Errorf("Expected quotation mark in lexFilterFuncArgs")
 		}
 	}
 }
 
+// lexRegex consumes a regular expression literal after the opening slash,
+// along with its closing slash and any flags following it. It returns false
+// if the input ends before the closing slash.
+func lexRegex(l *lex.Lexer) bool {
+	for {
+		r := l.Next()
+		switch {
+		case r == lex.EOF:
+			return false
+		case r == '\\':
+			// Skip over the escaped rune, which could be a slash.
+			if l.Next() == lex.EOF {
+				return false
+			}
+		case r == '/':
+			l.AcceptRun(isNameSuffix)
+			return true
+		}
+	}
+}
+
 // lexFilterFuncName expects input to look like equal("...", "...").
 func lexFilterFuncName(l *lex.Lexer) lex.StateFn {
+	var name string
 	for {
 		// The caller already checked isNameBegin, and absorbed one rune.
 		r := l.Next()
@@ -197,9 +284,24 @@ func lexFilterFuncName(l *lex.Lexer) lex.StateFn {
 			continue
 		}
 		l.Backup()
+		name = l.Input[l.Start:l.Pos]
 		l.Emit(itemFilterFunc)
 		break
 	}
+	if isMathStart(l, name) {
+		// The expression, like math(age * 12 > 200), is its only argument.
+		l.AcceptRun(isSpace)
+		l.Ignore()
+		l.Next()
+		l.Emit(itemLeftRound)
+		if !lexBalanced(l) {
+			return l.Errorf("Unclosed math expression")
+		}
+		l.Emit(itemFilterFuncArg)
+		l.Next()
+		l.Emit(itemRightRound)
+		return lexFilterInside
+	}
 	return lexFilterFuncInside
 }
 
@@ -224,7 +326,17 @@ func lexFilterInside(l *lex.Lexer) lex.StateFn {
 			return l.Errorf("Unclosed directive")
 		case isSpace(r) || isEndOfLine(r):
 			l.Ignore()
+		case r == comma && l.FilterDepth == 1:
+			// Arguments can follow the function at the root, like
+			// me(anyof(name, "x"), first: 10).
+			l.Emit(itemArgument)
+			l.FilterDepth = 0
+			return lexArgInside
 		case isNameBegin(r):
+			if isFilterNot(l) {
+				l.Emit(itemFilterNot)
+				continue
+			}
 			return lexFilterFuncName
 		case r == '&':
 			r2 := l.Next()
@@ -273,7 +385,23 @@ func lexDirective(l *lex.Lexer) lex.StateFn {
 		if string(directive) == "filter" {
 			return lexFilterInside
 		}
-		return l.Errorf("Unhandled directive %s", directive)
+		// Other directives can have an optional argument list like (depth: 5).
+		l.AcceptRun(isSpace)
+		l.Ignore() // Any spaces encountered.
+		if l.Peek() == leftRound {
+			l.Next()
+			l.Emit(itemLeftRound)
+			if string(directive) == "facets" && isFilterArg(l) {
+				// The edges can be filtered by their facets, like
+				// @facets(eq("close", "true")).
+				l.Emit(itemFacetsFilter)
+				l.FilterDepth++
+				return lexFilterInside
+			}
+			l.Emit(itemArgument)
+			return lexArgInside
+		}
+		return lexInside
 	}
 	return lexInside
 }
@@ -281,18 +409,70 @@ func lexDirective(l *lex.Lexer) lex.StateFn {
 func lexAlias(l *lex.Lexer) lex.StateFn {
 	l.AcceptRun(isSpace)
 	l.Ignore() // Any spaces encountered.
+	var name string
 	for {
 		r := l.Next()
 		if isNameSuffix(r) {
 			continue
 		}
 		l.Backup()
+		name = l.Input[l.Start:l.Pos]
 		l.Emit(itemAlias)
 		break
 	}
+	if isMathStart(l, name) {
+		return lexMathExpr
+	}
 	return lexInside
 }
 
+// isMathStart checks if name is math and is followed by (, as in
+// score: math(likes / views).
+func isMathStart(l *lex.Lexer, name string) bool {
+	if name != "math" {
+		return false
+	}
+	pos := l.Pos
+	defer func() { l.Pos = pos }()
+	l.AcceptRun(isSpace)
+	return l.Next() == leftRound
+}
+
+// lexMathExpr lexes the expression of math(...), which is emitted as is, up to
+// the matching right round bracket.
+func lexMathExpr(l *lex.Lexer) lex.StateFn {
+	l.AcceptRun(isSpace)
+	l.Next() // Consume ( and ignore it.
+	l.Ignore()
+	if !lexBalanced(l) {
+		return l.Errorf("Unclosed math expression")
+	}
+	l.Emit(itemMathExpr)
+	l.Next() // Consume ) and ignore it.
+	l.Ignore()
+	return lexInside
+}
+
+// lexBalanced consumes the input after a ( up to the matching ), which is left
+// unconsumed. It returns false if the input ends before that.
+func lexBalanced(l *lex.Lexer) bool {
+	depth := 1
+	for {
+		switch l.Next() {
+		case lex.EOF:
+			return false
+		case leftRound:
+			depth++
+		case rightRound:
+			depth--
+			if depth == 0 {
+				l.Backup()
+				return true
+			}
+		}
+	}
+}
+
 func lexFragmentSpread(l *lex.Lexer) lex.StateFn {
 	for {
 		r := l.Next()
@@ -307,6 +487,7 @@ func lexFragmentSpread(l *lex.Lexer) lex.StateFn {
 }
 
 func lexName(l *lex.Lexer) lex.StateFn {
+	var name string
 	for {
 		// The caller already checked isNameBegin, and absorbed one rune.
 		r := l.Next()
@@ -314,12 +495,49 @@ func lexName(l *lex.Lexer) lex.StateFn {
 			continue
 		}
 		l.Backup()
+		name = l.Input[l.Start:l.Pos]
 		l.Emit(itemName)
 		break
 	}
+	if isMathStart(l, name) {
+		return lexMathExpr
+	}
+	if l.Peek() == '@' && !isDirectiveNext(l) {
+		// A @ right after the name starts the languages to get its value in,
+		// like name@fr:en, unless a directive follows it, like name@filter.
+		l.Next()
+		l.Ignore()
+		l.AcceptRun(isLangRune)
+		if l.Pos == l.Start {
+			return l.Errorf("Expected a language after @")
+		}
+		l.Emit(itemLanguage)
+	}
 	return lexInside
 }
 
+// directives are the names which can follow a @, and aren't taken for the
+// languages of a predicate.
+var directives = map[string]bool{
+	"filter":    true,
+	"facets":    true,
+	"recurse":   true,
+	"cascade":   true,
+	"normalize": true,
+	"groupby":   true,
+}
+
+// isDirectiveNext returns true if the @ at the position of the lexer starts
+// one of the directives.
+func isDirectiveNext(l *lex.Lexer) bool {
+	rest := l.Input[l.Pos+1:]
+	end := strings.IndexFunc(rest, func(r rune) bool { return !isNameSuffix(r) })
+	if end < 0 {
+		end = len(rest)
+	}
+	return directives[rest[:end]]
+}
+
 // lexComment lexes a comment text.
 func lexComment(l *lex.Lexer) lex.StateFn {
 	for {
@@ -484,8 +702,18 @@ func lexVarName(l *lex.Lexer) lex.StateFn {
 func lexVarType(l *lex.Lexer) lex.StateFn {
 	l.AcceptRun(isSpace)
 	l.Ignore() // Any spaces encountered.
+	// Values can have parentheses of their own, like var(f).
+	depth := 0
 	for {
 		r := l.Next()
+		if r == leftRound {
+			depth++
+			continue
+		}
+		if r == rightRound && depth > 0 {
+			depth--
+			continue
+		}
 		if isSpace(r) || isEndOfLine(r) || r == rightRound || r == comma {
 			l.Backup()
 			l.Emit(itemVarType)
@@ -502,8 +730,18 @@ func lexVarType(l *lex.Lexer) lex.StateFn {
 func lexVarDefault(l *lex.Lexer) lex.StateFn {
 	l.AcceptRun(isSpace)
 	l.Ignore() // Any spaces encountered.
+	// Values can have parentheses of their own, like var(f).
+	depth := 0
 	for {
 		r := l.Next()
+		if r == leftRound {
+			depth++
+			continue
+		}
+		if r == rightRound && depth > 0 {
+			depth--
+			continue
+		}
 		if isSpace(r) || isEndOfLine(r) || r == rightRound || r == comma {
 			l.Backup()
 			l.Emit(itemVarDefault)
@@ -556,8 +794,18 @@ func lexArgName(l *lex.Lexer) lex.StateFn {
 func lexArgVal(l *lex.Lexer) lex.StateFn {
 	l.AcceptRun(isSpace)
 	l.Ignore() // Any spaces encountered.
+	// Values can have parentheses of their own, like var(f).
+	depth := 0
 	for {
 		r := l.Next()
+		if r == leftRound {
+			depth++
+			continue
+		}
+		if r == rightRound && depth > 0 {
+			depth--
+			continue
+		}
 		if isSpace(r) || isEndOfLine(r) || r == rightRound || r == comma {
 			l.Backup()
 			l.Emit(itemArgVal)
@@ -604,6 +852,13 @@ func isNameBegin(r rune) bool {
 	}
 }
 
+// isLangRune returns true if the rune can be part of a list of languages, like
+// fr:en-GB:. where . stands for no language.
+func isLangRune(r rune) bool {
+	return isNameBegin(r) || (r >= '0' && r <= '9') || r == '-' || r == ':' ||
+		r == '.'
+}
+
 func isNameSuffix(r rune) bool {
 	if isNameBegin(r) {
 		return true
@@ -148,7 +148,10 @@ func fpGroup(pred string) uint32 {
 	return farm.Fingerprint32([]byte(pred))%uint32(groupConfig.n) + uint32(groupConfig.k)
 }
 
+// BelongsTo returns the group the given predicate belongs to. Reverse edges,
+// like ~friend, are served by the group of the forward predicate.
 func BelongsTo(pred string) uint32 {
+	pred = strings.TrimPrefix(pred, "~")
 	for _, meta := range groupConfig.pred {
 		if meta.exactMatch && meta.val == pred {
 			return meta.gid
@@ -71,6 +71,10 @@ func TestGroups(t *testing.T) {
 	if gid != 2 {
 		t.Errorf("Expected groupId to be: %v. Got: %v", 2, gid)
 	}
+	gid = BelongsTo("~type.object.name.fr")
+	if gid != 2 {
+		t.Errorf("Expected groupId to be: %v. Got: %v", 2, gid)
+	}
 	gid = BelongsTo("film.actor.film")
 	if gid != 11 {
 		t.Errorf("Expected groupId to be: %v. Got: %v", 11, gid)
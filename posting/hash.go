@@ -75,6 +75,22 @@ func (s *listMap) PutIfMissing(key uint64, val *List) *List {
 	return s.shard[getShard(s.numShards, key)].putIfMissing(key, val)
 }
 
+func (s *listMapShard) each(f func(key uint64, val *List)) {
+	s.RLock()
+	defer s.RUnlock()
+	for k, v := range s.m {
+		f(k, v)
+	}
+}
+
+// Each iterates over listMap and calls the given function for each key, value
+// pair. The function must not access listMap.
+func (s *listMap) Each(f func(key uint64, val *List)) {
+	for _, shard := range s.shard {
+		shard.each(f)
+	}
+}
+
 func (s *listMapShard) eachWithDelete(f func(key uint64, val *List)) {
 	s.Lock()
 	defer s.Unlock()
@@ -18,6 +18,8 @@ package posting
 
 import (
 	"context"
+	"encoding/binary"
+	"log"
 	"sort"
 	"sync"
 
@@ -49,8 +51,11 @@ func init() {
 func initIndex() {
 	x.AssertTrue(pstore != nil)
 
-	// Initialize TokensTables.
+	// Initialize TokensTables, including those of the count indexes.
 	indexedFields := schema.IndexedFields()
+	for _, attr := range schema.CountedFields() {
+		indexedFields = append(indexedFields, CountAttr(attr))
+	}
 	type resultStruct struct {
 		attr  string
 		table *TokensTable
@@ -81,8 +86,9 @@ func initIndex() {
 
 }
 
-// indexTokens return tokens, without the predicate prefix and index rune.
-func indexTokens(attr string, p types.Value) ([]string, error) {
+// IndexTokens return tokens, without the predicate prefix and index rune.
+// The language of the value, if it has one, is used for full-text search.
+func IndexTokens(attr, lang string, p types.Value) ([]string, error) {
 	schemaType := schema.TypeOf(attr)
 	if !schemaType.IsScalar() {
 		return nil, x.Errorf("Cannot index attribute %s of type object.", attr)
@@ -104,16 +110,17 @@ func indexTokens(attr string, p types.Value) ([]string, error) {
 	case *types.Time:
 		return types.TimeIndex(attr, v)
 	case *types.String:
-		return types.DefaultIndexKeys(attr, v), nil
+		return types.DefaultIndexKeys(attr, lang, v, schema.IsFullText(attr)), nil
 	}
 	return nil, nil
 }
 
 // addIndexMutations adds mutation(s) for a single term, to maintain index.
-func addIndexMutations(ctx context.Context, attr string, uid uint64,
-	p types.Value, del bool) {
+// The tokens in keep aren't deleted.
+func addIndexMutations(ctx context.Context, attr, lang string, uid uint64,
+	p types.Value, del bool, keep map[string]bool) {
 	x.AssertTrue(uid != 0)
-	tokens, err := indexTokens(attr, p)
+	tokens, err := IndexTokens(attr, lang, p)
 	if err != nil {
 		// This data is not indexable
 		return
@@ -127,11 +134,211 @@ func addIndexMutations(ctx context.Context, attr string, uid uint64,
 	tokensTable := GetTokensTable(attr)
 	x.AssertTruef(tokensTable != nil, "TokensTable missing for attr %s", attr)
 
+	if schema.IsFullText(attr) {
+		updateFullTextStats(ctx, attr, lang, p, del)
+	}
 	for _, token := range tokens {
+		if del && keep[token] {
+			continue
+		}
 		addIndexMutation(ctx, attr, token, tokensTable, edge, del)
 	}
 }
 
+// fullTextStatsMu serializes the updates of the counters of full-text indexes.
+var fullTextStatsMu sync.Mutex
+
+// indexMetaAttr returns the attribute under which the metadata of the index
+// of attr is kept, apart from its tokens. It's kept in posting lists, like the
+// tokens, and only written as the mutations and rebuilds are applied, so that
+// all the replicas have the same.
+func indexMetaAttr(attr string) string {
+	return "%" + attr
+}
+
+// fullTextStatsKey returns the key holding the counters of the full-text
+// index of attr.
+func fullTextStatsKey(attr string) []byte {
+	return types.IndexKey(indexMetaAttr(attr), "")
+}
+
+// indexKindKey returns the key holding the kind of index attr was last built
+// with.
+func indexKindKey(attr string) []byte {
+	return types.IndexKey(indexMetaAttr(attr), "kind")
+}
+
+func indexKind(attr string) string {
+	if schema.IsFullText(attr) {
+		return "fulltext"
+	}
+	return "index"
+}
+
+// IndexStale returns true if the index of attr was last built with another
+// kind of index, or not at all, so that it's missing the tokens of the values
+// written before. It's rebuilt then with RebuildIndex.
+func IndexStale(attr string) bool {
+	pl, decr := GetOrCreate(indexKindKey(attr))
+	defer decr()
+	val, _, err := pl.Value()
+	return err != nil || string(val) != indexKind(attr)
+}
+
+// RebuildIndex adds the index tokens of all the values of attr, and records
+// the kind of index it was built with. The tokens already there are left as
+// they are, but the counters of the full-text index are recomputed, so attr
+// shouldn't be mutated meanwhile. It's applied through RAFT, in order with the
+// mutations, so that all the replicas of the group rebuild it alike.
+func RebuildIndex(ctx context.Context, attr string) error {
+	tokensTable := GetTokensTable(attr)
+	if tokensTable == nil {
+		return x.Errorf("Attribute %s is not indexed", attr)
+	}
+	log.Printf("Rebuilding index for attr %s", attr)
+	fullText := schema.IsFullText(attr)
+	var docs, total uint64
+
+	// The lists with mutations which aren't committed yet are included.
+	uids, err := UidsWithAttr(attr)
+	if err != nil {
+		return err
+	}
+	for _, uid := range uids.Uids {
+		edge := &task.DirectedEdge{ValueId: uid, Attr: attr, Label: "idx"}
+
+		pl, decr := GetOrCreate(Key(uid, attr))
+		for _, lang := range pl.Langs() {
+			vbytes, vtype, err := pl.LangValue(lang)
+			if err != nil {
+				continue
+			}
+			p := types.ValueForType(types.TypeID(vtype))
+			if err := p.UnmarshalBinary(vbytes); err != nil {
+				continue
+			}
+			tokens, err := IndexTokens(attr, lang, p)
+			if err != nil {
+				continue
+			}
+			for _, token := range tokens {
+				addIndexMutation(ctx, attr, token, tokensTable, edge, false)
+			}
+			if !fullText {
+				continue
+			}
+			if s, ok := p.(*types.String); ok {
+				if terms, err := types.FullTextTerms(lang, s); err == nil {
+					docs++
+					total += uint64(len(terms))
+				}
+			}
+		}
+		decr()
+	}
+	if fullText {
+		fullTextStatsMu.Lock()
+		err := setFullTextStats(ctx, attr, docs, total)
+		fullTextStatsMu.Unlock()
+		if err != nil {
+			return err
+		}
+	}
+	pl, decr := GetOrCreate(indexKindKey(attr))
+	defer decr()
+	edge := &task.DirectedEdge{Value: []byte(indexKind(attr)), Attr: attr, Label: "idx"}
+	_, err = pl.AddMutation(ctx, edge, Set)
+	return err
+}
+
+// FullTextStats returns the number of values in the full-text index of attr,
+// and the total number of their terms. Both are needed to score the results
+// of a search.
+func FullTextStats(attr string) (docs, terms uint64) {
+	pl, decr := GetOrCreate(fullTextStatsKey(attr))
+	defer decr()
+	val, _, err := pl.Value()
+	if err != nil || len(val) != 16 {
+		return 0, 0
+	}
+	return binary.BigEndian.Uint64(val[:8]), binary.BigEndian.Uint64(val[8:])
+}
+
+// updateFullTextStats adds the value p to the counters of the full-text index
+// of attr, or removes it if del is set.
+func updateFullTextStats(ctx context.Context, attr, lang string, p types.Value,
+	del bool) {
+	v, err := schema.TypeOf(attr).(types.Scalar).Convert(p)
+	if err != nil {
+		return
+	}
+	s, ok := v.(*types.String)
+	if !ok {
+		return
+	}
+	terms, err := types.FullTextTerms(lang, s)
+	if err != nil {
+		// Full-text search isn't supported for the language.
+		return
+	}
+
+	fullTextStatsMu.Lock()
+	defer fullTextStatsMu.Unlock()
+	docs, total := FullTextStats(attr)
+	n := uint64(len(terms))
+	if !del {
+		docs, total = docs+1, total+n
+	} else if docs > 0 && total >= n {
+		docs, total = docs-1, total-n
+	}
+	if err := setFullTextStats(ctx, attr, docs, total); err != nil {
+		x.TraceError(ctx, x.Wrapf(err,
+			"Error updating full-text counters for attr %s", attr))
+	}
+}
+
+// setFullTextStats sets the counters of the full-text index of attr. The
+// caller should hold fullTextStatsMu.
+func setFullTextStats(ctx context.Context, attr string, docs, terms uint64) error {
+	var buf [16]byte
+	binary.BigEndian.PutUint64(buf[:8], docs)
+	binary.BigEndian.PutUint64(buf[8:], terms)
+
+	pl, decr := GetOrCreate(fullTextStatsKey(attr))
+	defer decr()
+	edge := &task.DirectedEdge{Value: buf[:], Attr: attr, Label: "idx"}
+	_, err := pl.AddMutation(ctx, edge, Set)
+	return err
+}
+
+// otherLangTokens returns the index tokens of the values of l in languages
+// other than lang. The values in all languages share the term tokens, so
+// these must stay when the value in lang is deleted.
+func (l *List) otherLangTokens(attr, lang string) map[string]bool {
+	keep := make(map[string]bool)
+	for _, other := range l.Langs() {
+		if other == lang {
+			continue
+		}
+		vbytes, vtype, err := l.LangValue(other)
+		if err != nil {
+			continue
+		}
+		p := types.ValueForType(types.TypeID(vtype))
+		if err := p.UnmarshalBinary(vbytes); err != nil {
+			continue
+		}
+		tokens, err := IndexTokens(attr, other, p)
+		if err != nil {
+			continue
+		}
+		for _, token := range tokens {
+			keep[token] = true
+		}
+	}
+	return keep
+}
+
 func addIndexMutation(ctx context.Context, attr, token string,
 	tokensTable *TokensTable, edge *task.DirectedEdge, del bool) {
 	plist, decr := GetOrCreate(types.IndexKey(attr, token))
@@ -163,26 +370,156 @@ func addIndexMutation(ctx context.Context, attr, token string,
 	}
 }
 
-// AddMutationWithIndex is AddMutation with support for indexing.
+// ReverseAttr returns the attribute under which the reverse edges of attr are
+// stored.
+func ReverseAttr(attr string) string {
+	return "~" + attr
+}
+
+// addReverseMutation applies the reverse of the UID edge t, so that it can
+// be traversed from the destination UID using ~attr.
+func addReverseMutation(ctx context.Context, t *task.DirectedEdge, op uint32) error {
+	edge := &task.DirectedEdge{
+		Entity:  t.ValueId,
+		ValueId: t.Entity,
+		Attr:    ReverseAttr(t.Attr),
+		Label:   t.Label,
+		Facets:  t.Facets,
+	}
+	plist, decr := GetOrCreate(Key(edge.Entity, edge.Attr))
+	defer decr()
+
+	hasMutated, err := plist.AddMutation(ctx, edge, op)
+	if err != nil {
+		x.TraceError(ctx, x.Wrapf(err,
+			"Error adding reverse edge for attr %s entity %d", t.Attr, t.Entity))
+		return err
+	}
+	if hasMutated {
+		// The object gets the reverse predicate recorded, for expand(_all_).
+		if err := plist.recordPredicate(ctx, edge, op); err != nil {
+			return err
+		}
+	}
+	indexLog.Printf("REVERSE [%s] [%d] [%d] op: %d",
+		t.Attr, t.Entity, t.ValueId, op)
+	return nil
+}
+
+// recordPredicate records the predicate of t for its entity, for
+// expand(_all_), once t is applied to the list l. The record is removed when
+// l has no postings left. It is kept in the group serving the predicate.
+func (l *List) recordPredicate(ctx context.Context, t *task.DirectedEdge, op uint32) error {
+	if op == Del && l.Length(0) > 0 {
+		return nil
+	}
+	edge := &task.DirectedEdge{
+		Entity: t.Entity,
+		Attr:   PredicateAttr,
+		Value:  []byte(t.Attr),
+		Label:  t.Label,
+	}
+	plist, decr := GetOrCreate(Key(edge.Entity, edge.Attr))
+	defer decr()
+
+	if _, err := plist.AddMutation(ctx, edge, op); err != nil {
+		x.TraceError(ctx, x.Wrapf(err,
+			"Error recording predicate %s for entity %d", t.Attr, t.Entity))
+		return err
+	}
+	return nil
+}
+
+// CountAttr returns the attribute under which the count index of attr is
+// stored. Its tokens are the numbers of edges, and hold the UIDs which have
+// that many edges of attr.
+func CountAttr(attr string) string {
+	return "#" + attr
+}
+
+// CountToken returns the token of the count index for n edges. It is big
+// endian, so that the tokens sort in the order of the counts.
+func CountToken(n uint32) string {
+	var b [4]byte
+	binary.BigEndian.PutUint32(b[:], n)
+	return string(b[:])
+}
+
+// CountFromToken returns the number of edges of a token of the count index.
+func CountFromToken(token string) uint32 {
+	x.AssertTrue(len(token) == 4)
+	return binary.BigEndian.Uint32([]byte(token))
+}
+
+// updateCountIndex moves uid from the token for before edges to the one for
+// after edges, in the count index of attr. The UIDs without any edges aren't
+// kept in the index.
+func updateCountIndex(ctx context.Context, attr string, uid uint64, before, after int) {
+	if before == after {
+		return
+	}
+	cattr := CountAttr(attr)
+	edge := &task.DirectedEdge{
+		ValueId: uid,
+		Attr:    cattr,
+		Label:   "idx",
+	}
+	tokensTable := GetTokensTable(cattr)
+	x.AssertTruef(tokensTable != nil, "TokensTable missing for attr %s", cattr)
+	if before > 0 {
+		addIndexMutation(ctx, cattr, CountToken(uint32(before)), tokensTable, edge, true)
+	}
+	if after > 0 {
+		addIndexMutation(ctx, cattr, CountToken(uint32(after)), tokensTable, edge, false)
+	}
+}
+
+// AddMutationWithIndex is AddMutation with support for indexing, reverse
+// edges and the count index.
 func (l *List) AddMutationWithIndex(ctx context.Context, t *task.DirectedEdge, op uint32) error {
 	x.AssertTruef(len(t.Attr) > 0 && t.Attr[0] != ':',
 		"[%s] [%d] [%v] %d %d\n", t.Attr, t.Entity, t.Value, t.ValueId, op)
+	if op == Del && string(t.Value) == x.Star {
+		return l.deleteAllWithIndex(ctx, t)
+	}
 
 	var vbytes []byte
 	var vtype byte
 	var verr error
 
+	doUpdateCount := pstore != nil && t.Value == nil && schema.IsCounted(t.Attr)
+	var countBefore int
+	if doUpdateCount {
+		countBefore = l.Length(0)
+	}
+
 	doUpdateIndex := pstore != nil && (t.Value != nil) &&
 		schema.IsIndexed(t.Attr)
 	if doUpdateIndex {
 		// Check last posting for original value BEFORE any mutation actually happens.
-		vbytes, vtype, verr = l.Value()
+		vbytes, vtype, verr = l.LangValue(t.Lang)
 	}
 	hasMutated, err := l.AddMutation(ctx, t, op)
 	if err != nil {
 		return err
 	}
-	if !hasMutated || !doUpdateIndex {
+	if !hasMutated {
+		return nil
+	}
+	if t.Attr != PredicateAttr {
+		if err := l.recordPredicate(ctx, t, op); err != nil {
+			return err
+		}
+	}
+	if t.Value == nil && schema.IsReversed(t.Attr) {
+		if err := addReverseMutation(ctx, t, op); err != nil {
+			return err
+		}
+	}
+	if doUpdateCount {
+		updateCountIndex(ctx, t.Attr, t.Entity, countBefore, l.Length(0))
+	}
+	if !doUpdateIndex {
 		return nil
 	}
 
@@ -194,14 +531,15 @@ func (l *List) AddMutationWithIndex(ctx context.Context, t *task.DirectedEdge, o
 		if err := p.UnmarshalBinary(delTerm); err != nil {
 			return err
 		}
-		addIndexMutations(ctx, t.Attr, t.Entity, p, true)
+		addIndexMutations(ctx, t.Attr, t.Lang, t.Entity, p, true,
+			l.otherLangTokens(t.Attr, t.Lang))
 	}
 	if op == Set {
 		p := types.ValueForType(types.TypeID(t.ValueType))
 		if err := p.UnmarshalBinary(t.Value); err != nil {
 			return err
 		}
-		addIndexMutations(ctx, t.Attr, t.Entity, p, false)
+		addIndexMutations(ctx, t.Attr, t.Lang, t.Entity, p, false, nil)
 	}
 	return nil
 }
@@ -300,3 +638,28 @@ func (t *TokensTable) GetFirst() string {
 	}
 	return t.key[0]
 }
+
+// GetPrev returns the key before the given key. If we reach the start, we
+// return an empty string.
+func (t *TokensTable) GetPrev(key string) string {
+	t.RLock()
+	defer t.RUnlock()
+	i := sort.Search(len(t.key),
+		func(i int) bool {
+			return t.key[i] >= key
+		})
+	if i > 0 {
+		return t.key[i-1]
+	}
+	return ""
+}
+
+// GetLast returns the last key in our list of keys.
+func (t *TokensTable) GetLast() string {
+	t.RLock()
+	defer t.RUnlock()
+	if len(t.key) == 0 {
+		return ""
+	}
+	return t.key[len(t.key)-1]
+}
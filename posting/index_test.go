@@ -1,10 +1,15 @@
 package posting
 
 import (
+	"context"
+	"io/ioutil"
+	"os"
 	"testing"
 	"time"
 
 	"github.com/dgraph-io/dgraph/schema"
+	"github.com/dgraph-io/dgraph/store"
+	"github.com/dgraph-io/dgraph/task"
 	"github.com/dgraph-io/dgraph/types"
 	"github.com/stretchr/testify/require"
 )
@@ -14,9 +19,9 @@ func TestIndexingInt(t *testing.T) {
 	v = 10
 
 	schema.ParseBytes([]byte("scalar age:int @index"))
-	a, err := indexTokens("age", types.Value(&v))
+	a, err := IndexTokens("age", "", types.Value(&v))
 	require.NoError(t, err)
-	require.EqualValues(t, []byte{0x0, 0x0, 0x0, 0xa}, a[0])
+	require.EqualValues(t, []byte{0x80, 0x0, 0x0, 0xa}, a[0])
 }
 
 func TestIndexingFloat(t *testing.T) {
@@ -24,9 +29,10 @@ func TestIndexingFloat(t *testing.T) {
 	v = 10.43
 
 	schema.ParseBytes([]byte("scalar age:float @index"))
-	a, err := indexTokens("age", types.Value(&v))
+	a, err := IndexTokens("age", "", types.Value(&v))
 	require.NoError(t, err)
-	require.EqualValues(t, []byte{0x0, 0x0, 0x0, 0xa}, a[0])
+	require.EqualValues(t,
+		[]byte{0xc0, 0x24, 0xdc, 0x28, 0xf5, 0xc2, 0x8f, 0x5c}, a[0])
 }
 
 func TestIndexingDate(t *testing.T) {
@@ -34,9 +40,9 @@ func TestIndexingDate(t *testing.T) {
 	v.Time = time.Date(10, 1, 1, 1, 1, 1, 1, time.UTC)
 
 	schema.ParseBytes([]byte("scalar age:date @index"))
-	a, err := indexTokens("age", types.Value(&v))
+	a, err := IndexTokens("age", "", types.Value(&v))
 	require.NoError(t, err)
-	require.EqualValues(t, []byte{0x0, 0x0, 0x0, 0xa}, a[0])
+	require.EqualValues(t, []byte{0x80, 0x0, 0x0, 0xa}, a[0])
 }
 
 func TestIndexingTime(t *testing.T) {
@@ -44,9 +50,9 @@ func TestIndexingTime(t *testing.T) {
 	v.Time = time.Date(10, 1, 1, 1, 1, 1, 1, time.UTC)
 
 	schema.ParseBytes([]byte("scalar age:datetime @index"))
-	a, err := indexTokens("age", types.Value(&v))
+	a, err := IndexTokens("age", "", types.Value(&v))
 	require.NoError(t, err)
-	require.EqualValues(t, []byte{0x0, 0x0, 0x0, 0xa}, a[0])
+	require.EqualValues(t, []byte{0x80, 0x0, 0x0, 0xa}, a[0])
 }
 
 func TestIndexing(t *testing.T) {
@@ -54,7 +60,118 @@ func TestIndexing(t *testing.T) {
 	v = "abc"
 
 	schema.ParseBytes([]byte("scalar name:string @index"))
-	a, err := indexTokens("name", types.Value(&v))
+	a, err := IndexTokens("name", "", types.Value(&v))
 	require.NoError(t, err)
 	require.EqualValues(t, "abc", string(a[0]))
+	require.Contains(t, a, types.ExactToken("abc"))
+	require.NotContains(t, a, types.FullTextToken("abc"))
+
+	schema.ParseBytes([]byte("scalar description:string @index(fulltext)"))
+	a, err = IndexTokens("description", "", types.Value(&v))
+	require.NoError(t, err)
+	require.Contains(t, a, types.ExactToken("abc"))
+	require.Contains(t, a, types.FullTextToken("abc"))
+}
+
+func TestTokensTableBackwards(t *testing.T) {
+	tt := NewTokensTable()
+	for _, s := range []string{"b", "d", "a", "c"} {
+		tt.Add(s)
+	}
+	var keys []string
+	for k := tt.GetLast(); len(k) > 0; k = tt.GetPrev(k) {
+		keys = append(keys, k)
+	}
+	require.Equal(t, []string{"d", "c", "b", "a"}, keys)
+	require.Equal(t, "", NewTokensTable().GetLast())
+}
+
+func TestCountToken(t *testing.T) {
+	tt := NewTokensTable()
+	for _, n := range []uint32{300, 2, 1, 256} {
+		tt.Add(CountToken(n))
+	}
+	var counts []uint32
+	for k := tt.GetFirst(); len(k) > 0; k = tt.GetNext(k) {
+		counts = append(counts, CountFromToken(k))
+	}
+	require.Equal(t, []uint32{1, 2, 256, 300}, counts)
+}
+
+func TestIndexingLang(t *testing.T) {
+	v := types.String("running shoes")
+
+	schema.ParseBytes([]byte("scalar name:string @index(fulltext)"))
+	a, err := IndexTokens("name", "en-GB", types.Value(&v))
+	require.NoError(t, err)
+	require.Contains(t, a, types.FullTextToken("run"))
+
+	// There's no full-text search in French yet, but the terms are indexed.
+	a, err = IndexTokens("name", "fr", types.Value(&v))
+	require.NoError(t, err)
+	require.Contains(t, a, "running")
+	require.NotContains(t, a, types.ExactToken("running shoes"))
+	require.NotContains(t, a, types.FullTextToken("run"))
+}
+
+func TestRebuildIndex(t *testing.T) {
+	dir, err := ioutil.TempDir("", "storetest_")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	ps, err := store.NewStore(dir)
+	require.NoError(t, err)
+	defer ps.Close()
+
+	// Values written before the attribute is indexed.
+	schema.ParseBytes([]byte("scalar nick:string"))
+	Init(ps)
+	for uid, nick := range map[uint64]string{5: "Rick", 7: "Glenn the walker"} {
+		ol := getNew()
+		ol.init(Key(uid, "nick"), ps)
+		addMutation(t, ol, &task.DirectedEdge{
+			Value:     []byte(nick),
+			ValueType: uint32(types.StringID),
+			Label:     "testing",
+			Attr:      "nick",
+			Entity:    uid,
+		}, Set)
+		_, err := ol.CommitIfDirty(context.Background())
+		require.NoError(t, err)
+	}
+
+	// The index is only rebuilt when asked to, through RAFT.
+	schema.ParseBytes([]byte("scalar nick:string @index(fulltext)"))
+	Init(ps)
+	uids := func(token string) []uint64 {
+		pl, decr := GetOrCreate(types.IndexKey("nick", token))
+		defer decr()
+		return pl.Uids(ListOptions{}).Uids
+	}
+	require.True(t, IndexStale("nick"))
+	require.Empty(t, uids(types.ExactToken("Rick")))
+	docs, terms := FullTextStats("nick")
+	require.Zero(t, docs)
+	require.Zero(t, terms)
+
+	require.NoError(t, RebuildIndex(context.Background(), "nick"))
+	require.False(t, IndexStale("nick"))
+	require.Equal(t, []uint64{5}, uids(types.ExactToken("Rick")))
+	require.Equal(t, []uint64{7}, uids("walker"))
+	require.Equal(t, []uint64{7}, uids(types.FullTextToken("walker")))
+	docs, terms = FullTextStats("nick")
+	require.EqualValues(t, 2, docs)
+	require.EqualValues(t, 3, terms)
+
+	// The metadata of the index is kept in posting lists too, so that it's
+	// copied to the other replicas along with the rest.
+	for _, key := range [][]byte{indexKindKey("nick"), fullTextStatsKey("nick")} {
+		pl, decr := GetOrCreate(key)
+		_, err := pl.CommitIfDirty(context.Background())
+		decr()
+		require.NoError(t, err)
+		ol := getNew()
+		ol.init(key, ps)
+		_, _, err = ol.Value()
+		require.NoError(t, err)
+	}
 }
@@ -26,6 +26,7 @@ import (
 	"math"
 	"sort"
 	"strconv"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -129,6 +130,81 @@ func samePosting(a *types.Posting, b *types.Posting) bool {
 	return true
 }
 
+// sameFacets returns true if both postings have the same facets. Unlike the
+// content compared by samePosting, a change in facets doesn't stop a Del from
+// removing the posting; it only makes a Set replace it.
+func sameFacets(a *types.Posting, b *types.Posting) bool {
+	if len(a.Facets) != len(b.Facets) {
+		return false
+	}
+	for i, fa := range a.Facets {
+		fb := b.Facets[i]
+		if fa.Key != fb.Key || fa.ValType != fb.ValType ||
+			!bytes.Equal(fa.Value, fb.Value) {
+			return false
+		}
+	}
+	return true
+}
+
+// PredicateAttr is the attribute which records the predicates set for each
+// entity, for expand(_all_). Unlike other attributes, an entity can have many
+// values for it, one for each predicate.
+const PredicateAttr = "_predicate_"
+
+// PredicateUid returns the uid of the posting which records the predicate
+// attr, in the posting list of PredicateAttr, unless another predicate has
+// taken it first. The postings are told apart by their values.
+func PredicateUid(attr string) uint64 {
+	return fingerprintUid(attr)
+}
+
+// findPredicate returns the posting recording attr, if there is one.
+// Otherwise it returns the first free uid from PredicateUid on, as the
+// fingerprints of two predicates may be the same. The postings are sought by
+// uid, so that it doesn't go over the whole list. l must be locked.
+func (l *List) findPredicate(attr string) (*types.Posting, uint64) {
+	uid := PredicateUid(attr)
+	for {
+		p := l.postingAt(uid)
+		if p == nil || string(p.Value) == attr {
+			return p, uid
+		}
+		uid++
+		if uid == math.MaxUint64 {
+			uid = 1
+		}
+	}
+}
+
+// postingAt returns the posting with the given uid, or nil if there is none.
+// l must be locked.
+func (l *List) postingAt(uid uint64) *types.Posting {
+	var found *types.Posting
+	l.iterate(uid-1, func(p *types.Posting) bool {
+		if p.Uid == uid {
+			found = p
+		}
+		return false
+	})
+	return found
+}
+
+// LangUid returns the uid of the posting which holds the value in the
+// language lang, in the posting list of an entity.
+func LangUid(lang string) uint64 {
+	return fingerprintUid(lang)
+}
+
+func fingerprintUid(s string) uint64 {
+	uid := farm.Fingerprint64([]byte(s))
+	if uid == 0 || uid == math.MaxUint64 {
+		// These are taken.
+		uid = 1
+	}
+	return uid
+}
+
 // Key = attribute|uid
 func Key(uid uint64, attr string) []byte {
 	buf := make([]byte, len(attr)+9)
@@ -168,7 +244,8 @@ func debugKey(key []byte) string {
 }
 
 func newPosting(t *task.DirectedEdge, op uint32) *types.Posting {
-	x.AssertTruef(bytes.Equal(t.Value, nil) || t.ValueId == math.MaxUint64,
+	x.AssertTruef(bytes.Equal(t.Value, nil) || t.ValueId == math.MaxUint64 ||
+		t.Attr == PredicateAttr || len(t.Lang) > 0,
 		"This should have been set by the caller.")
 
 	return &types.Posting{
@@ -176,6 +253,8 @@ func newPosting(t *task.DirectedEdge, op uint32) *types.Posting {
 		Value:   t.Value,
 		ValType: uint32(t.ValueType),
 		Label:   t.Label,
+		Facets:  t.Facets,
+		Lang:    t.Lang,
 		Op:      op,
 	}
 }
@@ -237,11 +316,13 @@ func (l *List) updateMutationLayer(mpost *types.Posting) bool {
 		// Note that mpost.Op is either Set or Del, whereas oldPost.Op can be
 		// either Set or Del or Add.
 		msame := samePosting(oldPost, mpost)
-		if msame && ((mpost.Op == Del) == (oldPost.Op == Del)) {
+		if msame && ((mpost.Op == Del) == (oldPost.Op == Del)) &&
+			(mpost.Op == Del || sameFacets(oldPost, mpost)) {
 			// This posting has similar content as what is found in mlayer. If the
 			// ops are similar, then we do nothing. Note that Add and Set are
 			// considered similar, and the second clause is true also when
-			// mpost.Op==Add and oldPost.Op==Set.
+			// mpost.Op==Add and oldPost.Op==Set. A Set with new facets still
+			// replaces the old post.
 			return false
 		}
 
@@ -279,17 +360,18 @@ func (l *List) updateMutationLayer(mpost *types.Posting) bool {
 		return mpost.Uid <= p.Uid
 	})
 
-	var uidFound, psame bool
+	var uidFound, psame, pfacets bool
 	if pidx < len(pl.Postings) {
 		p := pl.Postings[pidx]
 		uidFound = mpost.Uid == p.Uid
 		if uidFound {
 			psame = samePosting(p, mpost)
+			pfacets = sameFacets(p, mpost)
 		}
 	}
 
 	if mpost.Op == Set {
-		if psame {
+		if psame && pfacets {
 			return false
 		}
 		if !uidFound {
@@ -348,18 +430,6 @@ func (l *List) AddMutation(ctx context.Context, t *task.DirectedEdge, op uint32)
 		return false, ErrRetry
 	}
 
-	// All edges with a value set, have the same uid. In other words,
-	// an (entity, attribute) can only have one value.
-	if !bytes.Equal(t.Value, nil) {
-		t.ValueId = math.MaxUint64
-	}
-	if t.ValueId == 0 {
-		err := x.Errorf("ValueId cannot be zero")
-		x.TraceError(ctx, err)
-		return false, err
-	}
-	mpost := newPosting(t, op)
-
 	// Mutation arrives:
 	// - Check if we had any(SET/DEL) before this, stored in the mutation list.
 	//		- If yes, then replace that mutation. Jump to a)
@@ -369,7 +439,37 @@ func (l *List) AddMutation(ctx context.Context, t *task.DirectedEdge, op uint32)
 	l.Lock()
 	defer l.Unlock()
 
+	// All edges with a value set, have the same uid. In other words,
+	// an (entity, attribute) can only have one value. The predicates recorded
+	// for an entity are the exception, and so are the values in a language,
+	// of which there is one per language.
+	if t.Attr == PredicateAttr {
+		p, uid := l.findPredicate(string(t.Value))
+		if (p != nil) == (op == Set) {
+			// Already recorded, or nothing to delete.
+			return false, nil
+		}
+		if p != nil {
+			// It's deleted by name, whatever it was recorded with.
+			t.Label = p.Label
+		}
+		t.ValueId = uid
+	} else if !bytes.Equal(t.Value, nil) && len(t.Lang) > 0 {
+		t.ValueId = LangUid(t.Lang)
+	} else if !bytes.Equal(t.Value, nil) {
+		t.ValueId = math.MaxUint64
+	}
+	if t.ValueId == 0 {
+		err := x.Errorf("ValueId cannot be zero")
+		x.TraceError(ctx, err)
+		return false, err
+	}
+	mpost := newPosting(t, op)
+	wasClean := len(l.mlayer) == 0
 	hasMutated := l.updateMutationLayer(mpost)
+	if wasClean && len(l.mlayer) > 0 {
+		markDirty(l.key, true)
+	}
 	if len(l.mlayer) > 0 {
 		atomic.StoreInt64(&l.dirtyTs, time.Now().UnixNano())
 		if dirtyChan != nil {
@@ -379,6 +479,69 @@ func (l *List) AddMutation(ctx context.Context, t *task.DirectedEdge, op uint32)
 	return hasMutated, nil
 }
 
+// deleteAllWithIndex deletes every posting of the list, for a deletion of
+// S P *. Each is deleted on its own, so that the index, the reverse edges and
+// the count index are updated as for an explicit deletion.
+func (l *List) deleteAllWithIndex(ctx context.Context, t *task.DirectedEdge) error {
+	if strings.HasPrefix(t.Attr, "~") {
+		return l.deleteReverseWithIndex(ctx, t)
+	}
+	var edges []*task.DirectedEdge
+	l.Iterate(0, func(p *types.Posting) bool {
+		edge := &task.DirectedEdge{
+			Entity: t.Entity,
+			Attr:   t.Attr,
+			Label:  p.Label,
+			Lang:   p.Lang,
+		}
+		if bytes.Equal(p.Value, nil) {
+			edge.ValueId = p.Uid
+		} else {
+			edge.Value = p.Value
+			edge.ValueType = p.ValType
+		}
+		edges = append(edges, edge)
+		return true
+	})
+	for _, edge := range edges {
+		if err := l.AddMutationWithIndex(ctx, edge, Del); err != nil {
+			return err
+		}
+	}
+	if t.Attr == PredicateAttr {
+		return nil
+	}
+	// The record of the predicate may be older than the list being emptied.
+	return l.recordPredicate(ctx, t, Del)
+}
+
+// deleteReverseWithIndex deletes every reverse edge of the list, for a
+// deletion of S ~P *, by deleting the edges O P S they stand for.
+func (l *List) deleteReverseWithIndex(ctx context.Context, t *task.DirectedEdge) error {
+	attr := strings.TrimPrefix(t.Attr, "~")
+	var edges []*task.DirectedEdge
+	l.Iterate(0, func(p *types.Posting) bool {
+		edges = append(edges, &task.DirectedEdge{
+			Entity:  p.Uid,
+			Attr:    attr,
+			ValueId: t.Entity,
+			Label:   p.Label,
+		})
+		return true
+	})
+	for _, edge := range edges {
+		plist, decr := GetOrCreate(Key(edge.Entity, edge.Attr))
+		err := plist.AddMutationWithIndex(ctx, edge, Del)
+		decr()
+		if err != nil {
+			return err
+		}
+	}
+	// The edges may not be reversed any more, in which case the reverse
+	// edges are left as they are, and so is their record.
+	return l.recordPredicate(ctx, t, Del)
+}
+
 // Iterate will allow you to iterate over this Posting List, while having acquired a read lock.
 // So, please keep this iteration cheap, otherwise mutations would get stuck.
 // The iteration will start after the provided UID. The results would not include this UID.
@@ -492,6 +655,7 @@ func (l *List) commit() (committed bool, rerr error) {
 
 	if len(l.mlayer) == 0 {
 		atomic.StoreInt64(&l.dirtyTs, 0)
+		markDirty(l.key, false)
 		return false, nil
 	}
 
@@ -528,6 +692,7 @@ func (l *List) commit() (committed bool, rerr error) {
 	atomic.StorePointer(&l.pbuffer, nil) // Make prev buffer eligible for GC.
 	atomic.StoreInt64(&l.dirtyTs, 0)     // Set as clean.
 	l.mlayer = l.mlayer[:0]
+	markDirty(l.key, false)
 	l.lastCompact = time.Now()
 	return true, nil
 }
@@ -551,11 +716,18 @@ func (l *List) Uids(opt ListOptions) *task.List {
 		if p.Uid == math.MaxUint64 {
 			return false
 		}
+		if len(p.Lang) > 0 {
+			// A value in some language, not an edge.
+			return true
+		}
 		uid := p.Uid
 		if opt.Intersect != nil {
 			for ; intersectIdx < len(opt.Intersect.Uids) && opt.Intersect.Uids[intersectIdx] < uid; intersectIdx++ {
 			}
-			if intersectIdx >= opt.Intersect.Size() || opt.Intersect.Uids[intersectIdx] > uid {
+			if intersectIdx >= len(opt.Intersect.Uids) {
+				return false
+			}
+			if opt.Intersect.Uids[intersectIdx] > uid {
 				return true
 			}
 		}
@@ -565,6 +737,29 @@ func (l *List) Uids(opt ListOptions) *task.List {
 	return &task.List{Uids: result}
 }
 
+// HasAnyUid returns true if the list has an edge to any of the given sorted
+// UIDs. It seeks to each of them instead of walking the whole list.
+func (l *List) HasAnyUid(uids []uint64) bool {
+	l.wg.Wait()
+	l.RLock()
+	defer l.RUnlock()
+
+	for _, uid := range uids {
+		if uid == 0 {
+			continue
+		}
+		var found bool
+		l.iterate(uid-1, func(p *types.Posting) bool {
+			found = p.Uid == uid && len(p.Lang) == 0
+			return false
+		})
+		if found {
+			return true
+		}
+	}
+	return false
+}
+
 func (l *List) Value() (val []byte, vtype byte, rerr error) {
 	l.wg.Wait()
 	l.RLock()
@@ -586,3 +781,69 @@ func (l *List) Value() (val []byte, vtype byte, rerr error) {
 	}
 	return val, vtype, nil
 }
+
+// Langs returns the languages of the values in the list, with "" standing for
+// the value without a language.
+func (l *List) Langs() []string {
+	l.wg.Wait()
+	l.RLock()
+	defer l.RUnlock()
+
+	var langs []string
+	l.iterate(0, func(p *types.Posting) bool {
+		if p.Uid == math.MaxUint64 {
+			langs = append(langs, "")
+		} else if len(p.Lang) > 0 {
+			langs = append(langs, p.Lang)
+		}
+		return true
+	})
+	return langs
+}
+
+// ValueFor returns the value in the first of langs which there is one for.
+// "." stands for the value without a language, which is also the one
+// returned if langs is empty.
+func (l *List) ValueFor(langs []string) (val []byte, vtype byte, rerr error) {
+	if len(langs) == 0 {
+		return l.Value()
+	}
+	for _, lang := range langs {
+		if lang == "." {
+			lang = ""
+		}
+		val, vtype, rerr = l.LangValue(lang)
+		if rerr != ErrNoValue {
+			return val, vtype, rerr
+		}
+	}
+	return nil, 0, ErrNoValue
+}
+
+// LangValue returns the value in the language lang, or the value without a
+// language if lang is empty.
+func (l *List) LangValue(lang string) (val []byte, vtype byte, rerr error) {
+	if len(lang) == 0 {
+		return l.Value()
+	}
+	l.wg.Wait()
+	l.RLock()
+	defer l.RUnlock()
+
+	uid := LangUid(lang)
+	var found bool
+	l.iterate(uid-1, func(p *types.Posting) bool {
+		if p.Uid == uid && p.Lang == lang {
+			val = make([]byte, len(p.Value))
+			copy(val, p.Value)
+			vtype = byte(p.ValType)
+			found = true
+		}
+		return false
+	})
+
+	if !found {
+		return val, vtype, ErrNoValue
+	}
+	return val, vtype, nil
+}
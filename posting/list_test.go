@@ -190,6 +190,199 @@ func TestAddMutation_Value(t *testing.T) {
 	checkValue(t, ol, "119")
 }
 
+func TestAddMutation_facets(t *testing.T) {
+	ol := getNew()
+	key := Key(10, "friend")
+	dir, err := ioutil.TempDir("", "storetest_")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	ps, err := store.NewStore(dir)
+	require.NoError(t, err)
+	ol.init(key, ps)
+
+	edge := &task.DirectedEdge{
+		ValueId: 9,
+		Label:   "testing",
+		Facets:  []*task.Facet{{Key: "since", Value: []byte("2014")}},
+	}
+	addMutation(t, ol, edge, Set)
+	_, err = ol.CommitIfDirty(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "2014", string(getFirst(ol).Facets[0].Value))
+
+	// Setting the same edge with the same facets does nothing.
+	mutated, err := ol.AddMutation(context.Background(), edge, Set)
+	require.NoError(t, err)
+	require.False(t, mutated)
+
+	// New facets replace the old ones.
+	edge.Facets = []*task.Facet{{Key: "since", Value: []byte("2015")}}
+	mutated, err = ol.AddMutation(context.Background(), edge, Set)
+	require.NoError(t, err)
+	require.True(t, mutated)
+	require.Equal(t, "2015", string(getFirst(ol).Facets[0].Value))
+
+	// The facets don't need to match to delete the edge.
+	edge.Facets = nil
+	addMutation(t, ol, edge, Del)
+	require.Empty(t, listToArray(t, 0, ol))
+}
+
+func TestAddMutation_predicates(t *testing.T) {
+	ol := getNew()
+	key := Key(10, PredicateAttr)
+	dir, err := ioutil.TempDir("", "storetest_")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	ps, err := store.NewStore(dir)
+	require.NoError(t, err)
+	ol.init(key, ps)
+
+	// Unlike other values, all of the predicates are kept.
+	for _, attr := range []string{"name", "friend", "name"} {
+		edge := &task.DirectedEdge{
+			Value: []byte(attr),
+			Label: "testing",
+			Attr:  PredicateAttr,
+		}
+		_, err := ol.AddMutation(context.Background(), edge, Set)
+		require.NoError(t, err)
+	}
+	var attrs []string
+	ol.Iterate(0, func(p *types.Posting) bool {
+		require.Equal(t, PredicateUid(string(p.Value)), p.Uid)
+		attrs = append(attrs, string(p.Value))
+		return true
+	})
+	require.Len(t, attrs, 2)
+	require.Contains(t, attrs, "name")
+	require.Contains(t, attrs, "friend")
+
+	// Another predicate with the same fingerprint takes the next free uid.
+	ol.Lock()
+	ol.updateMutationLayer(newPosting(&task.DirectedEdge{
+		Value:   []byte("other"),
+		ValueId: PredicateUid("age"),
+		Attr:    PredicateAttr,
+	}, Set))
+	ol.Unlock()
+	edge := &task.DirectedEdge{
+		Value: []byte("age"),
+		Attr:  PredicateAttr,
+	}
+	_, err = ol.AddMutation(context.Background(), edge, Set)
+	require.NoError(t, err)
+	ol.Iterate(PredicateUid("age"), func(p *types.Posting) bool {
+		require.Equal(t, PredicateUid("age")+1, p.Uid)
+		require.Equal(t, "age", string(p.Value))
+		return false
+	})
+
+	// The predicates are deleted by their names, and found past the
+	// predicates with the same fingerprint.
+	for _, attr := range []string{"age", "name", "unknown"} {
+		edge := &task.DirectedEdge{
+			Value: []byte(attr),
+			Attr:  PredicateAttr,
+		}
+		_, err := ol.AddMutation(context.Background(), edge, Del)
+		require.NoError(t, err)
+	}
+	attrs = attrs[:0]
+	ol.Iterate(0, func(p *types.Posting) bool {
+		attrs = append(attrs, string(p.Value))
+		return true
+	})
+	require.Len(t, attrs, 2)
+	require.Contains(t, attrs, "other")
+	require.Contains(t, attrs, "friend")
+}
+
+func TestAddMutation_langs(t *testing.T) {
+	ol := getNew()
+	key := Key(10, "name")
+	dir, err := ioutil.TempDir("", "storetest_")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	ps, err := store.NewStore(dir)
+	require.NoError(t, err)
+	ol.init(key, ps)
+
+	// Each language has a value of its own, apart from the one without.
+	for _, e := range []struct{ lang, val string }{
+		{"", "Paris"}, {"fr", "Paris, France"}, {"de", "Paris, Frankreich"},
+		{"fr", "Paris"},
+	} {
+		edge := &task.DirectedEdge{
+			Value: []byte(e.val),
+			Label: "testing",
+			Lang:  e.lang,
+		}
+		_, err := ol.AddMutation(context.Background(), edge, Set)
+		require.NoError(t, err)
+	}
+	val, _, err := ol.Value()
+	require.NoError(t, err)
+	require.Equal(t, "Paris", string(val))
+	val, _, err = ol.ValueFor([]string{"fr"})
+	require.NoError(t, err)
+	require.Equal(t, "Paris", string(val))
+	val, _, err = ol.ValueFor([]string{"es", "de", "fr"})
+	require.NoError(t, err)
+	require.Equal(t, "Paris, Frankreich", string(val))
+	val, _, err = ol.ValueFor([]string{"es", "."})
+	require.NoError(t, err)
+	require.Equal(t, "Paris", string(val))
+	_, _, err = ol.ValueFor([]string{"es"})
+	require.Equal(t, ErrNoValue, err)
+
+	// The values in a language aren't edges.
+	require.Empty(t, ol.Uids(ListOptions{}).Uids)
+
+	edge := &task.DirectedEdge{
+		Value: []byte("Paris"),
+		Label: "testing",
+		Lang:  "fr",
+	}
+	_, err = ol.AddMutation(context.Background(), edge, Del)
+	require.NoError(t, err)
+	_, _, err = ol.LangValue("fr")
+	require.Equal(t, ErrNoValue, err)
+	val, _, err = ol.ValueFor(nil)
+	require.NoError(t, err)
+	require.Equal(t, "Paris", string(val))
+}
+
+func TestHasAnyUid(t *testing.T) {
+	ol := getNew()
+	key := Key(10, "friend")
+	dir, err := ioutil.TempDir("", "storetest_")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	ps, err := store.NewStore(dir)
+	require.NoError(t, err)
+	ol.init(key, ps)
+
+	for _, uid := range []uint64{2, 5, 9} {
+		addMutation(t, ol, &task.DirectedEdge{ValueId: uid, Label: "test"}, Set)
+	}
+	_, err = ol.CommitIfDirty(context.Background())
+	require.NoError(t, err)
+	addMutation(t, ol, &task.DirectedEdge{ValueId: 7, Label: "test"}, Set)
+	addMutation(t, ol, &task.DirectedEdge{ValueId: 5, Label: "test"}, Del)
+
+	require.True(t, ol.HasAnyUid([]uint64{1, 2}))
+	require.True(t, ol.HasAnyUid([]uint64{6, 7}))
+	require.True(t, ol.HasAnyUid([]uint64{9}))
+	require.False(t, ol.HasAnyUid([]uint64{5}))
+	require.False(t, ol.HasAnyUid([]uint64{1, 3, 8, 10}))
+	require.False(t, ol.HasAnyUid(nil))
+}
+
 func TestAddMutation_jchiu1(t *testing.T) {
 	ol := getNew()
 	key := Key(10, "value")
@@ -804,6 +997,78 @@ func TestAfterUIDCountWithCommit(t *testing.T) {
 	require.EqualValues(t, 0, ol.Length(300))
 }
 
+func TestUidsIntersect(t *testing.T) {
+	ol := getNew()
+	key := Key(10, "value")
+	dir, err := ioutil.TempDir("", "storetest_")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	ps, err := store.NewStore(dir)
+	require.NoError(t, err)
+	ol.init(key, ps)
+
+	edge := &task.DirectedEdge{
+		Label: "jchiu",
+	}
+	for i := 100; i < 110; i++ {
+		edge.ValueId = uint64(i)
+		addMutation(t, ol, edge, Set)
+	}
+	// The UIDs past the end of the list to intersect with are left out.
+	opts := ListOptions{Intersect: &task.List{Uids: []uint64{50, 103, 105}}}
+	require.Equal(t, []uint64{103, 105}, ol.Uids(opts).Uids)
+}
+
+func TestUidsWithAttr(t *testing.T) {
+	dir, err := ioutil.TempDir("", "storetest_")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	ps, err := store.NewStore(dir)
+	require.NoError(t, err)
+	defer ps.Close()
+	Init(ps)
+
+	edge := func(uid uint64) *task.DirectedEdge {
+		return &task.DirectedEdge{ValueId: 100, Label: "testing", Attr: "friend", Entity: uid}
+	}
+	// Lists committed to the store, which aren't in memory.
+	for _, uid := range []uint64{5, 7, 9} {
+		ol := getNew()
+		ol.init(Key(uid, "friend"), ps)
+		addMutation(t, ol, edge(uid), Set)
+		if uid == 7 {
+			addMutation(t, ol, edge(uid), Del)
+		}
+		_, err := ol.CommitIfDirty(context.Background())
+		require.NoError(t, err)
+	}
+	// Lists in memory, with mutations which aren't committed.
+	l, decr := GetOrCreate(Key(3, "friend"))
+	addMutation(t, l, edge(3), Set)
+	decr()
+	l, decr = GetOrCreate(Key(9, "friend"))
+	addMutation(t, l, edge(9), Del)
+	decr()
+	l, decr = GetOrCreate(Key(4, "friends"))
+	addMutation(t, l, edge(4), Set)
+	decr()
+
+	uids, err := UidsWithAttr("friend")
+	require.NoError(t, err)
+	require.Equal(t, []uint64{3, 5}, uids.Uids)
+
+	// Once committed, the lists are found in the store.
+	l, decr = GetOrCreate(Key(3, "friend"))
+	_, err = l.CommitIfDirty(context.Background())
+	require.NoError(t, err)
+	decr()
+	require.NotContains(t, dirtyUids.m["friend|"], uint64(3))
+	uids, err = UidsWithAttr("friend")
+	require.NoError(t, err)
+	require.Equal(t, []uint64{3, 5}, uids.Uids)
+}
+
 func TestMain(m *testing.M) {
 	x.Init()
 	os.Exit(m.Run())
@@ -18,6 +18,7 @@ package posting
 
 import (
 	"context"
+	"encoding/binary"
 	"flag"
 	"fmt"
 	"io/ioutil"
@@ -34,7 +35,10 @@ import (
 
 	"github.com/dgryski/go-farm"
 
+	"github.com/dgraph-io/dgraph/algo"
+	"github.com/dgraph-io/dgraph/posting/types"
 	"github.com/dgraph-io/dgraph/store"
+	"github.com/dgraph-io/dgraph/task"
 )
 
 var (
@@ -359,3 +363,104 @@ func MergeLists(numRoutines int) {
 	close(workChan)
 	wg.Wait()
 }
+
+// dirtyUids holds, for the key prefix of each attribute, the UIDs of the
+// lists with mutations which aren't committed yet, as they might not be in
+// the store.
+var dirtyUids = struct {
+	sync.Mutex
+	m map[string]map[uint64]struct{}
+}{m: make(map[string]map[uint64]struct{})}
+
+// markDirty adds the list of key to dirtyUids, or removes it once it's
+// committed. Index keys are left out.
+func markDirty(key []byte, dirty bool) {
+	if len(key) < 9 || key[len(key)-9] != '|' {
+		return
+	}
+	prefix := string(key[:len(key)-8])
+	uid := binary.BigEndian.Uint64(key[len(key)-8:])
+
+	dirtyUids.Lock()
+	defer dirtyUids.Unlock()
+	uids := dirtyUids.m[prefix]
+	if dirty {
+		if uids == nil {
+			uids = make(map[uint64]struct{})
+			dirtyUids.m[prefix] = uids
+		}
+		uids[uid] = struct{}{}
+		return
+	}
+	delete(uids, uid)
+	if len(uids) == 0 {
+		delete(dirtyUids.m, prefix)
+	}
+}
+
+// UidsWithAttr returns the UIDs which have a non-empty posting list for attr,
+// for has(attr). The keys of attr are read from the store, and the lists of
+// those which are in memory are read instead, as they might have mutations
+// which aren't committed yet. So are the dirty lists which aren't in the
+// store yet.
+func UidsWithAttr(attr string) (*task.List, error) {
+	prefix := Key(0, attr)[:len(attr)+1]
+	uidOf := func(key []byte) uint64 {
+		return binary.BigEndian.Uint64(key[len(prefix):])
+	}
+	// inMemory returns whether the list of key has any postings, and whether
+	// it's in memory at all.
+	inMemory := func(key []byte) (bool, bool) {
+		l := getFromMap(farm.Fingerprint64(key))
+		if l == nil {
+			return false, false
+		}
+		defer l.decr()
+		l.RLock()
+		defer l.RUnlock()
+		return l.Length(0) > 0, true
+	}
+
+	dirtyUids.Lock()
+	dirty := make([]uint64, 0, len(dirtyUids.m[string(prefix)]))
+	for uid := range dirtyUids.m[string(prefix)] {
+		dirty = append(dirty, uid)
+	}
+	dirtyUids.Unlock()
+
+	out := new(task.List)
+	seen := make(map[uint64]bool)
+	it := pstore.NewIterator()
+	defer it.Close()
+	for it.Seek(prefix); it.ValidForPrefix(prefix); it.Next() {
+		key := it.Key().Data()
+		if len(key) != len(prefix)+8 {
+			continue
+		}
+		uid := uidOf(key)
+		seen[uid] = true
+		if has, ok := inMemory(key); ok {
+			if has {
+				out.Uids = append(out.Uids, uid)
+			}
+			continue
+		}
+		var pl types.PostingList
+		if err := pl.Unmarshal(it.Value().Data()); err != nil {
+			return nil, err
+		}
+		if len(pl.Postings) > 0 {
+			out.Uids = append(out.Uids, uid)
+		}
+	}
+	for _, uid := range dirty {
+		if seen[uid] {
+			continue
+		}
+		if has, _ := inMemory(Key(uid, attr)); has {
+			out.Uids = append(out.Uids, uid)
+		}
+	}
+	algo.Sort(out)
+	return out, nil
+}
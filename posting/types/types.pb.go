@@ -17,6 +17,7 @@ package types
 import proto "github.com/golang/protobuf/proto"
 import fmt "fmt"
 import math "math"
+import task "github.com/dgraph-io/dgraph/task"
 
 import io "io"
 
@@ -32,11 +33,13 @@ var _ = math.Inf
 const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
 
 type Posting struct {
-	Uid     uint64 `protobuf:"fixed64,1,opt,name=uid,proto3" json:"uid,omitempty"`
-	Value   []byte `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
-	ValType uint32 `protobuf:"varint,3,opt,name=valType,proto3" json:"valType,omitempty"`
-	Label   string `protobuf:"bytes,4,opt,name=label,proto3" json:"label,omitempty"`
-	Commit  uint64 `protobuf:"varint,5,opt,name=commit,proto3" json:"commit,omitempty"`
+	Uid     uint64        `protobuf:"fixed64,1,opt,name=uid,proto3" json:"uid,omitempty"`
+	Value   []byte        `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+	ValType uint32        `protobuf:"varint,3,opt,name=valType,proto3" json:"valType,omitempty"`
+	Label   string        `protobuf:"bytes,4,opt,name=label,proto3" json:"label,omitempty"`
+	Commit  uint64        `protobuf:"varint,5,opt,name=commit,proto3" json:"commit,omitempty"`
+	Facets  []*task.Facet `protobuf:"bytes,6,rep,name=facets" json:"facets,omitempty"`
+	Lang    string        `protobuf:"bytes,7,opt,name=lang,proto3" json:"lang,omitempty"`
 	// op is only used temporarily.
 	Op uint32 `protobuf:"varint,12,opt,name=op,proto3" json:"op,omitempty"`
 }
@@ -46,6 +49,13 @@ func (m *Posting) String() string            { return proto.CompactTextString(m)
 func (*Posting) ProtoMessage()               {}
 func (*Posting) Descriptor() ([]byte, []int) { return fileDescriptorTypes, []int{0} }
 
+func (m *Posting) GetFacets() []*task.Facet {
+	if m != nil {
+		return m.Facets
+	}
+	return nil
+}
+
 type PostingList struct {
 	Postings []*Posting `protobuf:"bytes,1,rep,name=postings" json:"postings,omitempty"`
 	Checksum []byte     `protobuf:"bytes,2,opt,name=checksum,proto3" json:"checksum,omitempty"`
@@ -110,11 +120,29 @@ func (m *Posting) MarshalTo(data []byte) (int, error) {
 		i++
 		i = encodeVarintTypes(data, i, uint64(m.Commit))
 	}
+	if len(m.Facets) > 0 {
+		for _, msg := range m.Facets {
+			data[i] = 0x32
+			i++
+			i = encodeVarintTypes(data, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(data[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
 	if m.Op != 0 {
 		data[i] = 0x60
 		i++
 		i = encodeVarintTypes(data, i, uint64(m.Op))
 	}
+	if len(m.Lang) > 0 {
+		data[i] = 0x3a
+		i++
+		i = encodeVarintTypes(data, i, uint64(len(m.Lang)))
+		i += copy(data[i:], m.Lang)
+	}
 	return i, nil
 }
 
@@ -206,9 +234,19 @@ func (m *Posting) Size() (n int) {
 	if m.Commit != 0 {
 		n += 1 + sovTypes(uint64(m.Commit))
 	}
+	if len(m.Facets) > 0 {
+		for _, e := range m.Facets {
+			l = e.Size()
+			n += 1 + l + sovTypes(uint64(l))
+		}
+	}
 	if m.Op != 0 {
 		n += 1 + sovTypes(uint64(m.Op))
 	}
+	l = len(m.Lang)
+	if l > 0 {
+		n += 1 + l + sovTypes(uint64(l))
+	}
 	return n
 }
 
@@ -388,6 +426,37 @@ func (m *Posting) Unmarshal(data []byte) error {
 					break
 				}
 			}
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Facets", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthTypes
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Facets = append(m.Facets, &task.Facet{})
+			if err := m.Facets[len(m.Facets)-1].Unmarshal(data[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
 		case 12:
 			if wireType != 0 {
 				return fmt.Errorf("proto: wrong wireType = %d for field Op", wireType)
@@ -407,6 +476,35 @@ func (m *Posting) Unmarshal(data []byte) error {
 					break
 				}
 			}
+		case 7:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Lang", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTypes
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Lang = string(data[iNdEx:postIndex])
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipTypes(data[iNdEx:])
@@ -667,20 +765,23 @@ var (
 func init() { proto.RegisterFile("types/types.proto", fileDescriptorTypes) }
 
 var fileDescriptorTypes = []byte{
-	// 236 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0xe2, 0x12, 0x2c, 0xa9, 0x2c, 0x48,
-	0x2d, 0xd6, 0x07, 0x93, 0x7a, 0x05, 0x45, 0xf9, 0x25, 0xf9, 0x42, 0xac, 0x60, 0x8e, 0x52, 0x27,
-	0x23, 0x17, 0x7b, 0x40, 0x7e, 0x71, 0x49, 0x66, 0x5e, 0xba, 0x90, 0x00, 0x17, 0x73, 0x69, 0x66,
-	0x8a, 0x04, 0xa3, 0x02, 0xa3, 0x06, 0x5b, 0x10, 0x88, 0x29, 0x24, 0xc2, 0xc5, 0x5a, 0x96, 0x98,
-	0x53, 0x9a, 0x2a, 0xc1, 0xa4, 0xc0, 0xa8, 0xc1, 0x13, 0x04, 0xe1, 0x08, 0x49, 0x70, 0xb1, 0x97,
-	0x25, 0xe6, 0x84, 0x54, 0x16, 0xa4, 0x4a, 0x30, 0x2b, 0x30, 0x6a, 0xf0, 0x06, 0xc1, 0xb8, 0x20,
-	0xf5, 0x39, 0x89, 0x49, 0xa9, 0x39, 0x12, 0x2c, 0x0a, 0x8c, 0x1a, 0x9c, 0x41, 0x10, 0x8e, 0x90,
-	0x18, 0x17, 0x5b, 0x72, 0x7e, 0x6e, 0x6e, 0x66, 0x89, 0x04, 0xab, 0x02, 0xa3, 0x06, 0x4b, 0x10,
-	0x94, 0x27, 0xc4, 0xc7, 0xc5, 0x94, 0x5f, 0x20, 0xc1, 0x03, 0x36, 0x82, 0x29, 0xbf, 0x40, 0x29,
-	0x97, 0x8b, 0x1b, 0xea, 0x14, 0x9f, 0xcc, 0xe2, 0x12, 0x21, 0x2d, 0x2e, 0x8e, 0x02, 0x08, 0xb7,
-	0x58, 0x82, 0x51, 0x81, 0x59, 0x83, 0xdb, 0x88, 0x4f, 0x0f, 0xe2, 0x03, 0xa8, 0xaa, 0x20, 0xb8,
-	0xbc, 0x90, 0x14, 0x17, 0x47, 0x72, 0x46, 0x6a, 0x72, 0x76, 0x71, 0x69, 0x2e, 0xd4, 0xad, 0x70,
-	0x3e, 0x92, 0xf5, 0xcc, 0xc8, 0xd6, 0x3b, 0x09, 0x9c, 0x78, 0x24, 0xc7, 0x78, 0xe1, 0x91, 0x1c,
-	0xe3, 0x83, 0x47, 0x72, 0x8c, 0x33, 0x1e, 0xcb, 0x31, 0x24, 0xb1, 0x81, 0x83, 0xc6, 0x18, 0x10,
-	0x00, 0x00, 0xff, 0xff, 0x44, 0xae, 0x9f, 0x23, 0x2f, 0x01, 0x00, 0x00,
+	// 273 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x4c, 0x90, 0x4d, 0x4e, 0xc3, 0x30,
+	0x10, 0x85, 0x71, 0x7e, 0xcb, 0xa4, 0x54, 0x65, 0x84, 0x90, 0xd5, 0x45, 0x64, 0x95, 0x8d, 0xc5,
+	0x22, 0x48, 0x70, 0x03, 0x16, 0xac, 0x58, 0x20, 0x8b, 0x0b, 0xb8, 0xc1, 0x94, 0xa8, 0x49, 0x6d,
+	0x61, 0xa7, 0x52, 0x6f, 0xc2, 0x79, 0x58, 0xb1, 0xe4, 0x08, 0x28, 0x5c, 0x04, 0xc5, 0x09, 0x55,
+	0x37, 0xd6, 0x7c, 0x33, 0xa3, 0xf1, 0x7b, 0x0f, 0xce, 0xdd, 0xde, 0x28, 0x7b, 0xe3, 0xdf, 0xc2,
+	0xbc, 0x6b, 0xa7, 0x31, 0xf6, 0xb0, 0x00, 0x27, 0xed, 0x66, 0x68, 0x2d, 0x3f, 0x09, 0xa4, 0x4f,
+	0xda, 0xba, 0x6a, 0xbb, 0xc6, 0x39, 0x84, 0x6d, 0xf5, 0x42, 0x09, 0x23, 0x3c, 0x11, 0x7d, 0x89,
+	0x17, 0x10, 0xef, 0x64, 0xdd, 0x2a, 0x1a, 0x30, 0xc2, 0xa7, 0x62, 0x00, 0xa4, 0x90, 0xee, 0x64,
+	0xfd, 0xbc, 0x37, 0x8a, 0x86, 0x8c, 0xf0, 0x33, 0xf1, 0x8f, 0xfd, 0x7e, 0x2d, 0x57, 0xaa, 0xa6,
+	0x11, 0x23, 0xfc, 0x54, 0x0c, 0x80, 0x97, 0x90, 0x94, 0xba, 0x69, 0x2a, 0x47, 0x63, 0x46, 0x78,
+	0x24, 0x46, 0xc2, 0x19, 0x04, 0xda, 0xd0, 0xa9, 0x3f, 0x11, 0x68, 0x83, 0x57, 0x90, 0xbc, 0xca,
+	0x52, 0x39, 0x4b, 0x13, 0x16, 0xf2, 0xec, 0x36, 0x2b, 0xbc, 0xd0, 0x87, 0xbe, 0x27, 0xc6, 0x11,
+	0x22, 0x44, 0xb5, 0xdc, 0xae, 0x69, 0xea, 0x7f, 0xf0, 0xf5, 0xb2, 0x81, 0x6c, 0xf4, 0xf0, 0x58,
+	0x59, 0x87, 0xd7, 0x30, 0x31, 0x03, 0x5a, 0x4a, 0xfc, 0xa5, 0x59, 0x31, 0xc4, 0x30, 0x6e, 0x89,
+	0xc3, 0x1c, 0x17, 0x30, 0x29, 0xdf, 0x54, 0xb9, 0xb1, 0x6d, 0x33, 0x9a, 0x3c, 0xf0, 0x91, 0xee,
+	0xf0, 0x58, 0xf7, 0xfd, 0xfc, 0xab, 0xcb, 0xc9, 0x77, 0x97, 0x93, 0x9f, 0x2e, 0x27, 0x1f, 0xbf,
+	0xf9, 0xc9, 0x2a, 0xf1, 0x61, 0xde, 0xfd, 0x0d, 0x00, 0x0d, 0xe7, 0xe7, 0xda, 0x74, 0x01, 0x00,
+	0x00,
 }
@@ -0,0 +1,161 @@
+/*
+ * Copyright 2016 Dgraph Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * 		http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/dgraph-io/dgraph/algo"
+	"github.com/dgraph-io/dgraph/schema"
+	"github.com/dgraph-io/dgraph/task"
+	"github.com/dgraph-io/dgraph/types"
+	"github.com/dgraph-io/dgraph/x"
+)
+
+// aggregateName returns the name under which the aggregation of sg, or its
+// number of edges for count(attr), is output.
+func (sg *SubGraph) aggregateName() string {
+	if len(sg.Params.Alias) > 0 {
+		return sg.Params.Alias
+	}
+	if sg.Params.IsCount {
+		return fmt.Sprintf("count(%s)", sg.Attr)
+	}
+	return fmt.Sprintf("%s(%s)", sg.Params.Aggregator, sg.Attr)
+}
+
+// scalarType returns the type the values of sg should be treated as. It is
+// the type from the schema if there is one, else the type of the given value.
+func (sg *SubGraph) scalarType(v types.Value) types.Scalar {
+	if st, ok := sg.Params.AttrType.(types.Scalar); ok {
+		return st
+	}
+	if st, ok := schema.TypeOf(sg.Attr).(types.Scalar); ok {
+		return st
+	}
+	return v.Type()
+}
+
+// aggregate computes the aggregation function of sg over the values of the
+// UIDs in ul. It returns nil if none of the UIDs have a value.
+func (sg *SubGraph) aggregate(ul *task.List) (types.Value, error) {
+	var vals []types.Value
+	for _, uid := range ul.Uids {
+		idx := algo.IndexOf(sg.SrcUIDs, uid)
+		if idx < 0 {
+			continue
+		}
+		tv := sg.values[idx]
+		if bytes.Equal(tv.Val, nil) {
+			continue
+		}
+		v, err := getValue(tv)
+		if err != nil {
+			return nil, err
+		}
+		vals = append(vals, v)
+	}
+	if len(vals) == 0 {
+		return nil, nil
+	}
+
+	typ := sg.scalarType(vals[0])
+	switch sg.Params.Aggregator {
+	case "min", "max":
+		// Convert the values to a common type and sort them.
+		var cvals []types.Value
+		for _, v := range vals {
+			cv, err := typ.Convert(v)
+			if err != nil {
+				// Skip values that don't convert.
+				continue
+			}
+			cvals = append(cvals, cv)
+		}
+		if len(cvals) == 0 {
+			return nil, nil
+		}
+		if err := typ.Sort(cvals, &task.List{Uids: make([]uint64, len(cvals))}); err != nil {
+			return nil, err
+		}
+		if sg.Params.Aggregator == "min" {
+			return cvals[0], nil
+		}
+		return cvals[len(cvals)-1], nil
+
+	case "sum", "avg":
+		if typ.ID() != types.Int32ID && typ.ID() != types.FloatID &&
+			typ.ID() != types.StringID && typ.ID() != types.BytesID {
+			return nil, x.Errorf("Cannot compute %s over predicate %s of type %s",
+				sg.Params.Aggregator, sg.Attr, typ)
+		}
+		floatType, _ := types.TypeForName("float")
+		var sum float64
+		var n int
+		for _, v := range vals {
+			fv, err := floatType.(types.Scalar).Convert(v)
+			if err != nil {
+				// Skip values that don't convert.
+				continue
+			}
+			sum += float64(*fv.(*types.Float))
+			n++
+		}
+		if n == 0 {
+			return nil, nil
+		}
+		if sg.Params.Aggregator == "avg" {
+			res := types.Float(sum / float64(n))
+			return &res, nil
+		}
+		if typ.ID() == types.Int32ID {
+			res := types.Int32(sum)
+			return &res, nil
+		}
+		res := types.Float(sum)
+		return &res, nil
+	}
+	return nil, x.Errorf("Unknown aggregation function: %s", sg.Params.Aggregator)
+}
+
+// addAggregations adds a node holding the aggregated values of the children of
+// sg over the UIDs in ul to dst, if sg has any aggregation children.
+func (sg *SubGraph) addAggregations(ul *task.List, attr string,
+	dst outputNode) error {
+	var uc outputNode
+	for _, pc := range sg.Children {
+		if len(pc.Params.Aggregator) == 0 {
+			continue
+		}
+		v, err := pc.aggregate(ul)
+		if err != nil {
+			return err
+		}
+		if v == nil {
+			continue
+		}
+		if uc == nil {
+			uc = dst.New(attr)
+		}
+		uc.AddValue(pc.aggregateName(), v)
+	}
+	if uc != nil {
+		dst.AddChild(attr, uc)
+	}
+	return nil
+}
@@ -0,0 +1,168 @@
+/*
+ * Copyright 2016 Dgraph Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * 		http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dgraph-io/dgraph/task"
+)
+
+// planStats records how a SubGraph was processed, to explain slow queries.
+// Each SubGraph only writes its own stats, from the goroutine processing it.
+type planStats struct {
+	query      *task.Query   // The task query sent, if any.
+	group      uint32        // The group the task query was sent to.
+	took       time.Duration // Time taken by the task query.
+	fetched    int           // Number of UIDs got, before filters and pagination.
+	filterTook time.Duration // Time taken by the filters.
+	sort       string        // How the UIDs were sorted and paginated.
+	sortTook   time.Duration
+}
+
+// planNode is the JSON form of a SubGraph and its stats.
+type planNode struct {
+	Attr        string      `json:"attr,omitempty"`
+	Alias       string      `json:"alias,omitempty"`
+	Func        []string    `json:"func,omitempty"`
+	FilterOp    string      `json:"filter_op,omitempty"`
+	Task        *planTask   `json:"task,omitempty"`
+	UidsIn      int         `json:"uids_in"`
+	UidsFetched int         `json:"uids_fetched"`
+	UidsOut     int         `json:"uids_out"`
+	FilterTime  string      `json:"filter_time,omitempty"`
+	Sort        string      `json:"sort,omitempty"`
+	SortTime    string      `json:"sort_time,omitempty"`
+	Filters     []*planNode `json:"filters,omitempty"`
+	Children    []*planNode `json:"children,omitempty"`
+}
+
+// planTask describes the task query sent for a SubGraph.
+type planTask struct {
+	Group    uint32   `json:"group"`
+	Uids     int      `json:"uids"`
+	Func     []string `json:"func,omitempty"`
+	Count    int32    `json:"count,omitempty"`
+	Offset   int32    `json:"offset,omitempty"`
+	AfterUid uint64   `json:"after_uid,omitempty"`
+	DoCount  bool     `json:"do_count,omitempty"`
+	Langs    []string `json:"langs,omitempty"`
+	Facets   bool     `json:"facets,omitempty"`
+	Time     string   `json:"time"`
+}
+
+// sortStrategy returns how sg is sorted and paginated, to be recorded in its
+// stats.
+func (sg *SubGraph) sortStrategy() string {
+	p := sg.Params
+	if p.Facets != nil && len(p.Facets.OrderKey) > 0 {
+		return fmt.Sprintf("facet(%s%s)", p.Facets.OrderKey, descSuffix(p.Facets.OrderDesc))
+	}
+	if len(p.Order) == 0 {
+		if p.Count == 0 && p.Offset == 0 && p.AfterUID == 0 {
+			return ""
+		}
+		return "pagination"
+	}
+	var keys []string
+	for _, o := range p.Order {
+		keys = append(keys, o.Attr+descSuffix(o.Desc))
+	}
+	if sg.mathChild(p.Order[0].Attr) != nil {
+		// The values of math() are computed and sorted here.
+		return fmt.Sprintf("values(%s)", strings.Join(keys, ", "))
+	}
+	s := fmt.Sprintf("index(%s%s)", p.Order[0].Attr, descSuffix(p.Order[0].Desc))
+	if len(p.Order) == 1 && p.Count >= 0 {
+		return s
+	}
+	return fmt.Sprintf("%s then values(%s)", s, strings.Join(keys, ", "))
+}
+
+func descSuffix(desc bool) string {
+	if desc {
+		return " desc"
+	}
+	return ""
+}
+
+// plan returns the plan of sg, along with those of its filters and children.
+func (sg *SubGraph) plan() *planNode {
+	st := sg.stats
+	n := &planNode{
+		Attr:        sg.Attr,
+		Alias:       sg.Params.Alias,
+		Func:        sg.SrcFunc,
+		FilterOp:    sg.FilterOp,
+		UidsIn:      listLen(sg.SrcUIDs),
+		UidsFetched: st.fetched,
+		UidsOut:     listLen(sg.DestUIDs),
+		Sort:        st.sort,
+	}
+	if q := st.query; q != nil {
+		n.Task = &planTask{
+			Group:    st.group,
+			Uids:     len(q.Uids),
+			Func:     q.SrcFunc,
+			Count:    q.Count,
+			Offset:   q.Offset,
+			AfterUid: q.AfterUid,
+			DoCount:  q.DoCount,
+			Langs:    q.Langs,
+			Facets:   q.FacetParam != nil,
+			Time:     st.took.String(),
+		}
+	}
+	if len(sg.Filters) > 0 {
+		n.FilterTime = st.filterTook.String()
+	}
+	if len(st.sort) > 0 {
+		n.SortTime = st.sortTook.String()
+	}
+	for _, f := range sg.Filters {
+		n.Filters = append(n.Filters, f.plan())
+	}
+	for _, c := range sg.Children {
+		n.Children = append(n.Children, c.plan())
+	}
+	return n
+}
+
+func listLen(l *task.List) int {
+	if l == nil {
+		return 0
+	}
+	return len(l.Uids)
+}
+
+// Explain returns the plans of the processed SubGraphs of all the query blocks
+// in a request as JSON, along with the latency. A plan has the task queries
+// sent, the groups they went to, the number of UIDs at each level, and the
+// time taken by filtering and sorting.
+func Explain(l *Latency, sgl []*SubGraph) ([]byte, error) {
+	plans := make([]*planNode, 0, len(sgl))
+	for _, sg := range sgl {
+		plans = append(plans, sg.plan())
+	}
+	return json.Marshal(map[string]interface{}{
+		"explain":        plans,
+		"server_latency": l.ToMap(),
+	})
+}
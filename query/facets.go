@@ -0,0 +1,303 @@
+/*
+ * Copyright 2016 Dgraph Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * 		http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+import (
+	"bytes"
+	"math"
+	"sort"
+
+	"github.com/dgraph-io/dgraph/algo"
+	"github.com/dgraph-io/dgraph/gql"
+	"github.com/dgraph-io/dgraph/task"
+	"github.com/dgraph-io/dgraph/types"
+	"github.com/dgraph-io/dgraph/x"
+)
+
+// valueFacetsKey is the UID under which the facets on a value are kept, the
+// same as for the value itself in the posting lists.
+const valueFacetsKey = math.MaxUint64
+
+// facetParams returns the facets to fetch for sg, which are the ones asked for
+// by @facets, along with the ones needed to filter and sort the edges.
+func facetParams(sg *SubGraph) *task.FacetParams {
+	f, ft := sg.Params.Facets, sg.Params.FacetsFilter
+	if f == nil && ft == nil {
+		return nil
+	}
+	if f != nil && len(f.Keys) == 0 {
+		return &task.FacetParams{AllKeys: true}
+	}
+	keys := make(map[string]bool)
+	if f != nil {
+		for _, k := range f.Keys {
+			keys[k] = true
+		}
+		if len(f.OrderKey) > 0 {
+			keys[f.OrderKey] = true
+		}
+	}
+	if ft != nil {
+		filterKeys(ft, keys)
+	}
+	out := &task.FacetParams{}
+	for k := range keys {
+		out.Keys = append(out.Keys, k)
+	}
+	sort.Strings(out.Keys)
+	return out
+}
+
+// filterKeys adds the facets used by the filter ft to keys.
+func filterKeys(ft *gql.FilterTree, keys map[string]bool) {
+	if ft.Func != nil {
+		keys[ft.Func.Attr] = true
+	}
+	for _, c := range ft.Child {
+		filterKeys(c, keys)
+	}
+}
+
+// setFacets records the facets on the edges from each of the source UIDs.
+// They are kept by the UID the edge points to, as the lists get modified by
+// filtering, sorting and pagination.
+func (sg *SubGraph) setFacets(matrix []*task.FacetsList) {
+	sg.facets = make([]map[uint64]*task.Facets, len(matrix))
+	for i, fl := range matrix {
+		m := make(map[uint64]*task.Facets, len(fl.FacetsList))
+		if len(sg.uidMatrix[i].Uids) == 0 {
+			if len(fl.FacetsList) > 0 {
+				m[valueFacetsKey] = fl.FacetsList[0]
+			}
+		} else {
+			for j, uid := range sg.uidMatrix[i].Uids {
+				m[uid] = fl.FacetsList[j]
+			}
+		}
+		sg.facets[i] = m
+	}
+}
+
+// edgeFacets returns the facets on the edge from the source UID at idx to uid.
+func (sg *SubGraph) edgeFacets(idx int, uid uint64) *task.Facets {
+	if idx >= len(sg.facets) {
+		return nil
+	}
+	return sg.facets[idx][uid]
+}
+
+// applyFacetsFilter removes the edges whose facets don't match the filter
+// given with @facets. For a value, the value itself is removed.
+func (sg *SubGraph) applyFacetsFilter() error {
+	ft := sg.Params.FacetsFilter
+	for i, ul := range sg.uidMatrix {
+		if len(ul.Uids) == 0 {
+			ok, err := matchFacets(ft, sg.edgeFacets(i, valueFacetsKey))
+			if err != nil {
+				return err
+			}
+			if !ok && i < len(sg.values) {
+				sg.values[i] = &task.Value{Val: x.Nilbyte}
+			}
+			continue
+		}
+		var rerr error
+		algo.ApplyFilter(ul, func(uid uint64, idx int) bool {
+			ok, err := matchFacets(ft, sg.edgeFacets(i, uid))
+			if err != nil {
+				rerr = err
+			}
+			return ok
+		})
+		if rerr != nil {
+			return rerr
+		}
+	}
+	return nil
+}
+
+// matchFacets returns true if the facets satisfy the filter ft. The facet
+// filters are eq, ge, gt, le and lt, combined with &&, || and not.
+func matchFacets(ft *gql.FilterTree, facets *task.Facets) (bool, error) {
+	if ft.Func == nil && ft.Op == "!" {
+		ok, err := matchFacets(ft.Child[0], facets)
+		return !ok, err
+	}
+	if ft.Func == nil {
+		for _, c := range ft.Child {
+			ok, err := matchFacets(c, facets)
+			if err != nil {
+				return false, err
+			}
+			if ok == (ft.Op == "|") {
+				return ok, nil
+			}
+		}
+		return ft.Op != "|", nil
+	}
+
+	fn := ft.Func
+	if len(fn.Args) != 1 {
+		return false, x.Errorf("Function %s on facets requires 2 arguments, but got %d",
+			fn.Name, len(fn.Args)+1)
+	}
+	switch fn.Name {
+	case "eq", "ge", "gt", "le", "lt":
+	default:
+		return false, x.Errorf("Function %s is not supported on facets", fn.Name)
+	}
+	f := findFacet(facets, fn.Attr)
+	if f == nil {
+		return false, nil
+	}
+	fv, err := facetValue(f)
+	if err != nil {
+		return false, err
+	}
+	// The argument is taken to be of the type of the facet. If it can't be
+	// converted, the facet doesn't match.
+	arg := types.ValueForType(types.TypeID(f.ValType))
+	if err := arg.UnmarshalText([]byte(fn.Args[0])); err != nil {
+		return false, nil
+	}
+	if fn.Name == "eq" {
+		b, err := arg.MarshalBinary()
+		if err != nil {
+			return false, err
+		}
+		return bytes.Equal(b, f.Value), nil
+	}
+
+	var less bool
+	if fn.Name == "ge" || fn.Name == "lt" {
+		less, err = fv.Type().Less(fv, arg)
+	} else {
+		less, err = fv.Type().Less(arg, fv)
+	}
+	if err != nil {
+		return false, err
+	}
+	if fn.Name == "lt" || fn.Name == "gt" {
+		return less, nil
+	}
+	return !less, nil
+}
+
+// findFacet returns the facet with the given key, or nil if there is none.
+func findFacet(facets *task.Facets, key string) *task.Facet {
+	if facets == nil {
+		return nil
+	}
+	fs := facets.Facets
+	i := sort.Search(len(fs), func(i int) bool { return fs[i].Key >= key })
+	if i < len(fs) && fs[i].Key == key {
+		return fs[i]
+	}
+	return nil
+}
+
+// facetValue returns the value of the facet f.
+func facetValue(f *task.Facet) (types.Value, error) {
+	return getValue(&task.Value{Val: f.Value, ValType: f.ValType})
+}
+
+// byFacet sorts UIDs by the value of a facet on their edges. The UIDs whose
+// edges don't have the facet come last.
+type byFacet struct {
+	uids   []uint64
+	values []types.Value
+	desc   bool
+}
+
+func (b byFacet) Len() int { return len(b.uids) }
+func (b byFacet) Swap(i, j int) {
+	b.uids[i], b.uids[j] = b.uids[j], b.uids[i]
+	b.values[i], b.values[j] = b.values[j], b.values[i]
+}
+func (b byFacet) Less(i, j int) bool {
+	vi, vj := b.values[i], b.values[j]
+	if vi == nil || vj == nil {
+		return vj == nil && vi != nil
+	}
+	if vi.Type().ID() != vj.Type().ID() {
+		return vi.Type().ID() < vj.Type().ID()
+	}
+	if b.desc {
+		vi, vj = vj, vi
+	}
+	return types.LessValues(vi, vj)
+}
+
+// applyFacetOrderAndPagination sorts the edges from each of the source UIDs
+// by the facet given with @facets(orderasc: ...), and then applies
+// pagination.
+func (sg *SubGraph) applyFacetOrderAndPagination() error {
+	f := sg.Params.Facets
+	for i, ul := range sg.uidMatrix {
+		algo.IntersectWith(ul, sg.DestUIDs)
+		b := byFacet{
+			uids:   ul.Uids,
+			values: make([]types.Value, len(ul.Uids)),
+			desc:   f.OrderDesc,
+		}
+		for j, uid := range ul.Uids {
+			if fc := findFacet(sg.edgeFacets(i, uid), f.OrderKey); fc != nil {
+				v, err := facetValue(fc)
+				if err != nil {
+					return err
+				}
+				b.values[j] = v
+			}
+		}
+		sort.Stable(b)
+		start, end := pageRange(&sg.Params, len(ul.Uids))
+		ul.Uids = ul.Uids[start:end]
+	}
+
+	// The lists aren't sorted by UID anymore, so we mark the UIDs which are
+	// left in sg.DestUIDs, like applyOrderAndPagination does.
+	included := make([]bool, len(sg.DestUIDs.Uids))
+	for _, ul := range sg.uidMatrix {
+		for _, uid := range ul.Uids {
+			if idx := algo.IndexOf(sg.DestUIDs, uid); idx >= 0 {
+				included[idx] = true
+			}
+		}
+	}
+	algo.ApplyFilter(sg.DestUIDs,
+		func(uid uint64, idx int) bool { return included[idx] })
+	return nil
+}
+
+// addFacets adds the facets of the edge of attr to dst, if it has any.
+func addFacets(dst outputNode, attr string, facets *task.Facets) error {
+	if facets == nil || len(facets.Facets) == 0 {
+		return nil
+	}
+	keys := make([]string, 0, len(facets.Facets))
+	vals := make([]types.Value, 0, len(facets.Facets))
+	for _, f := range facets.Facets {
+		v, err := facetValue(f)
+		if err != nil {
+			return err
+		}
+		keys = append(keys, f.Key)
+		vals = append(vals, v)
+	}
+	dst.AddFacets(attr, keys, vals)
+	return nil
+}
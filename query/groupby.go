@@ -0,0 +1,165 @@
+/*
+ * Copyright 2016 Dgraph Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * 		http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+import (
+	"bytes"
+	"sort"
+
+	"github.com/dgraph-io/dgraph/algo"
+	"github.com/dgraph-io/dgraph/gql"
+	"github.com/dgraph-io/dgraph/task"
+	"github.com/dgraph-io/dgraph/types"
+	"github.com/dgraph-io/dgraph/x"
+)
+
+// uidGroup holds the UIDs which have the same value, or point to the same UID,
+// for the predicate given with @groupby.
+type uidGroup struct {
+	uid   uint64      // Set when grouping by a UID predicate.
+	value types.Value // Set when grouping by a value.
+	uids  []uint64
+}
+
+// byGroupKey sorts groups by their UID or value.
+type byGroupKey []*uidGroup
+
+func (b byGroupKey) Len() int      { return len(b) }
+func (b byGroupKey) Swap(i, j int) { b[i], b[j] = b[j], b[i] }
+func (b byGroupKey) Less(i, j int) bool {
+	vi, vj := b[i].value, b[j].value
+	if vi == nil || vj == nil {
+		return b[i].uid < b[j].uid
+	}
+	return types.LessValues(vi, vj)
+}
+
+// setGroupBy sets up sg for the @groupby on gq. Only aggregations are allowed
+// among the children, and a child is added to fetch the predicate to group by.
+func (sg *SubGraph) setGroupBy(gq *gql.GraphQuery) error {
+	for _, c := range gq.Children {
+		if c.Func == nil || c.Func.Name == "count" {
+			return x.Errorf("Only aggregations are allowed inside @groupby on %s, got %s",
+				gq.Attr, c.Attr)
+		}
+	}
+	sg.Params.GroupBy = gq.GroupBy
+	sg.Children = append(sg.Children, &SubGraph{
+		Attr:   gq.GroupBy,
+		Params: params{isDebug: sg.Params.isDebug},
+	})
+	return nil
+}
+
+// groupKey returns the child of sg which fetches the predicate given with
+// @groupby.
+func (sg *SubGraph) groupKey() *SubGraph {
+	for _, pc := range sg.Children {
+		if pc.Attr == sg.Params.GroupBy && len(pc.Params.Aggregator) == 0 {
+			return pc
+		}
+	}
+	return nil
+}
+
+// groups buckets the UIDs in ul by the predicate given with @groupby. The
+// UIDs without it are left out.
+func (sg *SubGraph) groups(ul *task.List) ([]*uidGroup, error) {
+	key := sg.groupKey()
+	if key == nil {
+		return nil, nil
+	}
+	var out []*uidGroup
+	byUID := make(map[uint64]*uidGroup)
+	byValue := make(map[string]*uidGroup)
+	for _, uid := range ul.Uids {
+		idx := algo.IndexOf(key.SrcUIDs, uid)
+		if idx < 0 {
+			continue
+		}
+		// A UID predicate puts the UID in a group for each UID it points to.
+		for _, kuid := range key.uidMatrix[idx].Uids {
+			g, ok := byUID[kuid]
+			if !ok {
+				g = &uidGroup{uid: kuid}
+				byUID[kuid] = g
+				out = append(out, g)
+			}
+			g.uids = append(g.uids, uid)
+		}
+		tv := key.values[idx]
+		if len(key.uidMatrix[idx].Uids) > 0 || bytes.Equal(tv.Val, nil) {
+			continue
+		}
+		v, err := getValue(tv)
+		if err != nil {
+			return nil, err
+		}
+		if cv, err := key.scalarType(v).Convert(v); err == nil {
+			v = cv
+		}
+		b, err := v.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		g, ok := byValue[string(b)]
+		if !ok {
+			g = &uidGroup{value: v}
+			byValue[string(b)] = g
+			out = append(out, g)
+		}
+		g.uids = append(g.uids, uid)
+	}
+	sort.Sort(byGroupKey(out))
+	return out, nil
+}
+
+// addGroups adds a node to dst for each group of the UIDs in ul, for
+// @groupby. A node has the key of the group, the number of UIDs in it, and
+// the aggregations asked for over them. It returns the number of groups.
+func (sg *SubGraph) addGroups(ul *task.List, dst outputNode) (int, error) {
+	groups, err := sg.groups(ul)
+	if err != nil {
+		return 0, err
+	}
+	for _, g := range groups {
+		uc := dst.New(sg.Attr)
+		if g.value != nil {
+			uc.AddValue(sg.Params.GroupBy, g.value)
+		} else {
+			uc.SetUID(g.uid)
+		}
+		c := types.Int32(len(g.uids))
+		uc.AddValue("count", &c)
+
+		gl := &task.List{Uids: g.uids}
+		for _, pc := range sg.Children {
+			if len(pc.Params.Aggregator) == 0 {
+				continue
+			}
+			v, err := pc.aggregate(gl)
+			if err != nil {
+				return 0, err
+			}
+			if v != nil {
+				uc.AddValue(pc.aggregateName(), v)
+			}
+		}
+		dst.AddChild(sg.Attr, uc)
+	}
+	return len(groups), nil
+}
@@ -0,0 +1,210 @@
+/*
+ * Copyright 2016 Dgraph Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * 		http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+import (
+	"bytes"
+	"context"
+	"math"
+
+	"github.com/dgraph-io/dgraph/algo"
+	"github.com/dgraph-io/dgraph/gql"
+	"github.com/dgraph-io/dgraph/task"
+	"github.com/dgraph-io/dgraph/types"
+	"github.com/dgraph-io/dgraph/worker"
+	"github.com/dgraph-io/dgraph/x"
+)
+
+// evalMath evaluates the expression t, given the values of the predicates it
+// uses. Booleans are 1 for true and 0 for false. It returns false if any of
+// the predicates is missing from vars.
+func evalMath(t *gql.MathTree, vars map[string]float64) (float64, bool) {
+	if len(t.Fn) == 0 {
+		if len(t.Var) == 0 {
+			return t.Const, true
+		}
+		v, ok := vars[t.Var]
+		return v, ok
+	}
+	args := make([]float64, len(t.Child))
+	for i, c := range t.Child {
+		v, ok := evalMath(c, vars)
+		if !ok {
+			return 0, false
+		}
+		args[i] = v
+	}
+	b := func(cond bool) float64 {
+		if cond {
+			return 1
+		}
+		return 0
+	}
+
+	if len(args) == 1 {
+		a := args[0]
+		switch t.Fn {
+		case "-":
+			return -a, true
+		case "!":
+			return b(a == 0), true
+		case "ln":
+			return math.Log(a), true
+		case "exp":
+			return math.Exp(a), true
+		case "sqrt":
+			return math.Sqrt(a), true
+		case "abs":
+			return math.Abs(a), true
+		case "floor":
+			return math.Floor(a), true
+		case "ceil":
+			return math.Ceil(a), true
+		}
+	}
+	switch t.Fn {
+	case "min", "max":
+		res := args[0]
+		for _, a := range args[1:] {
+			if t.Fn == "min" {
+				res = math.Min(res, a)
+			} else {
+				res = math.Max(res, a)
+			}
+		}
+		return res, true
+	}
+
+	x.AssertTruef(len(args) == 2, "Unexpected arguments for %s: %v", t.Fn, args)
+	a, c := args[0], args[1]
+	switch t.Fn {
+	case "+":
+		return a + c, true
+	case "-":
+		return a - c, true
+	case "*":
+		return a * c, true
+	case "/":
+		return a / c, true
+	case "%":
+		return math.Mod(a, c), true
+	case "pow":
+		return math.Pow(a, c), true
+	case "<":
+		return b(a < c), true
+	case "<=":
+		return b(a <= c), true
+	case ">":
+		return b(a > c), true
+	case ">=":
+		return b(a >= c), true
+	case "==":
+		return b(a == c), true
+	case "!=":
+		return b(a != c), true
+	case "&&":
+		return b(a != 0 && c != 0), true
+	case "||":
+		return b(a != 0 || c != 0), true
+	}
+	x.AssertTruef(false, "Unknown function in math expression: %s", t.Fn)
+	return 0, false
+}
+
+// mathValues computes the value of expr for each of uids, from the values of
+// the predicates it uses. A value is nil if any of those is missing or isn't
+// a number, or if the result isn't finite, as for a division by zero.
+func mathValues(ctx context.Context, expr *gql.MathTree, uids []uint64) ([]types.Value,
+	error) {
+	vars := make([]map[string]float64, len(uids))
+	for i := range vars {
+		vars[i] = make(map[string]float64)
+	}
+	floatType, _ := types.TypeForName("float")
+	for _, attr := range expr.Vars() {
+		result, err := worker.ProcessTaskOverNetwork(ctx, &task.Query{
+			Attr: attr,
+			Uids: uids,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for i, tv := range result.Values {
+			if i >= len(uids) || bytes.Equal(tv.Val, nil) {
+				continue
+			}
+			v, err := getValue(tv)
+			if err != nil {
+				return nil, err
+			}
+			fv, err := floatType.(types.Scalar).Convert(v)
+			if err != nil {
+				continue
+			}
+			vars[i][attr] = float64(*fv.(*types.Float))
+		}
+	}
+
+	out := make([]types.Value, len(uids))
+	for i := range uids {
+		f, ok := evalMath(expr, vars[i])
+		if !ok || math.IsNaN(f) || math.IsInf(f, 0) {
+			continue
+		}
+		if expr.IsBool() {
+			v := types.Bool(f != 0)
+			out[i] = &v
+		} else {
+			v := types.Float(f)
+			out[i] = &v
+		}
+	}
+	return out, nil
+}
+
+// processMath computes the math() expression of sg for its SrcUIDs. As a
+// child, the values are then added to the nodes. As a filter, DestUIDs holds
+// the UIDs for which the value is true, or not zero.
+func (sg *SubGraph) processMath(ctx context.Context) error {
+	vals, err := mathValues(ctx, sg.Params.MathExpr, sg.SrcUIDs.Uids)
+	if err != nil {
+		return err
+	}
+	sg.mathVals = vals
+	sg.DestUIDs = &task.List{Uids: make([]uint64, len(sg.SrcUIDs.Uids))}
+	copy(sg.DestUIDs.Uids, sg.SrcUIDs.Uids)
+	algo.ApplyFilter(sg.DestUIDs, func(uid uint64, i int) bool {
+		switch v := vals[i].(type) {
+		case *types.Bool:
+			return bool(*v)
+		case *types.Float:
+			return *v != 0
+		}
+		return false
+	})
+	return nil
+}
+
+// mathChild returns the math() child of sg whose alias is name, if any.
+func (sg *SubGraph) mathChild(name string) *SubGraph {
+	for _, pc := range sg.Children {
+		if pc.Params.MathExpr != nil && pc.Params.Alias == name {
+			return pc
+		}
+	}
+	return nil
+}
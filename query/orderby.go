@@ -0,0 +1,144 @@
+/*
+ * Copyright 2016 Dgraph Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * 		http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+import (
+	"bytes"
+	"context"
+	"sort"
+
+	"github.com/dgraph-io/dgraph/algo"
+	"github.com/dgraph-io/dgraph/gql"
+	"github.com/dgraph-io/dgraph/schema"
+	"github.com/dgraph-io/dgraph/task"
+	"github.com/dgraph-io/dgraph/types"
+	"github.com/dgraph-io/dgraph/worker"
+)
+
+// byOrder sorts UIDs by the values of several predicates, the later ones
+// breaking ties between the earlier ones. The UIDs missing a value come last
+// for that predicate.
+type byOrder struct {
+	uids   []uint64
+	values [][]types.Value // values[i][k] is the value of order[k] for uids[i].
+	order  []*gql.Order
+}
+
+func (b byOrder) Len() int { return len(b.uids) }
+func (b byOrder) Swap(i, j int) {
+	b.uids[i], b.uids[j] = b.uids[j], b.uids[i]
+	b.values[i], b.values[j] = b.values[j], b.values[i]
+}
+func (b byOrder) Less(i, j int) bool {
+	for k, o := range b.order {
+		vi, vj := b.values[i][k], b.values[j][k]
+		if vi == nil || vj == nil {
+			if vi == nil && vj == nil {
+				continue
+			}
+			return vj == nil
+		}
+		if vi.Type().ID() != vj.Type().ID() {
+			return vi.Type().ID() < vj.Type().ID()
+		}
+		if types.LessValues(vi, vj) {
+			return !o.Desc
+		}
+		if types.LessValues(vj, vi) {
+			return o.Desc
+		}
+	}
+	return false
+}
+
+// orderValues fetches the values of the predicates in sg.Params.Order for the
+// UIDs in sg.DestUIDs. The ones naming a math() child are computed instead.
+// The result is indexed like sg.DestUIDs, and then by the position of the
+// predicate in the order.
+func (sg *SubGraph) orderValues(ctx context.Context) ([][]types.Value, error) {
+	uids := sg.DestUIDs.Uids
+	out := make([][]types.Value, len(uids))
+	for i := range out {
+		out[i] = make([]types.Value, len(sg.Params.Order))
+	}
+	for k, o := range sg.Params.Order {
+		if mc := sg.mathChild(o.Attr); mc != nil {
+			vals, err := mathValues(ctx, mc.Params.MathExpr, uids)
+			if err != nil {
+				return nil, err
+			}
+			for i, v := range vals {
+				out[i][k] = v
+			}
+			continue
+		}
+		result, err := worker.ProcessTaskOverNetwork(ctx, &task.Query{
+			Attr: o.Attr,
+			Uids: uids,
+		})
+		if err != nil {
+			return nil, err
+		}
+		scalar, isScalar := schema.TypeOf(o.Attr).(types.Scalar)
+		for i, tv := range result.Values {
+			if i >= len(uids) || bytes.Equal(tv.Val, nil) {
+				continue
+			}
+			v, err := getValue(tv)
+			if err != nil {
+				return nil, err
+			}
+			if isScalar {
+				if cv, err := scalar.Convert(v); err == nil {
+					v = cv
+				}
+			}
+			out[i][k] = v
+		}
+	}
+	return out, nil
+}
+
+// applyMultiOrder sorts each of the lists in sg.uidMatrix by all of the
+// predicates in sg.Params.Order, and then applies pagination. It's only used
+// when some of them are math() children, as the worker sorts by the others.
+// Unless the first one is a math() child, the lists are expected to hold only
+// the UIDs which have a value for it.
+func (sg *SubGraph) applyMultiOrder(ctx context.Context) error {
+	values, err := sg.orderValues(ctx)
+	if err != nil {
+		return err
+	}
+	for _, ul := range sg.uidMatrix {
+		b := byOrder{
+			uids:   ul.Uids,
+			values: make([][]types.Value, len(ul.Uids)),
+			order:  sg.Params.Order,
+		}
+		for j, uid := range ul.Uids {
+			if idx := algo.IndexOf(sg.DestUIDs, uid); idx >= 0 {
+				b.values[j] = values[idx]
+			} else {
+				b.values[j] = make([]types.Value, len(sg.Params.Order))
+			}
+		}
+		sort.Stable(b)
+		start, end := pageRange(&sg.Params, len(ul.Uids))
+		ul.Uids = ul.Uids[start:end]
+	}
+	return nil
+}
@@ -23,6 +23,7 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -32,6 +33,7 @@ import (
 
 	"github.com/dgraph-io/dgraph/algo"
 	"github.com/dgraph-io/dgraph/gql"
+	"github.com/dgraph-io/dgraph/group"
 	"github.com/dgraph-io/dgraph/query/graph"
 	"github.com/dgraph-io/dgraph/schema"
 	"github.com/dgraph-io/dgraph/task"
@@ -123,8 +125,72 @@ type params struct {
 	AfterUID uint64
 	DoCount  bool
 	GetUID   bool
-	Order    string
 	isDebug  bool
+
+	// Order holds the predicates to sort by, the later ones breaking ties
+	// between the earlier ones.
+	Order []*gql.Order
+
+	// Langs are the languages to get the values in, in order of preference.
+	Langs []string
+
+	// Var is the name of the variable which DestUIDs are stored in, for use as
+	// the root of later query blocks.
+	Var string
+
+	// Aggregator is the aggregation function, like min or avg, computed over
+	// the values of this predicate.
+	Aggregator string
+
+	// IsCount is set for count(attr). As a child, the number of edges is
+	// added as a value of each node. As a function or filter, like
+	// gt(count(friend), 10), the number of edges is compared instead of the
+	// values.
+	IsCount bool
+
+	// MathExpr is the expression of math(), for a child like
+	// score: math(likes / views) or a filter like math(age > 18). It is
+	// computed here, from the values of the predicates it uses.
+	MathExpr *gql.MathTree
+
+	// NeedsVar is the variable holding the UIDs of a uid_in filter, like f in
+	// uid_in(friend, var(f)). They are added to SrcFunc before processing.
+	NeedsVar string
+
+	// Recurse is set for @recurse, in which case the children are expanded
+	// repeatedly, up to RecurseDepth levels. Zero depth means no limit.
+	Recurse      bool
+	RecurseDepth int
+
+	// Cascade is set for @cascade, or if an ancestor has it. Nodes which are
+	// missing any of the children asked for are then left out of the result.
+	Cascade bool
+
+	// Normalize is set for @normalize on the query block. Values are then
+	// only added under their aliases, and the results are flattened.
+	Normalize bool
+
+	// GroupBy is the predicate given with @groupby. The UIDs are then grouped
+	// by it, and the aggregations among the children are done per group.
+	GroupBy string
+
+	// ExpandAll is set for expand(_all_), in which case a child is added for
+	// each of the predicates stored for the UIDs.
+	ExpandAll bool
+	expanded  bool // Set for the children added by expand(_all_).
+
+	// ShortestPath is set for the shortest(from: X, to: Y) query. Its children
+	// are the predicates which can be followed along the path.
+	ShortestPath *gql.ShortestPath
+
+	// GetScore is set if the relevance of the results of a full-text search
+	// is asked for with _score_.
+	GetScore bool
+
+	// Facets is set by @facets, to get the facets on the edges, and to sort
+	// the edges by one of them. FacetsFilter filters the edges by their facets.
+	Facets       *gql.Facets
+	FacetsFilter *gql.FilterTree
 }
 
 // SubGraph is the way to represent data internally. It contains both the
@@ -136,6 +202,13 @@ type SubGraph struct {
 	counts    []uint32
 	values    []*task.Value
 	uidMatrix []*task.List
+	// scores holds the relevance of the UIDs found by a full-text search.
+	scores map[uint64]float64
+	// facets holds the facets on the edges from each of SrcUIDs, by the UID
+	// the edge points to.
+	facets []map[uint64]*task.Facets
+	// mathVals holds the values of a math() expression for each of SrcUIDs.
+	mathVals []types.Value
 
 	// SrcUIDs is a list of unique source UIDs. They are always copies of destUIDs
 	// of parent nodes in GraphQL structure.
@@ -148,6 +221,8 @@ type SubGraph struct {
 
 	// destUIDs is a list of destination UIDs, after applying filters, pagination.
 	DestUIDs *task.List
+
+	stats planStats // For Explain.
 }
 
 // DebugPrint prints out the SubGraph tree in a nice format for debugging purposes.
@@ -216,17 +291,47 @@ func (sg *SubGraph) preTraverse(uid uint64, dst outputNode) error {
 	invalidUids := make(map[uint64]bool)
 	// We go through all predicate children of the subgraph.
 	for _, pc := range sg.Children {
+		if len(pc.Params.Aggregator) > 0 {
+			// These are added by the parent, over all the UIDs at this level.
+			continue
+		}
 		idx := algo.IndexOf(pc.SrcUIDs, uid)
 		if idx < 0 {
+			if sg.Params.Cascade {
+				return x.Errorf("_INV_")
+			}
+			continue
+		}
+		if pc.Params.MathExpr != nil {
+			if v := pc.mathVals[idx]; v != nil {
+				dst.AddValue(pc.Params.Alias, v)
+			} else if pc.Params.Cascade {
+				return x.Errorf("_INV_")
+			}
 			continue
 		}
 		ul := pc.uidMatrix[idx]
+		// found is set if anything gets added to dst for pc, which @cascade
+		// requires.
+		var found bool
+
+		if len(pc.Params.GroupBy) > 0 {
+			n, err := pc.addGroups(ul, dst)
+			if err != nil {
+				return err
+			}
+			found = n > 0
 
-		if len(pc.counts) > 0 {
+		} else if len(pc.counts) > 0 {
 			c := types.Int32(pc.counts[idx])
-			uc := dst.New(pc.Attr)
-			uc.AddValue("_count_", &c)
-			dst.AddChild(pc.Attr, uc)
+			if pc.Params.IsCount {
+				dst.AddValue(pc.aggregateName(), &c)
+			} else {
+				uc := dst.New(pc.Attr)
+				uc.AddValue("_count_", &c)
+				dst.AddChild(pc.Attr, uc)
+			}
+			found = true
 
 		} else if len(ul.Uids) > 0 || len(pc.Children) > 0 {
 			// We create as many predicate entity children as the length of uids for
@@ -236,9 +341,15 @@ func (sg *SubGraph) preTraverse(uid uint64, dst outputNode) error {
 					continue
 				}
 				uc := dst.New(pc.Attr)
-				if sg.Params.GetUID || sg.Params.isDebug {
+				if sg.Params.GetUID || sg.Params.isDebug || pc.Params.expanded {
 					uc.SetUID(uid)
 				}
+				if pc.Params.Facets != nil {
+					// The facets of the edge leading to uc are kept under "_".
+					if err := addFacets(uc, "_", pc.edgeFacets(idx, uid)); err != nil {
+						return err
+					}
+				}
 				if rerr := pc.preTraverse(uid, uc); rerr != nil {
 					if rerr.Error() == "_INV_" {
 						invalidUids[uid] = true
@@ -250,8 +361,12 @@ func (sg *SubGraph) preTraverse(uid uint64, dst outputNode) error {
 				}
 				if !uc.IsEmpty() {
 					dst.AddChild(pc.Attr, uc)
+					found = true
 				}
 			}
+			if err := pc.addAggregations(ul, pc.Attr, dst); err != nil {
+				return err
+			}
 		} else {
 			tv := pc.values[idx]
 			v, err := getValue(tv)
@@ -265,8 +380,10 @@ func (sg *SubGraph) preTraverse(uid uint64, dst outputNode) error {
 					return err
 				}
 				dst.SetXID(string(txt))
+				found = true
 			} else if pc.Attr == "_uid_" {
 				dst.SetUID(uid)
+				found = true
 			} else {
 				globalType := schema.TypeOf(pc.Attr)
 				schemaType := pc.Params.AttrType
@@ -284,6 +401,9 @@ func (sg *SubGraph) preTraverse(uid uint64, dst outputNode) error {
 						// skip values that don't convert.
 						return x.Errorf("_INV_")
 					}
+				} else if bytes.Equal(tv.Val, nil) {
+					// Nothing stored here, like a UID predicate without any edges.
+					sv = nil
 				} else if globalType != nil {
 					// Try to coerce types if this is an optional scalar outside an
 					// object definition.
@@ -291,19 +411,45 @@ func (sg *SubGraph) preTraverse(uid uint64, dst outputNode) error {
 						return x.Errorf("Leaf predicate:'%v' must be a scalar.", pc.Attr)
 					}
 					gt := globalType.(types.Scalar)
-					// Convert to schema type.
-					sv, err = gt.Convert(v)
-					if bytes.Equal(tv.Val, nil) || err != nil {
-						continue
+					// Convert to schema type, skipping values that don't convert.
+					if sv, err = gt.Convert(v); err != nil {
+						sv = nil
+					}
+				}
+				if sv != nil {
+					if !sg.Params.Normalize {
+						dst.AddValue(pc.valueName(), sv)
+					} else if len(pc.Params.Alias) > 0 {
+						// Only the aliased values are kept by @normalize.
+						dst.AddValue(pc.Params.Alias, sv)
+					}
+					found = true
+					if pc.Params.Facets != nil {
+						if err := addFacets(dst, pc.valueName(),
+							pc.edgeFacets(idx, valueFacetsKey)); err != nil {
+							return err
+						}
 					}
 				}
-				dst.AddValue(pc.Attr, sv)
 			}
 		}
+		if sg.Params.Cascade && !found {
+			// The node is incomplete, so its parent leaves it out.
+			return x.Errorf("_INV_")
+		}
 	}
 	return nil
 }
 
+// valueName returns the name the value of sg is output under, which includes
+// the languages asked for, like name@fr:en.
+func (sg *SubGraph) valueName() string {
+	if len(sg.Params.Langs) == 0 {
+		return sg.Attr
+	}
+	return sg.Attr + "@" + strings.Join(sg.Params.Langs, ":")
+}
+
 func createProperty(prop string, v types.Value) *graph.Property {
 	pval := toProtoValue(v)
 	return &graph.Property{Prop: prop, Value: pval}
@@ -324,6 +470,9 @@ func filterCopy(sg *SubGraph, ft *gql.FilterTree) {
 		sg.FilterOp = ft.Op
 	} else {
 		sg.Attr = ft.Func.Attr
+		sg.Params.IsCount = ft.Func.IsCount
+		sg.Params.MathExpr = ft.Func.MathExpr
+		sg.Params.NeedsVar = ft.Func.NeedsVar
 		sg.SrcFunc = append(sg.SrcFunc, ft.Func.Name)
 		sg.SrcFunc = append(sg.SrcFunc, ft.Func.Args...)
 	}
@@ -340,6 +489,12 @@ func treeCopy(ctx context.Context, gq *gql.GraphQuery, sg *SubGraph) error {
 	// node, because of the way we're dealing with the root node.
 	// So, we work on the children, and then recurse for grand children.
 
+	if gq.Recurse {
+		if err := sg.setRecurse(gq); err != nil {
+			return err
+		}
+	}
+
 	var scalars []string
 	// Add scalar children nodes based on schema
 	if obj, ok := sg.Params.AttrType.(types.Object); ok {
@@ -360,7 +515,18 @@ func treeCopy(ctx context.Context, gq *gql.GraphQuery, sg *SubGraph) error {
 	}
 
 	for _, gchild := range gq.Children {
-		if isPresent(scalars, gchild.Attr) {
+		if gchild.Func == nil && isPresent(scalars, gchild.Attr) {
+			continue
+		}
+		if gchild.Func != nil && gchild.Func.Name == "expand" {
+			if gchild.Attr != "_all_" {
+				return x.Errorf("Only expand(_all_) is supported, got expand(%s)",
+					gchild.Attr)
+			}
+			if len(gchild.Children) > 0 {
+				return x.Errorf("expand(_all_) cannot have other attributes")
+			}
+			sg.Params.ExpandAll = true
 			continue
 		}
 		if gchild.Attr == "_count_" {
@@ -376,6 +542,33 @@ func treeCopy(ctx context.Context, gq *gql.GraphQuery, sg *SubGraph) error {
 		if gchild.Attr == "_uid_" {
 			sg.Params.GetUID = true
 		}
+		if gchild.Attr == "_score_" {
+			if len(sg.SrcFunc) == 0 || !worker.IsTextFunc(sg.SrcFunc[0]) {
+				return x.Errorf("_score_ is only available at the root of a full-text search")
+			}
+			sg.Params.GetScore = true
+			continue
+		}
+		if gchild.MathExpr != nil {
+			if len(gchild.Children) > 0 || gchild.Filter != nil {
+				return x.Errorf("math() cannot have other attributes or filters")
+			}
+			sg.Children = append(sg.Children, &SubGraph{
+				Attr: gchild.Attr,
+				Params: params{
+					Alias:    gchild.Alias,
+					isDebug:  sg.Params.isDebug,
+					Cascade:  sg.Params.Cascade || gchild.Cascade,
+					MathExpr: gchild.MathExpr,
+				},
+			})
+			continue
+		}
+		if strings.HasPrefix(gchild.Attr, "~") &&
+			!schema.IsReversed(strings.TrimPrefix(gchild.Attr, "~")) {
+			return x.Errorf("Predicate %s doesn't have reverse edges",
+				strings.TrimPrefix(gchild.Attr, "~"))
+		}
 
 		// Determine the type of current node.
 		var attrType types.Type
@@ -392,49 +585,105 @@ func treeCopy(ctx context.Context, gq *gql.GraphQuery, sg *SubGraph) error {
 			}
 		}
 		args := params{
-			AttrType: attrType,
-			Alias:    gchild.Alias,
-			isDebug:  sg.Params.isDebug,
+			AttrType:  attrType,
+			Alias:     gchild.Alias,
+			isDebug:   sg.Params.isDebug,
+			Var:       gchild.Var,
+			Cascade:   sg.Params.Cascade || gchild.Cascade,
+			Normalize: sg.Params.Normalize,
 		}
 		dst := &SubGraph{
 			Attr:   gchild.Attr,
 			Params: args,
 		}
+		if gchild.Func != nil {
+			if len(gchild.Children) > 0 {
+				return x.Errorf("Aggregation %s(%s) cannot have other attributes",
+					gchild.Func.Name, gchild.Attr)
+			}
+			if gchild.Func.Name == "count" {
+				dst.Params.DoCount = true
+				dst.Params.IsCount = true
+			} else {
+				dst.Params.Aggregator = gchild.Func.Name
+			}
+		}
 		if gchild.Filter != nil {
 			dstf := &SubGraph{}
 			filterCopy(dstf, gchild.Filter)
 			dst.Filters = append(dst.Filters, dstf)
 		}
 
-		if v, ok := gchild.Args["offset"]; ok {
-			offset, err := strconv.ParseInt(v, 0, 32)
-			if err != nil {
-				return err
-			}
-			dst.Params.Offset = int(offset)
+		if err := dst.Params.setPagination(gchild.Args); err != nil {
+			return err
 		}
-		if v, ok := gchild.Args["after"]; ok {
-			after, err := strconv.ParseUint(v, 0, 64)
-			if err != nil {
-				return err
-			}
-			dst.Params.AfterUID = uint64(after)
+		dst.Params.Order = gchild.Order
+		dst.Params.Langs = gchild.Langs
+		dst.Params.Facets = gchild.Facets
+		dst.Params.FacetsFilter = gchild.FacetsFilter
+		if gchild.Facets != nil && len(gchild.Facets.OrderKey) > 0 &&
+			len(dst.Params.Order) > 0 {
+			return x.Errorf("Cannot sort %s by both a predicate and a facet", gchild.Attr)
 		}
-		if v, ok := gchild.Args["first"]; ok {
-			first, err := strconv.ParseInt(v, 0, 32)
-			if err != nil {
+		sg.Children = append(sg.Children, dst)
+		err := treeCopy(ctx, gchild, dst)
+		if err != nil {
+			return err
+		}
+		if len(gchild.GroupBy) > 0 {
+			if err := dst.setGroupBy(gchild); err != nil {
 				return err
 			}
-			dst.Params.Count = int(first)
 		}
-		if v, ok := gchild.Args["order"]; ok {
-			dst.Params.Order = v
+	}
+	return nil
+}
+
+// setPagination sets the pagination params from the offset, after and first
+// arguments, if given.
+func (p *params) setPagination(args map[string]string) error {
+	if v, ok := args["offset"]; ok {
+		offset, err := strconv.ParseInt(v, 0, 32)
+		if err != nil {
+			return err
+		}
+		p.Offset = int(offset)
+	}
+	if v, ok := args["after"]; ok {
+		after, err := strconv.ParseUint(v, 0, 64)
+		if err != nil {
+			return err
 		}
-		sg.Children = append(sg.Children, dst)
-		err := treeCopy(ctx, gchild, dst)
+		p.AfterUID = uint64(after)
+	}
+	if v, ok := args["first"]; ok {
+		first, err := strconv.ParseInt(v, 0, 32)
 		if err != nil {
 			return err
 		}
+		p.Count = int(first)
+	}
+	return nil
+}
+
+// setRecurse sets the recurse params for sg, given gq has the @recurse directive.
+func (sg *SubGraph) setRecurse(gq *gql.GraphQuery) error {
+	sg.Params.Recurse = true
+	if v, ok := gq.Args["depth"]; ok {
+		depth, err := strconv.ParseInt(v, 0, 32)
+		if err != nil {
+			return err
+		}
+		if depth <= 0 {
+			return x.Errorf("Depth for @recurse should be positive. Got: %v", depth)
+		}
+		sg.Params.RecurseDepth = int(depth)
+	}
+	for _, gchild := range gq.Children {
+		if len(gchild.Children) > 0 {
+			return x.Errorf("Nested blocks are not allowed with @recurse: %v",
+				gchild.Attr)
+		}
 	}
 	return nil
 }
@@ -460,7 +709,14 @@ func newGraph(ctx context.Context, gq *gql.GraphQuery) (*SubGraph, error) {
 		x.Trace(ctx, "Xid: %v Uid: %v", exid, euid)
 	}
 
-	if euid == 0 && gq.Func == nil {
+	if gq.ShortestPath != nil {
+		if err := checkShortestPath(gq); err != nil {
+			return nil, err
+		}
+		euid = gq.ShortestPath.From
+	}
+
+	if euid == 0 && gq.Func == nil && len(gq.NeedsVar) == 0 {
 		err := x.Errorf("Invalid query, query internal id is zero and generator is nil")
 		x.TraceError(ctx, err)
 		return nil, err
@@ -469,9 +725,13 @@ func newGraph(ctx context.Context, gq *gql.GraphQuery) (*SubGraph, error) {
 	// For the root, the name to be used in result is stored in Alias, not Attr.
 	// The attr at root (if present) would stand for the source functions attr.
 	args := params{
-		AttrType: schema.TypeOf(gq.Alias),
-		isDebug:  gq.Alias == "debug",
-		Alias:    gq.Alias,
+		AttrType:     schema.TypeOf(gq.Alias),
+		isDebug:      gq.Alias == "debug",
+		Alias:        gq.Alias,
+		Var:          gq.Var,
+		ShortestPath: gq.ShortestPath,
+		Cascade:      gq.Cascade,
+		Normalize:    gq.Normalize,
 	}
 
 	sg := &SubGraph{
@@ -479,9 +739,21 @@ func newGraph(ctx context.Context, gq *gql.GraphQuery) (*SubGraph, error) {
 	}
 	if gq.Func != nil {
 		sg.Attr = gq.Func.Attr
+		sg.Params.IsCount = gq.Func.IsCount
 		sg.SrcFunc = append(sg.SrcFunc, gq.Func.Name)
 		sg.SrcFunc = append(sg.SrcFunc, gq.Func.Args...)
 	}
+	// The results at the root are filtered, sorted and paginated the same way
+	// as the edges of a predicate.
+	if err := sg.Params.setPagination(gq.Args); err != nil {
+		return nil, err
+	}
+	sg.Params.Order = gq.Order
+	if gq.Filter != nil {
+		sgf := &SubGraph{}
+		filterCopy(sgf, gq.Filter)
+		sg.Filters = append(sg.Filters, sgf)
+	}
 	if euid > 0 {
 		// euid is the root UID.
 		sg.SrcUIDs = &task.List{Uids: []uint64{euid}}
@@ -503,17 +775,25 @@ func createNilValuesList(count int) []*task.Value {
 
 // createTaskQuery generates the query buffer.
 func createTaskQuery(sg *SubGraph) *task.Query {
+	// The counts are compared by the worker for a function on them, and are
+	// otherwise only fetched if they don't depend on the filters.
+	doCount := (len(sg.SrcFunc) > 0 && sg.Params.IsCount) ||
+		(len(sg.Filters) == 0 && sg.Params.FacetsFilter == nil && sg.Params.DoCount)
 	out := &task.Query{
 		Attr:     sg.Attr,
 		SrcFunc:  sg.SrcFunc,
 		Count:    int32(sg.Params.Count),
 		Offset:   int32(sg.Params.Offset),
 		AfterUid: sg.Params.AfterUID,
-		DoCount:  len(sg.Filters) == 0 && sg.Params.DoCount,
+		DoCount:  doCount,
+		Langs:    sg.Params.Langs,
 	}
 	if sg.SrcUIDs != nil {
 		out.Uids = sg.SrcUIDs.Uids
 	}
+	if len(sg.SrcFunc) == 0 {
+		out.FacetParam = facetParams(sg)
+	}
 	return out
 }
 
@@ -522,6 +802,12 @@ func createTaskQuery(sg *SubGraph) *task.Query {
 func ProcessGraph(ctx context.Context, sg, parent *SubGraph, rch chan error) {
 	var err error
 
+	if sg.Params.MathExpr != nil {
+		// math() needs nothing but the values of the predicates it uses.
+		rch <- sg.processMath(ctx)
+		return
+	}
+
 	if len(sg.Attr) == 0 {
 		// If we have a filter SubGraph which only contains an operator,
 		// it won't have any attribute to work on.
@@ -535,22 +821,38 @@ func ProcessGraph(ctx context.Context, sg, parent *SubGraph, rch chan error) {
 
 	} else {
 		taskQuery := createTaskQuery(sg)
+		sg.stats.query = taskQuery
+		sg.stats.group = group.BelongsTo(sg.Attr)
+		start := time.Now()
 		result, err := worker.ProcessTaskOverNetwork(ctx, taskQuery)
 		if err != nil {
 			x.TraceError(ctx, x.Wrapf(err, "Error while processing task"))
 			rch <- err
 			return
 		}
+		sg.stats.took = time.Since(start)
 
 		sg.uidMatrix = result.UidMatrix
 		sg.values = result.Values
+		if len(result.Scores) > 0 {
+			sg.setScores(result.UidMatrix[0], result.Scores)
+		}
 		if len(sg.values) > 0 {
 			v := sg.values[0]
 			x.Trace(ctx, "Sample value for attr: %v Val: %v", sg.Attr, string(v.Val))
 		}
 		sg.counts = result.Counts
+		if len(result.FacetMatrix) > 0 {
+			sg.setFacets(result.FacetMatrix)
+		}
+		if sg.Params.FacetsFilter != nil && len(sg.SrcFunc) == 0 {
+			if err = sg.applyFacetsFilter(); err != nil {
+				rch <- err
+				return
+			}
+		}
 
-		if sg.Params.DoCount && len(sg.Filters) == 0 {
+		if sg.Params.DoCount && len(sg.Filters) == 0 && sg.Params.FacetsFilter == nil {
 			// If there is a filter, we need to do more work to get the actual count.
 			x.Trace(ctx, "Zero uids. Only count requested")
 			rch <- nil
@@ -562,8 +864,14 @@ func ProcessGraph(ctx context.Context, sg, parent *SubGraph, rch chan error) {
 		} else {
 			sg.DestUIDs = algo.MergeSorted(result.UidMatrix)
 		}
+		if parent == nil {
+			// The results of a function at the root form a single list, to be
+			// sorted and paginated.
+			sg.uidMatrix = []*task.List{{Uids: append([]uint64{}, sg.DestUIDs.Uids...)}}
+		}
 	}
 
+	sg.stats.fetched = len(sg.DestUIDs.Uids)
 	if len(sg.DestUIDs.Uids) == 0 {
 		// Looks like we're done here. Be careful with nil srcUIDs!
 		x.Trace(ctx, "Zero uids for %q. Num attr children: %v", sg.Attr, len(sg.Children))
@@ -573,6 +881,7 @@ func ProcessGraph(ctx context.Context, sg, parent *SubGraph, rch chan error) {
 
 	// Apply filters if any.
 	if len(sg.Filters) > 0 {
+		start := time.Now()
 		// Run all filters in parallel.
 		filterChan := make(chan error, len(sg.Filters))
 		for _, filter := range sg.Filters {
@@ -603,12 +912,24 @@ func ProcessGraph(ctx context.Context, sg, parent *SubGraph, rch chan error) {
 		}
 		if sg.FilterOp == "|" {
 			sg.DestUIDs = algo.MergeSorted(lists)
+		} else if sg.FilterOp == "!" {
+			x.AssertTrue(len(lists) == 1)
+			sg.DestUIDs = algo.Difference(sg.DestUIDs, lists[0])
 		} else {
 			sg.DestUIDs = algo.IntersectSorted(lists)
 		}
+		sg.stats.filterTook = time.Since(start)
 	}
 
-	if len(sg.Params.Order) == 0 {
+	start := time.Now()
+	sg.stats.sort = sg.sortStrategy()
+	if sg.Params.Facets != nil && len(sg.Params.Facets.OrderKey) > 0 {
+		// The edges are sorted by a facet, before pagination.
+		if err = sg.applyFacetOrderAndPagination(); err != nil {
+			rch <- err
+			return
+		}
+	} else if len(sg.Params.Order) == 0 {
 		// There is no ordering. Just apply pagination and return.
 		if err = sg.applyPagination(ctx); err != nil {
 			rch <- err
@@ -621,6 +942,7 @@ func ProcessGraph(ctx context.Context, sg, parent *SubGraph, rch chan error) {
 			return
 		}
 	}
+	sg.stats.sortTook = time.Since(start)
 
 	// Here we consider handling _count_ with filtering. We do this after
 	// pagination because otherwise, we need to do the count with pagination
@@ -628,7 +950,7 @@ func ProcessGraph(ctx context.Context, sg, parent *SubGraph, rch chan error) {
 	// user wants to skip 100 entries and return 10 entries. In this case, you
 	// should return a count of 0, not 10.
 	if sg.Params.DoCount {
-		x.AssertTrue(len(sg.Filters) > 0)
+		x.AssertTrue(len(sg.Filters) > 0 || sg.Params.FacetsFilter != nil)
 		sg.counts = make([]uint32, len(sg.uidMatrix))
 		for i, ul := range sg.uidMatrix {
 			// A possible optimization is to return the size of the intersection
@@ -640,6 +962,23 @@ func ProcessGraph(ctx context.Context, sg, parent *SubGraph, rch chan error) {
 		return
 	}
 
+	if sg.Params.Recurse {
+		rch <- sg.expandRecurse(ctx)
+		return
+	}
+
+	if sg.Params.ShortestPath != nil {
+		rch <- sg.shortestPath(ctx)
+		return
+	}
+
+	if sg.Params.ExpandAll {
+		if err = sg.expandAll(ctx); err != nil {
+			rch <- err
+			return
+		}
+	}
+
 	childChan := make(chan error, len(sg.Children))
 	for i := 0; i < len(sg.Children); i++ {
 		child := sg.Children[i]
@@ -665,6 +1004,119 @@ func ProcessGraph(ctx context.Context, sg, parent *SubGraph, rch chan error) {
 	rch <- nil
 }
 
+// expandAll adds a child to sg for each of the predicates stored for its
+// UIDs, for expand(_all_). The predicates which are already asked for are
+// left as they are. The UIDs of the nodes a child points to are returned, as
+// there is nothing else to show for them.
+func (sg *SubGraph) expandAll(ctx context.Context) error {
+	preds, err := worker.PredicatesOverNetwork(ctx, sg.DestUIDs.Uids)
+	if err != nil {
+		return err
+	}
+	var attrs []string
+	for _, pc := range sg.Children {
+		attrs = append(attrs, pc.Attr)
+	}
+	for _, attr := range preds {
+		if isPresent(attrs, attr) {
+			continue
+		}
+		sg.Children = append(sg.Children, &SubGraph{
+			Attr: attr,
+			Params: params{
+				expanded:  true,
+				isDebug:   sg.Params.isDebug,
+				Cascade:   sg.Params.Cascade,
+				Normalize: sg.Params.Normalize,
+			},
+		})
+	}
+	return nil
+}
+
+// copyForRecurse returns a fresh copy of sg, without any results. This is used
+// to apply the children of a @recurse block again at the next level.
+func (sg *SubGraph) copyForRecurse() *SubGraph {
+	out := &SubGraph{
+		Attr:     sg.Attr,
+		Params:   sg.Params,
+		SrcFunc:  sg.SrcFunc,
+		FilterOp: sg.FilterOp,
+	}
+	for _, f := range sg.Filters {
+		out.Filters = append(out.Filters, f.copyForRecurse())
+	}
+	return out
+}
+
+// expandRecurse processes the children of a SubGraph with the @recurse
+// directive. The children act as a template, which is applied level by level
+// to the UIDs found at the previous level, until we run out of depth or new
+// UIDs. UIDs which have already been visited are not expanded again.
+func (sg *SubGraph) expandRecurse(ctx context.Context) error {
+	template := sg.Children
+	sg.Children = nil
+
+	visited := make(map[uint64]bool)
+	for _, uid := range sg.DestUIDs.Uids {
+		visited[uid] = true
+	}
+
+	frontier := []*SubGraph{sg}
+	for depth := 1; len(frontier) > 0; depth++ {
+		var children []*SubGraph
+		childChan := make(chan error, len(frontier)*len(template))
+		for _, parent := range frontier {
+			for _, t := range template {
+				child := t.copyForRecurse()
+				child.SrcUIDs = parent.DestUIDs // Make the connection.
+				parent.Children = append(parent.Children, child)
+				children = append(children, child)
+				go ProcessGraph(ctx, child, parent, childChan)
+			}
+		}
+
+		for _ = range children {
+			select {
+			case err := <-childChan:
+				if err != nil {
+					x.TraceError(ctx, x.Wrapf(err, "Error while processing child task"))
+					return err
+				}
+			case <-ctx.Done():
+				x.TraceError(ctx, x.Wrapf(ctx.Err(), "Context done before full execution"))
+				return ctx.Err()
+			}
+		}
+
+		if sg.Params.RecurseDepth > 0 && depth >= sg.Params.RecurseDepth {
+			break
+		}
+
+		// Pick the children with unvisited UIDs for the next level. We go over
+		// them in order, so that the output doesn't depend on which child got
+		// processed first.
+		var next []*SubGraph
+		for _, child := range children {
+			if child.DestUIDs == nil {
+				continue
+			}
+			algo.ApplyFilter(child.DestUIDs,
+				func(uid uint64, idx int) bool { return !visited[uid] })
+			if len(child.DestUIDs.Uids) == 0 {
+				continue
+			}
+			for _, uid := range child.DestUIDs.Uids {
+				visited[uid] = true
+			}
+			child.Params.GetUID = sg.Params.GetUID
+			next = append(next, child)
+		}
+		frontier = next
+	}
+	return nil
+}
+
 // pageRange returns start and end indices given pagination params. Note that n
 // is the size of the input list.
 func pageRange(p *params, n int) (int, int) {
@@ -695,7 +1147,19 @@ func (sg *SubGraph) applyPagination(ctx context.Context) error {
 	if params.Count == 0 && params.Offset == 0 { // No pagination.
 		return nil
 	}
-	x.AssertTrue(len(sg.SrcUIDs.Uids) == len(sg.uidMatrix))
+	if sg.scores != nil {
+		// The results of a full-text search are paginated by relevance.
+		uids := sg.rankedUIDs()
+		start, end := pageRange(&sg.Params, len(uids))
+		sg.uidMatrix = []*task.List{{Uids: uids[start:end]}}
+		sg.DestUIDs = &task.List{Uids: make([]uint64, end-start)}
+		copy(sg.DestUIDs.Uids, uids[start:end])
+		algo.Sort(sg.DestUIDs)
+		return nil
+	}
+	// The root has a single list, whatever the number of SrcUIDs.
+	x.AssertTrue(sg.SrcUIDs == nil || len(sg.uidMatrix) == 1 ||
+		len(sg.SrcUIDs.Uids) == len(sg.uidMatrix))
 	for _, l := range sg.uidMatrix {
 		algo.IntersectWith(l, sg.DestUIDs)
 		start, end := pageRange(&sg.Params, len(l.Uids))
@@ -716,20 +1180,44 @@ func (sg *SubGraph) applyOrderAndPagination(ctx context.Context) error {
 		// Only retrieve up to 1000 results by default.
 		sg.Params.Count = 1000
 	}
-
-	sort := &task.Sort{
-		Attr:      sg.Params.Order,
-		UidMatrix: sg.uidMatrix,
-		Offset:    int32(sg.Params.Offset),
-		Count:     int32(sg.Params.Count),
+	// Only the UIDs left after filtering are sorted.
+	for _, ul := range sg.uidMatrix {
+		algo.IntersectWith(ul, sg.DestUIDs)
 	}
-	result, err := worker.SortOverNetwork(ctx, sort)
-	if err != nil {
-		return err
+
+	// The values of math() are only known here, so if the UIDs are sorted by
+	// any, we get all of the UIDs sorted by the first predicate and finish the
+	// job here. Otherwise the worker breaks the ties and paginates.
+	byMath := false
+	for _, o := range sg.Params.Order {
+		byMath = byMath || sg.mathChild(o.Attr) != nil
 	}
+	if sg.mathChild(sg.Params.Order[0].Attr) == nil {
+		sort := &task.Sort{
+			Attr:      sg.Params.Order[0].Attr,
+			Desc:      sg.Params.Order[0].Desc,
+			UidMatrix: sg.uidMatrix,
+		}
+		if !byMath {
+			sort.Offset = int32(sg.Params.Offset)
+			sort.Count = int32(sg.Params.Count)
+			for _, o := range sg.Params.Order[1:] {
+				sort.ThenBy = append(sort.ThenBy, &task.Order{Attr: o.Attr, Desc: o.Desc})
+			}
+		}
+		result, err := worker.SortOverNetwork(ctx, sort)
+		if err != nil {
+			return err
+		}
 
-	x.AssertTrue(len(result.UidMatrix) == len(sg.uidMatrix))
-	sg.uidMatrix = result.GetUidMatrix()
+		x.AssertTrue(len(result.UidMatrix) == len(sg.uidMatrix))
+		sg.uidMatrix = result.GetUidMatrix()
+	}
+	if byMath {
+		if err := sg.applyMultiOrder(ctx); err != nil {
+			return err
+		}
+	}
 
 	// Update sg.destUID. Iterate over the UID matrix (which is not sorted by
 	// UID). For each element in UID matrix, we do a binary search in the
@@ -757,6 +1245,12 @@ type outputNode interface {
 	SetUID(uid uint64)
 	SetXID(xid string)
 	IsEmpty() bool
+	// AddFacets adds the facets of the edge of attr, with "_" for the edge
+	// leading to this node.
+	AddFacets(attr string, keys []string, vals []types.Value)
+	// Normalize flattens the node for @normalize. It returns a row for each
+	// path to a leaf, with the values along that path.
+	Normalize() []outputNode
 }
 
 // protoOutputNode is the proto output for preTraverse.
@@ -787,6 +1281,72 @@ func (p *protoOutputNode) SetUID(uid uint64) { p.Node.Uid = uid }
 // SetXID sets XID of a protoOutputNode.
 func (p *protoOutputNode) SetXID(xid string) { p.Node.Xid = xid }
 
+// AddFacets adds the facets of an edge to protoOutputNode, as the properties
+// of a child named attr, under a child named @facets.
+func (p *protoOutputNode) AddFacets(attr string, keys []string, vals []types.Value) {
+	var fn *graph.Node
+	for _, c := range p.Node.Children {
+		if c.Attribute == "@facets" {
+			fn = c
+		}
+	}
+	if fn == nil {
+		fn = p.New("@facets").(*protoOutputNode).Node
+		p.Node.Children = append(p.Node.Children, fn)
+	}
+	ac := p.New(attr).(*protoOutputNode).Node
+	for i, k := range keys {
+		ac.Properties = append(ac.Properties, createProperty(k, vals[i]))
+	}
+	fn.Children = append(fn.Children, ac)
+}
+
+// Normalize flattens protoOutputNode into rows which only have properties.
+// The rows from the children with the same attribute are alternatives to each
+// other, and are combined with the rows from the other attributes.
+func (p *protoOutputNode) Normalize() []outputNode {
+	rows := [][]*graph.Property{p.Node.Properties}
+	var attrs []string
+	children := make(map[string][]*graph.Node)
+	for _, c := range p.Node.Children {
+		if c.Attribute == "@facets" {
+			continue
+		}
+		if _, ok := children[c.Attribute]; !ok {
+			attrs = append(attrs, c.Attribute)
+		}
+		children[c.Attribute] = append(children[c.Attribute], c)
+	}
+	for _, attr := range attrs {
+		var crows [][]*graph.Property
+		for _, c := range children[attr] {
+			for _, cr := range (&protoOutputNode{c}).Normalize() {
+				crows = append(crows, cr.(*protoOutputNode).Node.Properties)
+			}
+		}
+		if len(crows) == 0 {
+			continue
+		}
+		next := make([][]*graph.Property, 0, len(rows)*len(crows))
+		for _, r := range rows {
+			for _, cr := range crows {
+				row := make([]*graph.Property, 0, len(r)+len(cr))
+				row = append(row, r...)
+				next = append(next, append(row, cr...))
+			}
+		}
+		rows = next
+	}
+
+	out := make([]outputNode, 0, len(rows))
+	for _, r := range rows {
+		n := p.New(p.Node.Attribute).(*protoOutputNode)
+		n.Node.Properties = r
+		out = append(out, n)
+	}
+	return out
+}
+
 func (p *protoOutputNode) IsEmpty() bool {
 	if p.Node.Uid > 0 {
 		return false
@@ -800,6 +1360,47 @@ func (p *protoOutputNode) IsEmpty() bool {
 	return true
 }
 
+// preTraverseRoot adds a node for each of the root UIDs of sg to dst, along
+// with the aggregations over them, if any. The results of a full-text search
+// are added in order of relevance.
+func (sg *SubGraph) preTraverseRoot(dst outputNode) error {
+	uids := sg.DestUIDs.Uids
+	if len(sg.Params.Order) > 0 {
+		// The UIDs are in the order asked for in the single list at the root.
+		uids = sg.uidMatrix[0].Uids
+	} else if sg.scores != nil {
+		uids = sg.rankedUIDs()
+	}
+	for _, uid := range uids {
+		// For the root, the name is stored in Alias, not Attr.
+		n1 := dst.New(sg.Params.Alias)
+		if sg.Params.GetUID || sg.Params.isDebug {
+			n1.SetUID(uid)
+		}
+		if sg.Params.GetScore {
+			score := types.Float(sg.scores[uid])
+			n1.AddValue("_score_", &score)
+		}
+
+		if err := sg.preTraverse(uid, n1); err != nil {
+			if sg.Params.Cascade && err.Error() == "_INV_" {
+				continue
+			}
+			return err
+		}
+		if !sg.Params.Normalize {
+			dst.AddChild(sg.Params.Alias, n1)
+			continue
+		}
+		for _, row := range n1.Normalize() {
+			if !row.IsEmpty() {
+				dst.AddChild(sg.Params.Alias, row)
+			}
+		}
+	}
+	return sg.addAggregations(sg.DestUIDs, sg.Params.Alias, dst)
+}
+
 // ToProtocolBuffer does preorder traversal to build a proto buffer. We have
 // used postorder traversal before, but preorder seems simpler and faster for
 // most cases.
@@ -810,18 +1411,25 @@ func (sg *SubGraph) ToProtocolBuffer(l *Latency) (*graph.Node, error) {
 	}
 
 	n := seedNode.New("_root_")
-	for _, uid := range sg.DestUIDs.Uids {
-		// For the root, the name is stored in Alias, not Attr.
-		n1 := seedNode.New(sg.Params.Alias)
-		if sg.Params.GetUID || sg.Params.isDebug {
-			n1.SetUID(uid)
-		}
+	if err := sg.preTraverseRoot(n); err != nil {
+		return n.(*protoOutputNode).Node, err
+	}
+	l.ProtocolBuffer = time.Since(l.Start) - l.Parsing - l.Processing
+	return n.(*protoOutputNode).Node, nil
+}
 
-		if rerr := sg.preTraverse(uid, n1); rerr != nil {
-			return n.(*protoOutputNode).Node, rerr
+// ToProtocolBuffer builds a single proto buffer out of the SubGraphs of all
+// the query blocks in a request.
+func ToProtocolBuffer(l *Latency, sgl []*SubGraph) (*graph.Node, error) {
+	var seedNode *protoOutputNode
+	n := seedNode.New("_root_")
+	for _, sg := range sgl {
+		if sg.DestUIDs == nil {
+			continue
+		}
+		if err := sg.preTraverseRoot(n); err != nil {
+			return n.(*protoOutputNode).Node, err
 		}
-
-		n.AddChild(sg.Params.Alias, n1)
 	}
 	l.ProtocolBuffer = time.Since(l.Start) - l.Parsing - l.Processing
 	return n.(*protoOutputNode).Node, nil
@@ -864,6 +1472,71 @@ func (p *jsonOutputNode) SetXID(xid string) {
 	p.data["_xid_"] = xid
 }
 
+// AddFacets adds the facets of an edge to jsonOutputNode, as a map under
+// attr in the map of @facets.
+func (p *jsonOutputNode) AddFacets(attr string, keys []string, vals []types.Value) {
+	fm, ok := p.data["@facets"].(map[string]interface{})
+	if !ok {
+		fm = make(map[string]interface{})
+		p.data["@facets"] = fm
+	}
+	m := make(map[string]interface{}, len(keys))
+	for i, k := range keys {
+		m[k] = vals[i]
+	}
+	fm[attr] = m
+}
+
+// Normalize flattens jsonOutputNode into rows which only have values, like
+// protoOutputNode.Normalize.
+func (p *jsonOutputNode) Normalize() []outputNode {
+	row := make(map[string]interface{})
+	var attrs []string
+	for k, v := range p.data {
+		switch v.(type) {
+		case types.Value:
+			row[k] = v
+		case []map[string]interface{}:
+			attrs = append(attrs, k)
+		}
+	}
+	// Go over the children in a fixed order, so that the rows are too.
+	sort.Strings(attrs)
+
+	rows := []map[string]interface{}{row}
+	for _, attr := range attrs {
+		var crows []map[string]interface{}
+		for _, c := range p.data[attr].([]map[string]interface{}) {
+			for _, cr := range (&jsonOutputNode{c}).Normalize() {
+				crows = append(crows, cr.(*jsonOutputNode).data)
+			}
+		}
+		if len(crows) == 0 {
+			continue
+		}
+		next := make([]map[string]interface{}, 0, len(rows)*len(crows))
+		for _, r := range rows {
+			for _, cr := range crows {
+				m := make(map[string]interface{}, len(r)+len(cr))
+				for k, v := range r {
+					m[k] = v
+				}
+				for k, v := range cr {
+					m[k] = v
+				}
+				next = append(next, m)
+			}
+		}
+		rows = next
+	}
+
+	out := make([]outputNode, 0, len(rows))
+	for _, r := range rows {
+		out = append(out, &jsonOutputNode{r})
+	}
+	return out
+}
+
 func (p *jsonOutputNode) IsEmpty() bool {
 	return len(p.data) == 0
 }
@@ -871,23 +1544,107 @@ func (p *jsonOutputNode) IsEmpty() bool {
 // ToJSON converts the internal subgraph object to JSON format which is then\
 // sent to the HTTP client.
 func (sg *SubGraph) ToJSON(l *Latency) ([]byte, error) {
+	return ToJSON(l, []*SubGraph{sg})
+}
+
+// ToJSON converts the SubGraphs of all the query blocks in a request to a
+// single JSON object, with a key for each block.
+func ToJSON(l *Latency, sgl []*SubGraph) ([]byte, error) {
 	var seedNode *jsonOutputNode
 	n := seedNode.New("_root_")
-	for _, uid := range sg.DestUIDs.Uids {
-		// For the root, the name is stored in Alias, not Attr.
-		n1 := seedNode.New(sg.Params.Alias)
-		if sg.Params.GetUID || sg.Params.isDebug {
-			n1.SetUID(uid)
+	var isDebug bool
+	for _, sg := range sgl {
+		if sg.DestUIDs == nil {
+			continue
 		}
-
-		if err := sg.preTraverse(uid, n1); err != nil {
+		if err := sg.preTraverseRoot(n); err != nil {
 			return nil, err
 		}
-		n.AddChild(sg.Params.Alias, n1)
+		isDebug = isDebug || sg.Params.isDebug
 	}
 	res := n.(*jsonOutputNode).data
-	if sg.Params.isDebug {
+	if isDebug {
 		res["server_latency"] = l.ToMap()
 	}
 	return json.Marshal(res)
 }
+
+// ProcessQuery converts the query blocks in res to SubGraphs and processes
+// them in order. The UIDs stored in a variable by one block are used as the
+// root of the later blocks which need that variable.
+func ProcessQuery(ctx context.Context, res gql.Result) ([]*SubGraph, error) {
+	sgl, _, err := ProcessQueryWithVars(ctx, res)
+	return sgl, err
+}
+
+// ProcessQueryWithVars is like ProcessQuery, and also returns the UIDs stored
+// in each of the variables defined by the query, like f in f as friend.
+func ProcessQueryWithVars(ctx context.Context, res gql.Result) ([]*SubGraph,
+	map[string]*task.List, error) {
+	vars := make(map[string]*task.List)
+	sgl := make([]*SubGraph, 0, len(res.Query))
+	for _, gq := range res.Query {
+		sg, err := ToSubGraph(ctx, gq)
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(gq.NeedsVar) > 0 {
+			// Copy the UIDs, as processing can modify the lists in place.
+			uids := new(task.List)
+			if l, ok := vars[gq.NeedsVar]; ok {
+				uids.Uids = append(uids.Uids, l.Uids...)
+			}
+			sg.SrcUIDs = uids
+			sg.uidMatrix = []*task.List{uids}
+		}
+		sg.fillFilterVars(vars)
+
+		rch := make(chan error)
+		go ProcessGraph(ctx, sg, nil, rch)
+		if err = <-rch; err != nil {
+			return nil, nil, err
+		}
+		sg.populateVarMap(vars)
+		sgl = append(sgl, sg)
+	}
+	return sgl, vars, nil
+}
+
+// fillFilterVars adds the UIDs of the variables used by the uid_in filters in
+// sg and below to their SrcFunc.
+func (sg *SubGraph) fillFilterVars(vars map[string]*task.List) {
+	if len(sg.Params.NeedsVar) > 0 {
+		sg.SrcFunc = sg.SrcFunc[:1]
+		if l, ok := vars[sg.Params.NeedsVar]; ok {
+			for _, uid := range l.Uids {
+				sg.SrcFunc = append(sg.SrcFunc, fmt.Sprintf("%#x", uid))
+			}
+		}
+	}
+	for _, filter := range sg.Filters {
+		filter.fillFilterVars(vars)
+	}
+	for _, child := range sg.Children {
+		child.fillFilterVars(vars)
+	}
+}
+
+// populateVarMap stores the DestUIDs of sg and its children in vars, for the
+// ones which are assigned to a variable. The variables of the ones which
+// weren't processed, as there was nothing to process them for, are empty.
+func (sg *SubGraph) populateVarMap(vars map[string]*task.List) {
+	if len(sg.Params.Var) > 0 && sg.DestUIDs == nil {
+		if _, ok := vars[sg.Params.Var]; !ok {
+			vars[sg.Params.Var] = new(task.List)
+		}
+	} else if len(sg.Params.Var) > 0 {
+		if l, ok := vars[sg.Params.Var]; ok {
+			vars[sg.Params.Var] = algo.MergeSorted([]*task.List{l, sg.DestUIDs})
+		} else {
+			vars[sg.Params.Var] = sg.DestUIDs
+		}
+	}
+	for _, child := range sg.Children {
+		child.populateVarMap(vars)
+	}
+}
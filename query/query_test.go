@@ -83,6 +83,9 @@ const schemaStr = `
 scalar name:string @index
 scalar dob:date @index
 scalar loc:geo @index
+scalar description:string @index(fulltext)
+scalar credit:int @index
+scalar score:float @index
 `
 
 func addEdgeToValue(t *testing.T, ps *store.Store, attr string, src uint64,
@@ -112,6 +115,20 @@ func addEdgeToTypedValue(t *testing.T, ps *store.Store, attr string, src uint64,
 		l.AddMutationWithIndex(context.Background(), edge, posting.Set))
 }
 
+func addEdgeToLangValue(t *testing.T, ps *store.Store, attr string, src uint64,
+	value, lang string) {
+	edge := &task.DirectedEdge{
+		Value:  []byte(value),
+		Lang:   lang,
+		Label:  "testing",
+		Attr:   attr,
+		Entity: src,
+	}
+	l, _ := posting.GetOrCreate(posting.Key(src, attr))
+	require.NoError(t,
+		l.AddMutationWithIndex(context.Background(), edge, posting.Set))
+}
+
 func addEdgeToUID(t *testing.T, ps *store.Store, attr string, src uint64, dst uint64) {
 	edge := &task.DirectedEdge{
 		ValueId: dst,
@@ -124,6 +141,55 @@ func addEdgeToUID(t *testing.T, ps *store.Store, attr string, src uint64, dst ui
 		l.AddMutationWithIndex(context.Background(), edge, posting.Set))
 }
 
+func addFacetedEdgeToUID(t *testing.T, ps *store.Store, attr string, src uint64,
+	dst uint64, facets []*task.Facet) {
+	edge := &task.DirectedEdge{
+		ValueId: dst,
+		Label:   "testing",
+		Attr:    attr,
+		Entity:  src,
+		Facets:  facets,
+	}
+	l, _ := posting.GetOrCreate(posting.Key(src, attr))
+	require.NoError(t,
+		l.AddMutationWithIndex(context.Background(), edge, posting.Set))
+}
+
+func addFacetedEdgeToValue(t *testing.T, ps *store.Store, attr string, src uint64,
+	value string, facets []*task.Facet) {
+	edge := &task.DirectedEdge{
+		Value:  []byte(value),
+		Label:  "testing",
+		Attr:   attr,
+		Entity: src,
+		Facets: facets,
+	}
+	l, _ := posting.GetOrCreate(posting.Key(src, attr))
+	require.NoError(t,
+		l.AddMutationWithIndex(context.Background(), edge, posting.Set))
+}
+
+// makeFacet returns a facet of the given type, parsed from text.
+func makeFacet(t *testing.T, key string, typ types.TypeID, text string) *task.Facet {
+	v := types.ValueForType(typ)
+	require.NoError(t, v.UnmarshalText([]byte(text)))
+	b, err := v.MarshalBinary()
+	require.NoError(t, err)
+	return &task.Facet{Key: key, Value: b, ValType: uint32(typ)}
+}
+
+func delEdgeToUID(t *testing.T, ps *store.Store, attr string, src uint64, dst uint64) {
+	edge := &task.DirectedEdge{
+		ValueId: dst,
+		Label:   "testing",
+		Attr:    attr,
+		Entity:  src,
+	}
+	l, _ := posting.GetOrCreate(posting.Key(src, attr))
+	require.NoError(t,
+		l.AddMutationWithIndex(context.Background(), edge, posting.Del))
+}
+
 func populateGraph(t *testing.T) (string, string, *store.Store) {
 	// logrus.SetLevel(logrus.DebugLevel)
 	dir, err := ioutil.TempDir("", "storetest_")
@@ -140,17 +206,37 @@ func populateGraph(t *testing.T) (string, string, *store.Store) {
 	dir2, err := ioutil.TempDir("", "wal_")
 	require.NoError(t, err)
 	worker.StartRaftNodes(dir2)
+	// The leader rebuilds the indexes of the new store in the background.
+	for _, attr := range schema.IndexedFields() {
+		for posting.IndexStale(attr) {
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
 
 	// So, user we're interested in has uid: 1.
 	// She has 5 friends: 23, 24, 25, 31, and 101
-	addEdgeToUID(t, ps, "friend", 1, 23)
-	addEdgeToUID(t, ps, "friend", 1, 24)
-	addEdgeToUID(t, ps, "friend", 1, 25)
+	// Most of the friendships have facets.
+	addFacetedEdgeToUID(t, ps, "friend", 1, 23, []*task.Facet{
+		makeFacet(t, "close", types.BoolID, "true"),
+		makeFacet(t, "since", types.Int32ID, "2006"),
+	})
+	addFacetedEdgeToUID(t, ps, "friend", 1, 24, []*task.Facet{
+		makeFacet(t, "close", types.BoolID, "false"),
+		makeFacet(t, "since", types.Int32ID, "2004"),
+	})
+	addFacetedEdgeToUID(t, ps, "friend", 1, 25, []*task.Facet{
+		makeFacet(t, "close", types.BoolID, "true"),
+		makeFacet(t, "since", types.Int32ID, "2010"),
+	})
 	addEdgeToUID(t, ps, "friend", 1, 31)
-	addEdgeToUID(t, ps, "friend", 1, 101)
+	addFacetedEdgeToUID(t, ps, "friend", 1, 101, []*task.Facet{
+		makeFacet(t, "since", types.Int32ID, "2001"),
+	})
 
 	// Now let's add a few properties for the main user.
-	addEdgeToValue(t, ps, "name", 1, "Michonne")
+	addFacetedEdgeToValue(t, ps, "name", 1, "Michonne", []*task.Facet{
+		makeFacet(t, "origin", types.StringID, "french"),
+	})
 	addEdgeToValue(t, ps, "gender", 1, "female")
 	var coord types.Geo
 	err = coord.UnmarshalText([]byte("{\"Type\":\"Point\", \"Coordinates\":[1.1,2.0]}"))
@@ -184,27 +270,42 @@ func populateGraph(t *testing.T) (string, string, *store.Store) {
 	addEdgeToValue(t, ps, "dob", 25, "1909-01-10")
 	addEdgeToValue(t, ps, "dob", 31, "1901-01-15")
 
+	addEdgeToValue(t, ps, "credit", 23, "-20")
+	addEdgeToValue(t, ps, "credit", 24, "5")
+	addEdgeToValue(t, ps, "credit", 25, "-3")
+	addEdgeToValue(t, ps, "credit", 31, "100")
+
+	addEdgeToValue(t, ps, "score", 23, "-1.5")
+	addEdgeToValue(t, ps, "score", 24, "0.5")
+	addEdgeToValue(t, ps, "score", 25, "-0.25")
+	addEdgeToValue(t, ps, "score", 31, "2.0")
+
+	addEdgeToValue(t, ps, "description", 1,
+		"Michonne carries a katana and walks with two chained walkers")
+	addEdgeToValue(t, ps, "description", 23,
+		"Rick was a sheriff's deputy who leads the group of survivors")
+	addEdgeToValue(t, ps, "description", 24, "Glenn was a pizza delivery boy")
+	addEdgeToValue(t, ps, "description", 25, "Daryl hunts walkers with a crossbow")
+	addEdgeToValue(t, ps, "description", 31,
+		"Andrea is a civil rights lawyer who learns to shoot walkers and walkers")
+
 	time.Sleep(200 * time.Millisecond) // Let the index process jobs from channel.
 	return dir, dir2, ps
 }
 
 func processToJSON(t *testing.T, query string) string {
-	gq, _, err := gql.Parse(query)
+	res, err := gql.Parse(query)
 	require.NoError(t, err)
 
 	ctx := context.Background()
-	sg, err := ToSubGraph(ctx, gq)
-	require.NoError(t, err)
-	sg.DebugPrint("")
-
-	ch := make(chan error)
-	go ProcessGraph(ctx, sg, nil, ch)
-	err = <-ch
+	sgl, err := ProcessQuery(ctx, res)
 	require.NoError(t, err)
-	sg.DebugPrint("")
+	for _, sg := range sgl {
+		sg.DebugPrint("")
+	}
 
 	var l Latency
-	js, err := sg.ToJSON(&l)
+	js, err := ToJSON(&l, sgl)
 	require.NoError(t, err)
 	return string(js)
 }
@@ -360,8 +461,9 @@ func TestCountError1(t *testing.T) {
 			}
 		}
 	`
-	gq, _, err := gql.Parse(query)
+	res, err := gql.Parse(query)
 	require.NoError(t, err)
+	gq := res.Query[0]
 
 	ctx := context.Background()
 	_, err = ToSubGraph(ctx, gq)
@@ -384,8 +486,9 @@ func TestCountError2(t *testing.T) {
 			}
 		}
 	`
-	gq, _, err := gql.Parse(query)
+	res, err := gql.Parse(query)
 	require.NoError(t, err)
+	gq := res.Query[0]
 
 	ctx := context.Background()
 	_, err = ToSubGraph(ctx, gq)
@@ -410,8 +513,9 @@ func TestProcessGraph(t *testing.T) {
 			}
 		}
 	`
-	gq, _, err := gql.Parse(query)
+	res, err := gql.Parse(query)
 	require.NoError(t, err)
+	gq := res.Query[0]
 
 	ctx := context.Background()
 	sg, err := ToSubGraph(ctx, gq)
@@ -463,8 +567,9 @@ func TestToJSON(t *testing.T) {
 		}
 	`
 
-	gq, _, err := gql.Parse(query)
+	res, err := gql.Parse(query)
 	require.NoError(t, err)
+	gq := res.Query[0]
 
 	ctx := context.Background()
 	sg, err := ToSubGraph(ctx, gq)
@@ -499,8 +604,9 @@ func TestToJSONFilter(t *testing.T) {
 		}
 	`
 
-	gq, _, err := gql.Parse(query)
+	res, err := gql.Parse(query)
 	require.NoError(t, err)
+	gq := res.Query[0]
 
 	ctx := context.Background()
 	sg, err := ToSubGraph(ctx, gq)
@@ -536,8 +642,9 @@ func TestToJSONFilterAllOf(t *testing.T) {
 		}
 	`
 
-	gq, _, err := gql.Parse(query)
+	res, err := gql.Parse(query)
 	require.NoError(t, err)
+	gq := res.Query[0]
 
 	ctx := context.Background()
 	sg, err := ToSubGraph(ctx, gq)
@@ -572,8 +679,9 @@ func TestToJSONFilterUID(t *testing.T) {
 		}
 	`
 
-	gq, _, err := gql.Parse(query)
+	res, err := gql.Parse(query)
 	require.NoError(t, err)
+	gq := res.Query[0]
 
 	ctx := context.Background()
 	sg, err := ToSubGraph(ctx, gq)
@@ -608,8 +716,9 @@ func TestToJSONFilterOr(t *testing.T) {
 		}
 	`
 
-	gq, _, err := gql.Parse(query)
+	res, err := gql.Parse(query)
 	require.NoError(t, err)
+	gq := res.Query[0]
 
 	ctx := context.Background()
 	sg, err := ToSubGraph(ctx, gq)
@@ -644,8 +753,9 @@ func TestToJSONFilterOrCount(t *testing.T) {
 		}
 	`
 
-	gq, _, err := gql.Parse(query)
+	res, err := gql.Parse(query)
 	require.NoError(t, err)
+	gq := res.Query[0]
 
 	ctx := context.Background()
 	sg, err := ToSubGraph(ctx, gq)
@@ -680,8 +790,9 @@ func TestToJSONFilterOrFirst(t *testing.T) {
 		}
 	`
 
-	gq, _, err := gql.Parse(query)
+	res, err := gql.Parse(query)
 	require.NoError(t, err)
+	gq := res.Query[0]
 
 	ctx := context.Background()
 	sg, err := ToSubGraph(ctx, gq)
@@ -716,8 +827,9 @@ func TestToJSONFilterOrOffset(t *testing.T) {
 		}
 	`
 
-	gq, _, err := gql.Parse(query)
+	res, err := gql.Parse(query)
 	require.NoError(t, err)
+	gq := res.Query[0]
 
 	ctx := context.Background()
 	sg, err := ToSubGraph(ctx, gq)
@@ -753,8 +865,9 @@ func TestToJSONFirstOffset(t *testing.T) {
 		}
 	`
 
-	gq, _, err := gql.Parse(query)
+	res, err := gql.Parse(query)
 	require.NoError(t, err)
+	gq := res.Query[0]
 
 	ctx := context.Background()
 	sg, err := ToSubGraph(ctx, gq)
@@ -789,8 +902,9 @@ func TestToJSONFilterOrFirstOffset(t *testing.T) {
 		}
 	`
 
-	gq, _, err := gql.Parse(query)
+	res, err := gql.Parse(query)
 	require.NoError(t, err)
+	gq := res.Query[0]
 
 	ctx := context.Background()
 	sg, err := ToSubGraph(ctx, gq)
@@ -825,8 +939,9 @@ func TestToJSONFilterOrFirstOffsetCount(t *testing.T) {
 		}
 	`
 
-	gq, _, err := gql.Parse(query)
+	res, err := gql.Parse(query)
 	require.NoError(t, err)
+	gq := res.Query[0]
 
 	ctx := context.Background()
 	sg, err := ToSubGraph(ctx, gq)
@@ -863,8 +978,9 @@ func TestToJSONFilterOrFirstNegative(t *testing.T) {
 		}
 	`
 
-	gq, _, err := gql.Parse(query)
+	res, err := gql.Parse(query)
 	require.NoError(t, err)
+	gq := res.Query[0]
 
 	ctx := context.Background()
 	sg, err := ToSubGraph(ctx, gq)
@@ -899,8 +1015,9 @@ func TestToJSONFilterAnd(t *testing.T) {
 		}
 	`
 
-	gq, _, err := gql.Parse(query)
+	res, err := gql.Parse(query)
 	require.NoError(t, err)
+	gq := res.Query[0]
 
 	ctx := context.Background()
 	sg, err := ToSubGraph(ctx, gq)
@@ -949,8 +1066,9 @@ func TestToProto(t *testing.T) {
 		}
   `
 
-	gq, _, err := gql.Parse(query)
+	res, err := gql.Parse(query)
 	require.NoError(t, err)
+	gq := res.Query[0]
 
 	ctx := context.Background()
 	sg, err := ToSubGraph(ctx, gq)
@@ -1078,8 +1196,9 @@ func TestSchema(t *testing.T) {
 		}
   `
 
-	gq, _, err := gql.Parse(query)
+	res, err := gql.Parse(query)
 	require.NoError(t, err)
+	gq := res.Query[0]
 
 	ctx := context.Background()
 	sg, err := ToSubGraph(ctx, gq)
@@ -1137,8 +1256,9 @@ func TestToProtoFilter(t *testing.T) {
 		}
 	`
 
-	gq, _, err := gql.Parse(query)
+	res, err := gql.Parse(query)
 	require.NoError(t, err)
+	gq := res.Query[0]
 
 	ctx := context.Background()
 	sg, err := ToSubGraph(ctx, gq)
@@ -1200,8 +1320,9 @@ func TestToProtoFilterOr(t *testing.T) {
 		}
 	`
 
-	gq, _, err := gql.Parse(query)
+	res, err := gql.Parse(query)
 	require.NoError(t, err)
+	gq := res.Query[0]
 
 	ctx := context.Background()
 	sg, err := ToSubGraph(ctx, gq)
@@ -1272,8 +1393,9 @@ func TestToProtoFilterAnd(t *testing.T) {
 		}
 	`
 
-	gq, _, err := gql.Parse(query)
+	res, err := gql.Parse(query)
 	require.NoError(t, err)
+	gq := res.Query[0]
 
 	ctx := context.Background()
 	sg, err := ToSubGraph(ctx, gq)
@@ -1327,8 +1449,9 @@ func TestToJSONOrder(t *testing.T) {
 		}
 	`
 
-	gq, _, err := gql.Parse(query)
+	res, err := gql.Parse(query)
 	require.NoError(t, err)
+	gq := res.Query[0]
 
 	ctx := context.Background()
 	sg, err := ToSubGraph(ctx, gq)
@@ -1366,8 +1489,9 @@ func TestToJSONOrderOffset(t *testing.T) {
 		}
 	`
 
-	gq, _, err := gql.Parse(query)
+	res, err := gql.Parse(query)
 	require.NoError(t, err)
+	gq := res.Query[0]
 
 	ctx := context.Background()
 	sg, err := ToSubGraph(ctx, gq)
@@ -1405,8 +1529,9 @@ func TestToJSONOrderOffsetCount(t *testing.T) {
 		}
 	`
 
-	gq, _, err := gql.Parse(query)
+	res, err := gql.Parse(query)
 	require.NoError(t, err)
+	gq := res.Query[0]
 
 	ctx := context.Background()
 	sg, err := ToSubGraph(ctx, gq)
@@ -1444,8 +1569,9 @@ func TestToProtoOrder(t *testing.T) {
 		}
 	`
 
-	gq, _, err := gql.Parse(query)
+	res, err := gql.Parse(query)
 	require.NoError(t, err)
+	gq := res.Query[0]
 
 	ctx := context.Background()
 	sg, err := ToSubGraph(ctx, gq)
@@ -1535,8 +1661,9 @@ func TestToProtoOrderCount(t *testing.T) {
 		}
 	`
 
-	gq, _, err := gql.Parse(query)
+	res, err := gql.Parse(query)
 	require.NoError(t, err)
+	gq := res.Query[0]
 
 	ctx := context.Background()
 	sg, err := ToSubGraph(ctx, gq)
@@ -1608,8 +1735,9 @@ func TestToProtoOrderOffsetCount(t *testing.T) {
 		}
 	`
 
-	gq, _, err := gql.Parse(query)
+	res, err := gql.Parse(query)
 	require.NoError(t, err)
+	gq := res.Query[0]
 
 	ctx := context.Background()
 	sg, err := ToSubGraph(ctx, gq)
@@ -1662,6 +1790,246 @@ children: <
 	require.EqualValues(t, expectedPb, proto.MarshalTextString(pb))
 }
 
+func TestToJSONOrderDesc(t *testing.T) {
+	dir, dir2, ps := populateGraph(t)
+	defer os.RemoveAll(dir)
+	defer os.RemoveAll(dir2)
+	defer ps.Close()
+
+	query := `
+		{
+			me(_uid_:0x01) {
+				friend(orderdesc: dob, first: 2, offset: 1) {
+					name
+				}
+			}
+		}
+	`
+	js := processToJSON(t, query)
+	require.JSONEq(t,
+		`{"me":[{"friend":[{"name":"Glenn Rhee"},{"name":"Daryl Dixon"}]}]}`,
+		js)
+}
+
+func TestToJSONFilterOrderDesc(t *testing.T) {
+	dir, dir2, ps := populateGraph(t)
+	defer os.RemoveAll(dir)
+	defer os.RemoveAll(dir2)
+	defer ps.Close()
+
+	// Rick is the youngest friend, but is filtered out.
+	query := `
+		{
+			me(_uid_:0x01) {
+				friend(orderdesc: dob, first: 1) @filter(anyof("name", "Andrea Rhee")) {
+					name
+				}
+			}
+		}
+	`
+	js := processToJSON(t, query)
+	require.JSONEq(t,
+		`{"me":[{"friend":[{"name":"Glenn Rhee"}]}]}`,
+		js)
+}
+
+func TestToJSONOrderString(t *testing.T) {
+	dir, dir2, ps := populateGraph(t)
+	defer os.RemoveAll(dir)
+	defer os.RemoveAll(dir2)
+	defer ps.Close()
+
+	query := `
+		{
+			me(_uid_:0x01) {
+				friend(orderasc: name) {
+					name
+				}
+			}
+		}
+	`
+	js := processToJSON(t, query)
+	require.JSONEq(t,
+		`{"me":[{"friend":[{"name":"Andrea"},{"name":"Daryl Dixon"},{"name":"Glenn Rhee"},{"name":"Rick Grimes"}]}]}`,
+		js)
+}
+
+func TestToJSONOrderMultiple(t *testing.T) {
+	dir, dir2, ps := populateGraph(t)
+	defer os.RemoveAll(dir)
+	defer os.RemoveAll(dir2)
+	defer ps.Close()
+
+	// Glenn and Daryl now share their date of birth.
+	addEdgeToValue(t, ps, "dob", 24, "1909-01-10")
+	time.Sleep(50 * time.Millisecond) // Let indexing finish.
+
+	query := `
+		{
+			me(_uid_:0x01) {
+				friend(orderasc: dob, orderdesc: name) {
+					name
+				}
+			}
+		}
+	`
+	js := processToJSON(t, query)
+	require.JSONEq(t,
+		`{"me":[{"friend":[{"name":"Andrea"},{"name":"Glenn Rhee"},{"name":"Daryl Dixon"},{"name":"Rick Grimes"}]}]}`,
+		js)
+
+	query = `
+		{
+			me(_uid_:0x01) {
+				friend(orderasc: dob, orderasc: name, first: -2) {
+					name
+				}
+			}
+		}
+	`
+	js = processToJSON(t, query)
+	require.JSONEq(t,
+		`{"me":[{"friend":[{"name":"Glenn Rhee"},{"name":"Rick Grimes"}]}]}`,
+		js)
+}
+
+func TestCountAlongside(t *testing.T) {
+	dir, dir2, _ := populateGraph(t)
+	defer os.RemoveAll(dir)
+	defer os.RemoveAll(dir2)
+
+	query := `
+		{
+			me(_uid_:0x01) {
+				name
+				count(friend)
+				friend {
+					name
+				}
+				numGender: count(gender)
+			}
+		}
+	`
+	js := processToJSON(t, query)
+	require.JSONEq(t,
+		`{"me":[{"count(friend)":5,"friend":[{"name":"Rick Grimes"},{"name":"Glenn Rhee"},{"name":"Daryl Dixon"},{"name":"Andrea"}],"name":"Michonne","numGender":1}]}`,
+		js)
+}
+
+func TestFilterCount(t *testing.T) {
+	dir, dir2, ps := populateGraph(t)
+	defer os.RemoveAll(dir)
+	defer os.RemoveAll(dir2)
+	defer ps.Close()
+
+	addEdgeToUID(t, ps, "friend", 23, 24)
+	addEdgeToUID(t, ps, "friend", 23, 25)
+	addEdgeToUID(t, ps, "friend", 24, 25)
+
+	query := `
+		{
+			me(_uid_:0x01) {
+				friend @filter(gt(count(friend), 0)) {
+					name
+					count(friend)
+				}
+			}
+		}
+	`
+	js := processToJSON(t, query)
+	require.JSONEq(t,
+		`{"me":[{"friend":[{"count(friend)":2,"name":"Rick Grimes"},{"count(friend)":1,"name":"Glenn Rhee"}]}]}`,
+		js)
+
+	query = `
+		{
+			me(_uid_:0x01) {
+				friend @filter(eq(count(friend), 0) && anyof("name", "Daryl Andrea")) {
+					name
+				}
+			}
+		}
+	`
+	js = processToJSON(t, query)
+	require.JSONEq(t,
+		`{"me":[{"friend":[{"name":"Daryl Dixon"},{"name":"Andrea"}]}]}`,
+		js)
+}
+
+func TestHas(t *testing.T) {
+	dir, dir2, ps := populateGraph(t)
+	defer os.RemoveAll(dir)
+	defer os.RemoveAll(dir2)
+	defer ps.Close()
+
+	addEdgeToUID(t, ps, "friend", 24, 25)
+
+	query := `
+		{
+			me(has(age)) {
+				name
+			}
+		}
+	`
+	js := processToJSON(t, query)
+	require.JSONEq(t,
+		`{"me":[{"name":"Michonne"},{"name":"Rick Grimes"}]}`,
+		js)
+
+	query = `
+		{
+			me(_uid_:0x01) {
+				friend @filter(has(age) || has(friend)) {
+					name
+				}
+			}
+		}
+	`
+	js = processToJSON(t, query)
+	require.JSONEq(t,
+		`{"me":[{"friend":[{"name":"Rick Grimes"},{"name":"Glenn Rhee"}]}]}`,
+		js)
+
+	res, err := gql.Parse(`{ me(has(age, "15")) { name } }`)
+	require.NoError(t, err)
+	_, err = ProcessQuery(context.Background(), res)
+	require.Error(t, err)
+}
+
+func TestFilterNot(t *testing.T) {
+	dir, dir2, _ := populateGraph(t)
+	defer os.RemoveAll(dir)
+	defer os.RemoveAll(dir2)
+
+	query := `
+		{
+			me(_uid_:0x01) {
+				friend @filter(not anyof("name", "Andrea Rick")) {
+					name
+				}
+			}
+		}
+	`
+	js := processToJSON(t, query)
+	require.JSONEq(t,
+		`{"me":[{"friend":[{"name":"Glenn Rhee"},{"name":"Daryl Dixon"}]}]}`,
+		js)
+
+	query = `
+		{
+			me(_uid_:0x01) {
+				friend @filter(not (anyof("name", "Andrea") || has(age)) && has(dob)) {
+					name
+				}
+			}
+		}
+	`
+	js = processToJSON(t, query)
+	require.JSONEq(t,
+		`{"me":[{"friend":[{"name":"Glenn Rhee"},{"name":"Daryl Dixon"}]}]}`,
+		js)
+}
+
 func TestSchema1(t *testing.T) {
 	require.NoError(t, schema.Parse("test_schema"))
 
@@ -1730,8 +2098,9 @@ func TestToProtoMultiRoot(t *testing.T) {
     }
   `
 
-	gq, _, err := gql.Parse(query)
+	res, err := gql.Parse(query)
 	require.NoError(t, err)
+	gq := res.Query[0]
 
 	ctx := context.Background()
 	sg, err := ToSubGraph(ctx, gq)
@@ -1809,7 +2178,1531 @@ children: <
 //require.EqualValues(t, `{"me":[{"gender":"female","name":"Michonne"}]}`, string(js))
 //}
 
-func TestMain(m *testing.M) {
-	x.Init()
-	os.Exit(m.Run())
+func TestRecurse(t *testing.T) {
+	dir, dir2, ps := populateGraph(t)
+	defer os.RemoveAll(dir)
+	defer os.RemoveAll(dir2)
+
+	// Rick is friends with Michonne, who has already been visited. Glenn has a
+	// friend Carl, who has a friend Lori one more level down.
+	addEdgeToUID(t, ps, "friend", 23, 1)
+	addEdgeToUID(t, ps, "friend", 24, 32)
+	addEdgeToValue(t, ps, "name", 32, "Carl")
+	addEdgeToUID(t, ps, "friend", 32, 33)
+	addEdgeToValue(t, ps, "name", 33, "Lori")
+
+	query := `
+		{
+			me(_uid_:0x01) @recurse(depth: 3) {
+				name
+				friend
+			}
+		}
+	`
+	js := processToJSON(t, query)
+	require.JSONEq(t,
+		`{"me":[{"friend":[{"name":"Rick Grimes"},{"friend":[{"name":"Carl"}],"name":"Glenn Rhee"},{"name":"Daryl Dixon"},{"name":"Andrea"}],"name":"Michonne"}]}`,
+		js)
+
+	query = `
+		{
+			me(_uid_:0x01) @recurse {
+				name
+				friend
+			}
+		}
+	`
+	js = processToJSON(t, query)
+	require.JSONEq(t,
+		`{"me":[{"friend":[{"name":"Rick Grimes"},{"friend":[{"friend":[{"name":"Lori"}],"name":"Carl"}],"name":"Glenn Rhee"},{"name":"Daryl Dixon"},{"name":"Andrea"}],"name":"Michonne"}]}`,
+		js)
+}
+
+func TestRecurseNestedError(t *testing.T) {
+	query := `
+		{
+			me(_uid_:0x01) @recurse {
+				friend {
+					name
+				}
+			}
+		}
+	`
+	res, err := gql.Parse(query)
+	require.NoError(t, err)
+	gq := res.Query[0]
+	_, err = ToSubGraph(context.Background(), gq)
+	require.Error(t, err)
+}
+
+func TestShortestPath(t *testing.T) {
+	dir, dir2, ps := populateGraph(t)
+	defer os.RemoveAll(dir)
+	defer os.RemoveAll(dir2)
+	defer ps.Close()
+
+	// Andrea has a friend 1001, who has a sibling 1003. Glenn reaches 1001
+	// too, but only through 1002.
+	addEdgeToUID(t, ps, "friend", 31, 1001)
+	addEdgeToUID(t, ps, "friend", 24, 1002)
+	addEdgeToUID(t, ps, "friend", 1002, 1001)
+	addEdgeToUID(t, ps, "sibling", 1001, 1003)
+
+	query := `
+		{
+			shortest(from: 0x01, to: 1003) {
+				friend
+				sibling
+			}
+		}
+	`
+	js := processToJSON(t, query)
+	require.JSONEq(t,
+		`{"shortest":[{"_uid_":"0x1","friend":[{"_uid_":"0x1f","friend":[{"_uid_":"0x3e9","sibling":[{"_uid_":"0x3eb"}]}]}]}]}`,
+		js)
+
+	// There is no path to 1003 without sibling.
+	query = `
+		{
+			shortest(from: 0x01, to: 1003) {
+				friend
+			}
+		}
+	`
+	js = processToJSON(t, query)
+	require.JSONEq(t, `{}`, js)
+}
+
+func TestShortestPathBidirectional(t *testing.T) {
+	dir, dir2, ps := populateGraph(t)
+	defer os.RemoveAll(dir)
+	defer os.RemoveAll(dir2)
+	defer ps.Close()
+
+	require.NoError(t, schema.ParseBytes([]byte(`
+type Place {
+  road: Place @reverse
+}
+`)))
+	// Both 2000 and 2001 lead to 50 places, but only 3025 leads on to 4001.
+	// The same edges are added for path, which has no reverse edges.
+	for _, attr := range []string{"road", "path"} {
+		addEdgeToUID(t, ps, attr, 1, 2000)
+		addEdgeToUID(t, ps, attr, 1, 2001)
+		for i := uint64(0); i < 50; i++ {
+			addEdgeToUID(t, ps, attr, 2000, 3000+i)
+			addEdgeToUID(t, ps, attr, 2001, 3100+i)
+		}
+		addEdgeToUID(t, ps, attr, 3025, 4000)
+		addEdgeToUID(t, ps, attr, 4000, 4001)
+	}
+
+	// Going backwards from 4001 reaches few places.
+	query := `
+		{
+			shortest(from: 0x01, to: 4001, maxvisited: 30) {
+				road
+			}
+		}
+	`
+	js := processToJSON(t, query)
+	require.JSONEq(t,
+		`{"shortest":[{"_uid_":"0x1","road":[{"_uid_":"0x7d0","road":[{"_uid_":"0xbd1","road":[{"_uid_":"0xfa0","road":[{"_uid_":"0xfa1"}]}]}]}]}]}`,
+		js)
+
+	// Going forwards only reaches all of them.
+	query = `
+		{
+			shortest(from: 0x01, to: 4001, maxvisited: 30) {
+				path
+			}
+		}
+	`
+	res, err := gql.Parse(query)
+	require.NoError(t, err)
+	_, err = ProcessQuery(context.Background(), res)
+	require.Error(t, err)
+
+	// The path has four edges.
+	query = `
+		{
+			shortest(from: 0x01, to: 4001, depth: 3) {
+				road
+			}
+		}
+	`
+	js = processToJSON(t, query)
+	require.JSONEq(t, `{}`, js)
+}
+
+func TestShortestPathWeighted(t *testing.T) {
+	dir, dir2, ps := populateGraph(t)
+	defer os.RemoveAll(dir)
+	defer os.RemoveAll(dir2)
+	defer ps.Close()
+
+	edges := []struct {
+		from, to uint64
+		distance string
+	}{
+		{1, 5001, "10"},
+		{1, 5002, "2"},
+		{5002, 5003, "2"},
+		{5003, 5001, "3"},
+		{5001, 5004, "1"},
+	}
+	for _, e := range edges {
+		addFacetedEdgeToUID(t, ps, "highway", e.from, e.to, []*task.Facet{
+			makeFacet(t, "distance", types.Int32ID, e.distance),
+		})
+	}
+
+	query := `
+		{
+			shortest(from: 0x01, to: 5004, weight: distance) {
+				highway
+			}
+		}
+	`
+	js := processToJSON(t, query)
+	require.JSONEq(t,
+		`{"shortest":[{"_uid_":"0x1","highway":[{"_uid_":"0x138a","highway":[{"_uid_":"0x138b","highway":[{"_uid_":"0x1389","highway":[{"_uid_":"0x138c"}]}]}]}]}]}`,
+		js)
+
+	// Without weights, the path with the fewest edges is taken.
+	query = `
+		{
+			shortest(from: 0x01, to: 5004) {
+				highway
+			}
+		}
+	`
+	js = processToJSON(t, query)
+	require.JSONEq(t,
+		`{"shortest":[{"_uid_":"0x1","highway":[{"_uid_":"0x1389","highway":[{"_uid_":"0x138c"}]}]}]}`,
+		js)
+}
+
+func TestAggregator(t *testing.T) {
+	dir, dir2, ps := populateGraph(t)
+	defer os.RemoveAll(dir)
+	defer os.RemoveAll(dir2)
+	defer ps.Close()
+
+	for uid, rate := range map[uint64]float64{23: 1.5, 24: 2.0, 25: 4.0} {
+		data, err := types.Float(rate).MarshalBinary()
+		require.NoError(t, err)
+		addEdgeToTypedValue(t, ps, "survival_rate", uid, types.FloatID, data)
+	}
+
+	query := `
+		{
+			me(_uid_:0x01) {
+				name
+				friend {
+					min(dob)
+					max(dob)
+					min(survival_rate)
+					max(survival_rate)
+					sum(survival_rate)
+					avg(survival_rate)
+				}
+			}
+		}
+	`
+	js := processToJSON(t, query)
+	require.JSONEq(t,
+		`{"me":[{"friend":[{"avg(survival_rate)":2.5,"max(dob)":"1910-01-02","max(survival_rate)":4,"min(dob)":"1901-01-15","min(survival_rate)":1.5,"sum(survival_rate)":7.5}],"name":"Michonne"}]}`,
+		js)
+}
+
+func TestAggregatorWithFields(t *testing.T) {
+	dir, dir2, ps := populateGraph(t)
+	defer os.RemoveAll(dir)
+	defer os.RemoveAll(dir2)
+	defer ps.Close()
+
+	query := `
+		{
+			me(_uid_:0x01) {
+				friend {
+					name
+					oldest: min(dob)
+				}
+			}
+		}
+	`
+	js := processToJSON(t, query)
+	require.JSONEq(t,
+		`{"me":[{"friend":[{"name":"Rick Grimes"},{"name":"Glenn Rhee"},{"name":"Daryl Dixon"},{"name":"Andrea"},{"oldest":"1901-01-15"}]}]}`,
+		js)
+}
+
+func TestMultipleBlocks(t *testing.T) {
+	dir, dir2, ps := populateGraph(t)
+	defer os.RemoveAll(dir)
+	defer os.RemoveAll(dir2)
+	defer ps.Close()
+
+	query := `
+		{
+			me(_uid_:0x01) {
+				name
+			}
+			you(_uid_:0x17) {
+				name
+			}
+		}
+	`
+	js := processToJSON(t, query)
+	require.JSONEq(t,
+		`{"me":[{"name":"Michonne"}],"you":[{"name":"Rick Grimes"}]}`, js)
+}
+
+func TestUIDVariable(t *testing.T) {
+	dir, dir2, ps := populateGraph(t)
+	defer os.RemoveAll(dir)
+	defer os.RemoveAll(dir2)
+	defer ps.Close()
+
+	query := `
+		{
+			me(_uid_:0x01) {
+				name
+				f as friend
+			}
+			friends(id: var(f)) {
+				name
+				dob
+			}
+		}
+	`
+	js := processToJSON(t, query)
+	require.JSONEq(t,
+		`{"me":[{"name":"Michonne"}],"friends":[{"dob":"1910-01-02","name":"Rick Grimes"},{"dob":"1909-05-05","name":"Glenn Rhee"},{"dob":"1909-01-10","name":"Daryl Dixon"},{"dob":"1901-01-15","name":"Andrea"},{}]}`,
+		js)
+}
+
+func TestUIDVariableRoot(t *testing.T) {
+	dir, dir2, ps := populateGraph(t)
+	defer os.RemoveAll(dir)
+	defer os.RemoveAll(dir2)
+	defer ps.Close()
+
+	query := `
+		{
+			m as me(_uid_:0x17) {
+				name
+			}
+			again(_uid_: var(m)) {
+				_uid_
+			}
+		}
+	`
+	js := processToJSON(t, query)
+	require.JSONEq(t,
+		`{"me":[{"name":"Rick Grimes"}],"again":[{"_uid_":"0x17"}]}`, js)
+}
+
+func TestReverseEdges(t *testing.T) {
+	dir, dir2, ps := populateGraph(t)
+	defer os.RemoveAll(dir)
+	defer os.RemoveAll(dir2)
+	defer ps.Close()
+
+	require.NoError(t, schema.ParseBytes([]byte(`
+type Follower {
+  follows: Follower @reverse
+}
+`)))
+	addEdgeToUID(t, ps, "follows", 23, 1)
+	addEdgeToUID(t, ps, "follows", 24, 1)
+	addEdgeToUID(t, ps, "follows", 1, 31)
+
+	query := `
+		{
+			me(_uid_:0x01) {
+				name
+				~follows {
+					name
+				}
+				follows {
+					name
+				}
+			}
+		}
+	`
+	js := processToJSON(t, query)
+	require.JSONEq(t,
+		`{"me":[{"follows":[{"name":"Andrea"}],"name":"Michonne","~follows":[{"name":"Rick Grimes"},{"name":"Glenn Rhee"}]}]}`,
+		js)
+
+	// Deleting the edge removes the reverse edge too.
+	delEdgeToUID(t, ps, "follows", 23, 1)
+	js = processToJSON(t, query)
+	require.JSONEq(t,
+		`{"me":[{"follows":[{"name":"Andrea"}],"name":"Michonne","~follows":[{"name":"Glenn Rhee"}]}]}`,
+		js)
+}
+
+func TestReverseEdgesNotInSchema(t *testing.T) {
+	query := `
+		{
+			me(_uid_:0x01) {
+				~gender
+			}
+		}
+	`
+	res, err := gql.Parse(query)
+	require.NoError(t, err)
+	_, err = ToSubGraph(context.Background(), res.Query[0])
+	require.Error(t, err)
+}
+
+func TestFilterInequality(t *testing.T) {
+	dir1, dir2, _ := populateGraph(t)
+	defer os.RemoveAll(dir1)
+	defer os.RemoveAll(dir2)
+	tests := []struct {
+		filter string
+		out    string
+	}{
+		{`ge("dob", "1909-05-05")`, `[{"name":"Rick Grimes"},{"name":"Glenn Rhee"}]`},
+		{`gt("dob", "1909-05-05")`, `[{"name":"Rick Grimes"}]`},
+		{`le("dob", "1909-01-10")`, `[{"name":"Daryl Dixon"},{"name":"Andrea"}]`},
+		{`lt("dob", "1909-01-10")`, `[{"name":"Andrea"}]`},
+		{`between("dob", "1909-01-01", "1909-12-31")`, `[{"name":"Glenn Rhee"},{"name":"Daryl Dixon"}]`},
+	}
+	for _, tt := range tests {
+		query := `
+			{
+				me(_uid_:0x01) {
+					friend @filter(` + tt.filter + `) {
+						name
+					}
+				}
+			}
+		`
+		js := processToJSON(t, query)
+		require.JSONEq(t, `{"me":[{"friend":`+tt.out+`}]}`, js, tt.filter)
+	}
+}
+
+func TestFilterInequalityNegative(t *testing.T) {
+	dir1, dir2, _ := populateGraph(t)
+	defer os.RemoveAll(dir1)
+	defer os.RemoveAll(dir2)
+	tests := []struct {
+		filter string
+		out    string
+	}{
+		{`ge("credit", "-5")`, `[{"name":"Glenn Rhee"},{"name":"Daryl Dixon"},{"name":"Andrea"}]`},
+		{`le("credit", "-3")`, `[{"name":"Rick Grimes"},{"name":"Daryl Dixon"}]`},
+		{`lt("credit", "-3")`, `[{"name":"Rick Grimes"}]`},
+		{`between("credit", "-10", "10")`, `[{"name":"Glenn Rhee"},{"name":"Daryl Dixon"}]`},
+		{`ge("score", "-0.3")`, `[{"name":"Glenn Rhee"},{"name":"Daryl Dixon"},{"name":"Andrea"}]`},
+		{`le("score", "-0.25")`, `[{"name":"Rick Grimes"},{"name":"Daryl Dixon"}]`},
+		{`between("score", "-1", "1")`, `[{"name":"Glenn Rhee"},{"name":"Daryl Dixon"}]`},
+	}
+	for _, tt := range tests {
+		query := `
+			{
+				me(_uid_:0x01) {
+					friend @filter(` + tt.filter + `) {
+						name
+					}
+				}
+			}
+		`
+		js := processToJSON(t, query)
+		require.JSONEq(t, `{"me":[{"friend":`+tt.out+`}]}`, js, tt.filter)
+	}
+}
+
+func TestToJSONOrderNegative(t *testing.T) {
+	dir1, dir2, _ := populateGraph(t)
+	defer os.RemoveAll(dir1)
+	defer os.RemoveAll(dir2)
+	tests := []struct {
+		order string
+		out   string
+	}{
+		{`orderasc: credit`, `[{"name":"Rick Grimes"},{"name":"Daryl Dixon"},{"name":"Glenn Rhee"},{"name":"Andrea"}]`},
+		{`orderdesc: credit`, `[{"name":"Andrea"},{"name":"Glenn Rhee"},{"name":"Daryl Dixon"},{"name":"Rick Grimes"}]`},
+		{`orderasc: score`, `[{"name":"Rick Grimes"},{"name":"Daryl Dixon"},{"name":"Glenn Rhee"},{"name":"Andrea"}]`},
+		{`orderdesc: score`, `[{"name":"Andrea"},{"name":"Glenn Rhee"},{"name":"Daryl Dixon"},{"name":"Rick Grimes"}]`},
+	}
+	for _, tt := range tests {
+		query := `
+			{
+				me(_uid_:0x01) {
+					friend(` + tt.order + `) {
+						name
+					}
+				}
+			}
+		`
+		js := processToJSON(t, query)
+		require.JSONEq(t, `{"me":[{"friend":`+tt.out+`}]}`, js, tt.order)
+	}
+}
+
+func TestGeneratorInequality(t *testing.T) {
+	dir1, dir2, _ := populateGraph(t)
+	defer os.RemoveAll(dir1)
+	defer os.RemoveAll(dir2)
+	query := `
+		{
+			me(ge("dob", "1909-05-05")) {
+				name
+			}
+		}
+	`
+	js := processToJSON(t, query)
+	require.JSONEq(t, `{"me":[{"name":"Rick Grimes"},{"name":"Glenn Rhee"}]}`, js)
+}
+
+func TestInequalityNotIndexed(t *testing.T) {
+	dir1, dir2, _ := populateGraph(t)
+	defer os.RemoveAll(dir1)
+	defer os.RemoveAll(dir2)
+	query := `
+		{
+			me(_uid_:0x01) {
+				friend @filter(ge("survival_rate", "2")) {
+					name
+				}
+			}
+		}
+	`
+	res, err := gql.Parse(query)
+	require.NoError(t, err)
+	_, err = ProcessQuery(context.Background(), res)
+	require.Error(t, err)
+}
+
+func TestFilterStringMatch(t *testing.T) {
+	dir1, dir2, _ := populateGraph(t)
+	defer os.RemoveAll(dir1)
+	defer os.RemoveAll(dir2)
+	tests := []struct {
+		filter string
+		out    string
+	}{
+		{`eq("name", "Glenn Rhee")`, `[{"name":"Glenn Rhee"}]`},
+		{`eq("name", "Glenn")`, ``},
+		{`prefix("name", "Da")`, `[{"name":"Daryl Dixon"}]`},
+		{`prefix("name", "")`, `[{"name":"Rick Grimes"},{"name":"Glenn Rhee"},{"name":"Daryl Dixon"},{"name":"Andrea"}]`},
+		{`regexp("name", /^(Rick|Andrea)/)`, `[{"name":"Rick Grimes"},{"name":"Andrea"}]`},
+		{`regexp("name", /i.*n$/)`, `[{"name":"Daryl Dixon"}]`},
+		{`regexp("name", /^glenn/i)`, `[{"name":"Glenn Rhee"}]`},
+		{`regexp("address", /mark street/)`, `[{"name":"Rick Grimes"}]`},
+	}
+	for _, tt := range tests {
+		query := `
+			{
+				me(_uid_:0x01) {
+					friend @filter(` + tt.filter + `) {
+						name
+					}
+				}
+			}
+		`
+		js := processToJSON(t, query)
+		if len(tt.out) == 0 {
+			require.JSONEq(t, `{"me":[{}]}`, js, tt.filter)
+			continue
+		}
+		require.JSONEq(t, `{"me":[{"friend":`+tt.out+`}]}`, js, tt.filter)
+	}
+}
+
+func TestGeneratorStringMatch(t *testing.T) {
+	dir1, dir2, _ := populateGraph(t)
+	defer os.RemoveAll(dir1)
+	defer os.RemoveAll(dir2)
+	query := `
+		{
+			me(prefix("name", "Mi")) {
+				name
+				gender
+			}
+		}
+	`
+	js := processToJSON(t, query)
+	require.JSONEq(t, `{"me":[{"gender":"female","name":"Michonne"}]}`, js)
+}
+
+func TestGeneratorRegexp(t *testing.T) {
+	dir1, dir2, _ := populateGraph(t)
+	defer os.RemoveAll(dir1)
+	defer os.RemoveAll(dir2)
+	query := `
+		{
+			me(regexp("name", /^Gl.*e$/)) {
+				name
+			}
+		}
+	`
+	js := processToJSON(t, query)
+	require.JSONEq(t, `{"me":[{"name":"Glenn Rhee"}]}`, js)
+
+	// Without a literal prefix, every value would have to be checked.
+	query = `
+		{
+			me(regexp("name", /Rhee/)) {
+				name
+			}
+		}
+	`
+	res, err := gql.Parse(query)
+	require.NoError(t, err)
+	_, err = ProcessQuery(context.Background(), res)
+	require.Error(t, err)
+}
+
+func TestFullTextSearch(t *testing.T) {
+	dir1, dir2, _ := populateGraph(t)
+	defer os.RemoveAll(dir1)
+	defer os.RemoveAll(dir2)
+	query := `
+		{
+			me(anyoftext("description", "Walker with crossbows")) {
+				name
+				_score_
+			}
+		}
+	`
+	js := processToJSON(t, query)
+	var res struct {
+		Me []struct {
+			Name  string  `json:"name"`
+			Score float64 `json:"_score_"`
+		} `json:"me"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(js), &res))
+	require.Len(t, res.Me, 3)
+	// Results are ranked by relevance.
+	var names []string
+	for i, r := range res.Me {
+		names = append(names, r.Name)
+		require.True(t, r.Score > 0)
+		if i > 0 {
+			require.True(t, r.Score < res.Me[i-1].Score)
+		}
+	}
+	require.Equal(t, []string{"Daryl Dixon", "Andrea", "Michonne"}, names)
+}
+
+func TestFullTextSearchAll(t *testing.T) {
+	dir1, dir2, _ := populateGraph(t)
+	defer os.RemoveAll(dir1)
+	defer os.RemoveAll(dir2)
+	query := `
+		{
+			me(alloftext("description", "the walkers and crossbow")) {
+				name
+			}
+		}
+	`
+	js := processToJSON(t, query)
+	require.JSONEq(t, `{"me":[{"name":"Daryl Dixon"}]}`, js)
+}
+
+func TestFullTextSearchFilter(t *testing.T) {
+	dir1, dir2, _ := populateGraph(t)
+	defer os.RemoveAll(dir1)
+	defer os.RemoveAll(dir2)
+	query := `
+		{
+			me(_uid_:0x01) {
+				friend @filter(anyoftext("description", "walker")) {
+					name
+				}
+			}
+		}
+	`
+	js := processToJSON(t, query)
+	require.JSONEq(t,
+		`{"me":[{"friend":[{"name":"Daryl Dixon"},{"name":"Andrea"}]}]}`, js)
+}
+
+func TestFullTextNotIndexed(t *testing.T) {
+	dir1, dir2, _ := populateGraph(t)
+	defer os.RemoveAll(dir1)
+	defer os.RemoveAll(dir2)
+	query := `
+		{
+			me(anyoftext("name", "Rick")) {
+				name
+			}
+		}
+	`
+	res, err := gql.Parse(query)
+	require.NoError(t, err)
+	_, err = ProcessQuery(context.Background(), res)
+	require.Error(t, err)
+}
+
+func TestFullTextStats(t *testing.T) {
+	dir1, dir2, ps := populateGraph(t)
+	defer os.RemoveAll(dir1)
+	defer os.RemoveAll(dir2)
+
+	descs := map[uint64]string{
+		1:  "Michonne carries a katana and walks with two chained walkers",
+		23: "Rick was a sheriff's deputy who leads the group of survivors",
+		24: "Glenn was a pizza delivery boy",
+		25: "Daryl hunts walkers with a crossbow",
+		31: "Andrea is a civil rights lawyer who learns to shoot walkers and walkers",
+	}
+	var total uint64
+	for _, desc := range descs {
+		s := types.String(desc)
+		terms, err := types.FullTextTerms("", &s)
+		require.NoError(t, err)
+		total += uint64(len(terms))
+	}
+	docs, terms := posting.FullTextStats("description")
+	require.EqualValues(t, len(descs), docs)
+	require.Equal(t, total, terms)
+
+	// Replacing a value updates the counters.
+	addEdgeToValue(t, ps, "description", 24, "Glenn")
+	time.Sleep(50 * time.Millisecond)
+	s := types.String(descs[24])
+	old, err := types.FullTextTerms("", &s)
+	require.NoError(t, err)
+	docs, terms = posting.FullTextStats("description")
+	require.EqualValues(t, len(descs), docs)
+	require.Equal(t, total-uint64(len(old))+1, terms)
+}
+
+func TestFullTextScoreNotAllowed(t *testing.T) {
+	query := `
+		{
+			me(_uid_:0x01) {
+				name
+				_score_
+			}
+		}
+	`
+	res, err := gql.Parse(query)
+	require.NoError(t, err)
+	_, err = ToSubGraph(context.Background(), res.Query[0])
+	require.Error(t, err)
+}
+
+func TestMain(m *testing.M) {
+	x.Init()
+	os.Exit(m.Run())
+}
+
+func TestFacets(t *testing.T) {
+	dir1, dir2, _ := populateGraph(t)
+	defer os.RemoveAll(dir1)
+	defer os.RemoveAll(dir2)
+	query := `
+		{
+			me(_uid_:0x01) {
+				name @facets
+				friend @facets {
+					name
+				}
+			}
+		}
+	`
+	js := processToJSON(t, query)
+	require.JSONEq(t,
+		`{"me":[{"@facets":{"name":{"origin":"french"}},"friend":[
+			{"@facets":{"_":{"close":true,"since":2006}},"name":"Rick Grimes"},
+			{"@facets":{"_":{"close":false,"since":2004}},"name":"Glenn Rhee"},
+			{"@facets":{"_":{"close":true,"since":2010}},"name":"Daryl Dixon"},
+			{"name":"Andrea"},
+			{"@facets":{"_":{"since":2001}}}],"name":"Michonne"}]}`,
+		js)
+}
+
+func TestFacetsKeys(t *testing.T) {
+	dir1, dir2, _ := populateGraph(t)
+	defer os.RemoveAll(dir1)
+	defer os.RemoveAll(dir2)
+	query := `
+		{
+			me(_uid_:0x01) {
+				friend @facets(close) {
+					name
+				}
+			}
+		}
+	`
+	js := processToJSON(t, query)
+	require.JSONEq(t,
+		`{"me":[{"friend":[
+			{"@facets":{"_":{"close":true}},"name":"Rick Grimes"},
+			{"@facets":{"_":{"close":false}},"name":"Glenn Rhee"},
+			{"@facets":{"_":{"close":true}},"name":"Daryl Dixon"},
+			{"name":"Andrea"}]}]}`,
+		js)
+}
+
+func TestFacetsFilter(t *testing.T) {
+	dir1, dir2, _ := populateGraph(t)
+	defer os.RemoveAll(dir1)
+	defer os.RemoveAll(dir2)
+	query := `
+		{
+			me(_uid_:0x01) {
+				friend @facets(eq("close", "true") && ge("since", "2005")) {
+					name
+				}
+			}
+		}
+	`
+	js := processToJSON(t, query)
+	require.JSONEq(t,
+		`{"me":[{"friend":[{"name":"Rick Grimes"},{"name":"Daryl Dixon"}]}]}`,
+		js)
+
+	query = `
+		{
+			me(_uid_:0x01) {
+				friend @facets(not eq("close", "true")) {
+					name
+				}
+			}
+		}
+	`
+	js = processToJSON(t, query)
+	require.JSONEq(t,
+		`{"me":[{"friend":[{"name":"Glenn Rhee"},{"name":"Andrea"}]}]}`,
+		js)
+}
+
+func TestFacetsFilterValue(t *testing.T) {
+	dir1, dir2, _ := populateGraph(t)
+	defer os.RemoveAll(dir1)
+	defer os.RemoveAll(dir2)
+	query := `
+		{
+			me(_uid_:0x01) {
+				name @facets(eq("origin", "english"))
+				gender
+			}
+		}
+	`
+	js := processToJSON(t, query)
+	require.JSONEq(t, `{"me":[{"gender":"female"}]}`, js)
+}
+
+func TestFacetsFilterCount(t *testing.T) {
+	dir1, dir2, _ := populateGraph(t)
+	defer os.RemoveAll(dir1)
+	defer os.RemoveAll(dir2)
+	query := `
+		{
+			me(_uid_:0x01) {
+				friend @facets(lt("since", "2005")) {
+					_count_
+				}
+			}
+		}
+	`
+	js := processToJSON(t, query)
+	require.JSONEq(t, `{"me":[{"friend":[{"_count_":2}]}]}`, js)
+}
+
+func TestFacetsOrder(t *testing.T) {
+	dir1, dir2, _ := populateGraph(t)
+	defer os.RemoveAll(dir1)
+	defer os.RemoveAll(dir2)
+	query := `
+		{
+			me(_uid_:0x01) {
+				friend(first: 3) @facets(orderdesc: since) {
+					name
+				}
+			}
+		}
+	`
+	js := processToJSON(t, query)
+	require.JSONEq(t,
+		`{"me":[{"friend":[
+			{"@facets":{"_":{"close":true,"since":2010}},"name":"Daryl Dixon"},
+			{"@facets":{"_":{"close":true,"since":2006}},"name":"Rick Grimes"},
+			{"@facets":{"_":{"close":false,"since":2004}},"name":"Glenn Rhee"}]}]}`,
+		js)
+}
+
+func TestFacetsOrderAndSortError(t *testing.T) {
+	dir1, dir2, _ := populateGraph(t)
+	defer os.RemoveAll(dir1)
+	defer os.RemoveAll(dir2)
+	query := `
+		{
+			me(_uid_:0x01) {
+				friend(order: name) @facets(orderasc: since) {
+					name
+				}
+			}
+		}
+	`
+	res, err := gql.Parse(query)
+	require.NoError(t, err)
+	_, err = ProcessQuery(context.Background(), res)
+	require.Error(t, err)
+}
+
+func TestCascade(t *testing.T) {
+	dir1, dir2, _ := populateGraph(t)
+	defer os.RemoveAll(dir1)
+	defer os.RemoveAll(dir2)
+	query := `
+		{
+			me(_uid_:0x01) @cascade {
+				name
+				friend {
+					name
+					address
+				}
+			}
+		}
+	`
+	js := processToJSON(t, query)
+	require.JSONEq(t,
+		`{"me":[{"friend":[{"address":"21, mark street, Mars","name":"Rick Grimes"}],"name":"Michonne"}]}`,
+		js)
+}
+
+func TestCascadeChild(t *testing.T) {
+	dir1, dir2, _ := populateGraph(t)
+	defer os.RemoveAll(dir1)
+	defer os.RemoveAll(dir2)
+	query := `
+		{
+			me(_uid_:0x01) {
+				name
+				friend @cascade {
+					name
+					dob
+				}
+			}
+		}
+	`
+	js := processToJSON(t, query)
+	require.JSONEq(t,
+		`{"me":[{"friend":[{"dob":"1910-01-02","name":"Rick Grimes"},
+			{"dob":"1909-05-05","name":"Glenn Rhee"},
+			{"dob":"1909-01-10","name":"Daryl Dixon"},
+			{"dob":"1901-01-15","name":"Andrea"}],"name":"Michonne"}]}`,
+		js)
+}
+
+func TestCascadePropagate(t *testing.T) {
+	dir1, dir2, _ := populateGraph(t)
+	defer os.RemoveAll(dir1)
+	defer os.RemoveAll(dir2)
+	// None of the friends have friends of their own, so the friends are all
+	// removed, and then so is the root node.
+	query := `
+		{
+			me(_uid_:0x01) @cascade {
+				name
+				friend {
+					name
+					friend {
+						name
+					}
+				}
+			}
+		}
+	`
+	js := processToJSON(t, query)
+	require.JSONEq(t, `{}`, js)
+}
+
+func TestNormalize(t *testing.T) {
+	dir1, dir2, _ := populateGraph(t)
+	defer os.RemoveAll(dir1)
+	defer os.RemoveAll(dir2)
+	query := `
+		{
+			me(_uid_:0x01) @normalize {
+				mn: name
+				gender
+				friend {
+					n: name
+					d: dob
+				}
+			}
+		}
+	`
+	js := processToJSON(t, query)
+	require.JSONEq(t,
+		`{"me":[{"d":"1910-01-02","mn":"Michonne","n":"Rick Grimes"},
+			{"d":"1909-05-05","mn":"Michonne","n":"Glenn Rhee"},
+			{"d":"1909-01-10","mn":"Michonne","n":"Daryl Dixon"},
+			{"d":"1901-01-15","mn":"Michonne","n":"Andrea"}]}`,
+		js)
+}
+
+func TestToProtoNormalize(t *testing.T) {
+	dir, dir2, _ := populateGraph(t)
+	defer os.RemoveAll(dir)
+	defer os.RemoveAll(dir2)
+	query := `
+		{
+			me(_uid_:0x01) @normalize {
+				mn: name
+				friend @filter(anyof("name", "Andrea Rick")) {
+					n: name
+				}
+			}
+		}
+	`
+
+	res, err := gql.Parse(query)
+	require.NoError(t, err)
+	gq := res.Query[0]
+
+	ctx := context.Background()
+	sg, err := ToSubGraph(ctx, gq)
+	require.NoError(t, err)
+
+	ch := make(chan error)
+	go ProcessGraph(ctx, sg, nil, ch)
+	err = <-ch
+	require.NoError(t, err)
+
+	var l Latency
+	pb, err := sg.ToProtocolBuffer(&l)
+	require.NoError(t, err)
+
+	require.Len(t, pb.Children, 2)
+	for i, name := range []string{"Rick Grimes", "Andrea"} {
+		row := pb.Children[i]
+		require.EqualValues(t, "me", row.Attribute)
+		require.Empty(t, row.Children)
+		require.Len(t, row.Properties, 2)
+		require.EqualValues(t, "Michonne", getProperty(row.Properties, "mn").GetStrVal())
+		require.EqualValues(t, name, getProperty(row.Properties, "n").GetStrVal())
+	}
+}
+
+func TestGroupBy(t *testing.T) {
+	dir, dir2, ps := populateGraph(t)
+	defer os.RemoveAll(dir)
+	defer os.RemoveAll(dir2)
+	defer ps.Close()
+
+	for uid, gender := range map[uint64]string{23: "male", 24: "male", 25: "male",
+		31: "female"} {
+		addEdgeToValue(t, ps, "gender", uid, gender)
+	}
+	for uid, rate := range map[uint64]float64{23: 1.5, 24: 2.0, 31: 4.0} {
+		data, err := types.Float(rate).MarshalBinary()
+		require.NoError(t, err)
+		addEdgeToTypedValue(t, ps, "survival_rate", uid, types.FloatID, data)
+	}
+
+	query := `
+		{
+			me(_uid_:0x01) {
+				name
+				friend @groupby(gender) {
+					avg(survival_rate)
+					oldest: min(dob)
+				}
+			}
+		}
+	`
+	js := processToJSON(t, query)
+	require.JSONEq(t,
+		`{"me":[{"friend":[
+			{"avg(survival_rate)":4,"count":1,"gender":"female","oldest":"1901-01-15"},
+			{"avg(survival_rate)":1.75,"count":3,"gender":"male","oldest":"1909-01-10"}],
+			"name":"Michonne"}]}`,
+		js)
+}
+
+func TestGroupByUID(t *testing.T) {
+	dir, dir2, ps := populateGraph(t)
+	defer os.RemoveAll(dir)
+	defer os.RemoveAll(dir2)
+	defer ps.Close()
+
+	addEdgeToUID(t, ps, "school", 23, 5001)
+	addEdgeToUID(t, ps, "school", 24, 5000)
+	addEdgeToUID(t, ps, "school", 25, 5001)
+	addEdgeToUID(t, ps, "school", 31, 5001)
+
+	query := `
+		{
+			me(_uid_:0x01) {
+				friend @groupby(school)
+			}
+		}
+	`
+	js := processToJSON(t, query)
+	require.JSONEq(t,
+		`{"me":[{"friend":[{"_uid_":"0x1388","count":1},{"_uid_":"0x1389","count":3}]}]}`,
+		js)
+}
+
+func TestGroupByError(t *testing.T) {
+	dir, dir2, ps := populateGraph(t)
+	defer os.RemoveAll(dir)
+	defer os.RemoveAll(dir2)
+	defer ps.Close()
+
+	query := `
+		{
+			me(_uid_:0x01) {
+				friend @groupby(gender) {
+					name
+				}
+			}
+		}
+	`
+	res, err := gql.Parse(query)
+	require.NoError(t, err)
+	_, err = ProcessQuery(context.Background(), res)
+	require.Error(t, err)
+}
+
+func TestExpandAll(t *testing.T) {
+	dir, dir2, ps := populateGraph(t)
+	defer os.RemoveAll(dir)
+	defer os.RemoveAll(dir2)
+	defer ps.Close()
+
+	query := `
+		{
+			me(_uid_:0x19) {
+				name
+				expand(_all_)
+			}
+		}
+	`
+	js := processToJSON(t, query)
+	require.JSONEq(t,
+		`{"me":[{"credit":-3,"description":"Daryl hunts walkers with a crossbow",
+			"dob":"1909-01-10","name":"Daryl Dixon","score":-0.25}]}`,
+		js)
+
+	// Deleting all the values of a predicate removes its record.
+	edge := &task.DirectedEdge{
+		Value:  []byte(x.Star),
+		Label:  "testing",
+		Attr:   "name",
+		Entity: 0x19,
+	}
+	l, _ := posting.GetOrCreate(posting.Key(0x19, "name"))
+	require.NoError(t,
+		l.AddMutationWithIndex(context.Background(), edge, posting.Del))
+	js = processToJSON(t, query)
+	require.JSONEq(t,
+		`{"me":[{"credit":-3,"description":"Daryl hunts walkers with a crossbow",
+			"dob":"1909-01-10","score":-0.25}]}`,
+		js)
+}
+
+func TestExpandAllReverse(t *testing.T) {
+	dir, dir2, ps := populateGraph(t)
+	defer os.RemoveAll(dir)
+	defer os.RemoveAll(dir2)
+	defer ps.Close()
+
+	require.NoError(t, schema.ParseBytes([]byte(`
+type Follower {
+  follows: Follower @reverse
+}
+`)))
+	addEdgeToUID(t, ps, "follows", 23, 1)
+	addEdgeToUID(t, ps, "follows", 24, 1)
+
+	query := `
+		{
+			me(_uid_:0x01) {
+				expand(_all_)
+			}
+		}
+	`
+	js := processToJSON(t, query)
+	require.Contains(t, js, `"~follows":[{"_uid_":"0x17"},{"_uid_":"0x18"}]`)
+
+	// Deleting all the reverse edges deletes the edges they stand for.
+	edge := &task.DirectedEdge{
+		Value:  []byte(x.Star),
+		Label:  "testing",
+		Attr:   "~follows",
+		Entity: 0x01,
+	}
+	l, _ := posting.GetOrCreate(posting.Key(0x01, "~follows"))
+	require.NoError(t,
+		l.AddMutationWithIndex(context.Background(), edge, posting.Del))
+	js = processToJSON(t, query)
+	require.NotContains(t, js, "follows")
+	js = processToJSON(t, `
+		{
+			me(_uid_:0x17) {
+				expand(_all_)
+			}
+		}
+	`)
+	require.NotContains(t, js, "follows")
+}
+
+func TestLangs(t *testing.T) {
+	dir, dir2, ps := populateGraph(t)
+	defer os.RemoveAll(dir)
+	defer os.RemoveAll(dir2)
+	defer ps.Close()
+
+	addEdgeToLangValue(t, ps, "name", 1, "Michonne la Française", "fr")
+	addEdgeToLangValue(t, ps, "name", 24, "Glenn Rhée", "fr")
+	addEdgeToLangValue(t, ps, "name", 25, "Daryl Dixon", "en")
+
+	query := `
+		{
+			me(_uid_:0x01) {
+				name
+				name@fr
+				name@de
+				name@de:.
+				friend @filter(anyof("name", "Rhée")) {
+					name@fr:en
+				}
+			}
+		}
+	`
+	js := processToJSON(t, query)
+	require.JSONEq(t,
+		`{"me":[{"friend":[{"name@fr:en":"Glenn Rhée"}],"name":"Michonne","name@de:.":"Michonne","name@fr":"Michonne la Française"}]}`,
+		js)
+
+	query = `
+		{
+			me(_uid_:0x01) {
+				friend {
+					name@fr:en
+				}
+			}
+		}
+	`
+	js = processToJSON(t, query)
+	require.JSONEq(t,
+		`{"me":[{"friend":[{"name@fr:en":"Glenn Rhée"},{"name@fr:en":"Daryl Dixon"}]}]}`,
+		js)
+}
+
+func TestExplain(t *testing.T) {
+	dir, dir2, ps := populateGraph(t)
+	defer os.RemoveAll(dir)
+	defer os.RemoveAll(dir2)
+	defer ps.Close()
+
+	query := `
+		{
+			me(_uid_:0x01) {
+				name
+				friend(orderdesc: dob) @filter(anyof("name", "Andrea Rhee")) {
+					name
+				}
+			}
+		}
+	`
+	res, err := gql.Parse(query)
+	require.NoError(t, err)
+	sgl, err := ProcessQuery(context.Background(), res)
+	require.NoError(t, err)
+
+	l := Latency{Start: time.Now()}
+	js, err := Explain(&l, sgl)
+	require.NoError(t, err)
+
+	var out struct {
+		Explain []*planNode
+	}
+	require.NoError(t, json.Unmarshal(js, &out))
+	require.Len(t, out.Explain, 1)
+	me := out.Explain[0]
+	require.Nil(t, me.Task)
+	require.Equal(t, 1, me.UidsOut)
+	require.Len(t, me.Children, 2)
+
+	name := me.Children[0]
+	require.Equal(t, "name", name.Attr)
+	require.NotNil(t, name.Task)
+	require.Equal(t, group.BelongsTo("name"), name.Task.Group)
+	require.Equal(t, 1, name.Task.Uids)
+
+	friend := me.Children[1]
+	require.Equal(t, "friend", friend.Attr)
+	require.Equal(t, 1, friend.UidsIn)
+	require.Equal(t, 5, friend.UidsFetched)
+	require.Equal(t, 2, friend.UidsOut)
+	require.Equal(t, "index(dob desc)", friend.Sort)
+	require.NotEmpty(t, friend.SortTime)
+	require.NotEmpty(t, friend.FilterTime)
+	require.Len(t, friend.Filters, 1)
+	require.Equal(t, []string{"anyof", "Andrea Rhee"}, friend.Filters[0].Task.Func)
+	require.Equal(t, 5, friend.Filters[0].UidsIn)
+	require.Equal(t, 2, friend.Filters[0].UidsOut)
+	require.Len(t, friend.Children, 1)
+	require.Equal(t, 2, friend.Children[0].UidsIn)
+}
+
+func TestMath(t *testing.T) {
+	dir, dir2, ps := populateGraph(t)
+	defer os.RemoveAll(dir)
+	defer os.RemoveAll(dir2)
+	addEdgeToValue(t, ps, "age", 24, "20")
+	addEdgeToValue(t, ps, "age", 25, "40")
+
+	query := `
+		{
+			me(_uid_:0x01) {
+				friend {
+					name
+					months: math(age * 12)
+					adult: math(age >= 18)
+				}
+			}
+		}
+	`
+	js := processToJSON(t, query)
+	require.JSONEq(t,
+		`{"me":[{"friend":[{"name":"Rick Grimes","months":180,"adult":false},
+		{"name":"Glenn Rhee","months":240,"adult":true},
+		{"name":"Daryl Dixon","months":480,"adult":true},{"name":"Andrea"}]}]}`,
+		js)
+
+	query = `
+		{
+			me(_uid_:0x01) {
+				friend @filter(math(age * 12 > 200) || anyof("name", "Andrea")) {
+					name
+				}
+			}
+		}
+	`
+	js = processToJSON(t, query)
+	require.JSONEq(t,
+		`{"me":[{"friend":[{"name":"Glenn Rhee"},{"name":"Daryl Dixon"},
+		{"name":"Andrea"}]}]}`,
+		js)
+
+	query = `
+		{
+			me(_uid_:0x01) {
+				friend(orderdesc: score, first: 2) {
+					name
+					score: math(max(age, 18) - 15)
+				}
+			}
+		}
+	`
+	js = processToJSON(t, query)
+	require.JSONEq(t,
+		`{"me":[{"friend":[{"name":"Daryl Dixon","score":25},
+		{"name":"Glenn Rhee","score":5}]}]}`,
+		js)
+}
+
+func TestMathError(t *testing.T) {
+	dir, dir2, _ := populateGraph(t)
+	defer os.RemoveAll(dir)
+	defer os.RemoveAll(dir2)
+
+	query := `
+		{
+			me(_uid_:0x01) {
+				friend {
+					months: math(age * 12) {
+						name
+					}
+				}
+			}
+		}
+	`
+	res, err := gql.Parse(query)
+	require.NoError(t, err)
+	_, err = ProcessQuery(context.Background(), res)
+	require.Error(t, err)
+}
+
+func TestUidIn(t *testing.T) {
+	dir, dir2, ps := populateGraph(t)
+	defer os.RemoveAll(dir)
+	defer os.RemoveAll(dir2)
+	addEdgeToUID(t, ps, "friend", 23, 24)
+	addEdgeToUID(t, ps, "friend", 23, 25)
+	addEdgeToUID(t, ps, "friend", 24, 25)
+	addEdgeToUID(t, ps, "friend", 31, 24)
+
+	query := `
+		{
+			me(_uid_:0x01) {
+				friend @filter(uid_in(friend, 0x18)) {
+					name
+				}
+			}
+		}
+	`
+	js := processToJSON(t, query)
+	require.JSONEq(t,
+		`{"me":[{"friend":[{"name":"Rick Grimes"},{"name":"Andrea"}]}]}`,
+		js)
+
+	query = `
+		{
+			rick(_uid_:0x17) {
+				f as friend
+			}
+			me(_uid_:0x01) {
+				friend @filter(uid_in(friend, var(f))) {
+					name
+				}
+			}
+		}
+	`
+	js = processToJSON(t, query)
+	require.JSONEq(t,
+		`{"rick":[{}],
+		"me":[{"friend":[{"name":"Rick Grimes"},{"name":"Glenn Rhee"},{"name":"Andrea"}]}]}`,
+		js)
+}
+
+func TestRootFuncFilterAndPagination(t *testing.T) {
+	dir, dir2, _ := populateGraph(t)
+	defer os.RemoveAll(dir)
+	defer os.RemoveAll(dir2)
+
+	query := `
+		{
+			me(anyof("name", "Rick Glenn Daryl Andrea"), first: 2) {
+				name
+			}
+		}
+	`
+	js := processToJSON(t, query)
+	require.JSONEq(t, `{"me":[{"name":"Rick Grimes"},{"name":"Glenn Rhee"}]}`, js)
+
+	query = `
+		{
+			me(anyof("name", "Rick Glenn Daryl Andrea"), orderdesc: dob, offset: 1, first: 2)
+				@filter(not anyof("name", "Daryl")) {
+				name
+			}
+		}
+	`
+	js = processToJSON(t, query)
+	require.JSONEq(t, `{"me":[{"name":"Glenn Rhee"},{"name":"Andrea"}]}`, js)
+
+	// The results of a full-text search are paginated by relevance.
+	query = `
+		{
+			me(anyoftext("description", "Walker with crossbows"), offset: 1) {
+				name
+			}
+		}
+	`
+	js = processToJSON(t, query)
+	require.JSONEq(t, `{"me":[{"name":"Andrea"},{"name":"Michonne"}]}`, js)
+}
+
+func TestLangsIndex(t *testing.T) {
+	dir, dir2, ps := populateGraph(t)
+	defer os.RemoveAll(dir)
+	defer os.RemoveAll(dir2)
+	defer ps.Close()
+
+	addEdgeToLangValue(t, ps, "name", 24, "Glenn Rhée", "fr")
+	addEdgeToLangValue(t, ps, "name", 25, "Daryl Dixon", "en")
+	addEdgeToLangValue(t, ps, "name", 31, "Andréa", "fr")
+	time.Sleep(50 * time.Millisecond)
+
+	// Each friend is sorted once, by the value without a language.
+	query := `
+		{
+			me(_uid_:0x01) {
+				friend(orderasc: name, first: 3) {
+					name
+				}
+			}
+		}
+	`
+	js := processToJSON(t, query)
+	require.JSONEq(t,
+		`{"me":[{"friend":[{"name":"Andrea"},{"name":"Daryl Dixon"},{"name":"Glenn Rhee"}]}]}`,
+		js)
+
+	// Whole values are only matched without a language.
+	query = `
+		{
+			me(_uid_:0x01) {
+				friend @filter(eq("name", "Glenn Rhée") || eq("name", "Andrea")) {
+					name
+				}
+			}
+		}
+	`
+	js = processToJSON(t, query)
+	require.JSONEq(t, `{"me":[{"friend":[{"name":"Andrea"}]}]}`, js)
+
+	// Deleting the value in one language keeps the terms of the others.
+	edge := &task.DirectedEdge{
+		Value:  []byte("Daryl Dixon"),
+		Lang:   "en",
+		Label:  "testing",
+		Attr:   "name",
+		Entity: 25,
+	}
+	l, _ := posting.GetOrCreate(posting.Key(25, "name"))
+	require.NoError(t,
+		l.AddMutationWithIndex(context.Background(), edge, posting.Del))
+	time.Sleep(50 * time.Millisecond)
+	query = `
+		{
+			me(_uid_:0x01) {
+				friend @filter(anyof("name", "Dixon")) {
+					name
+				}
+			}
+		}
+	`
+	js = processToJSON(t, query)
+	require.JSONEq(t, `{"me":[{"friend":[{"name":"Daryl Dixon"}]}]}`, js)
+}
+
+func TestLangsFacets(t *testing.T) {
+	dir, dir2, ps := populateGraph(t)
+	defer os.RemoveAll(dir)
+	defer os.RemoveAll(dir2)
+	defer ps.Close()
+
+	edge := &task.DirectedEdge{
+		Value:  []byte("Michonne la Française"),
+		Lang:   "fr",
+		Label:  "testing",
+		Attr:   "name",
+		Entity: 1,
+		Facets: []*task.Facet{makeFacet(t, "origin", types.StringID, "english")},
+	}
+	l, _ := posting.GetOrCreate(posting.Key(1, "name"))
+	require.NoError(t,
+		l.AddMutationWithIndex(context.Background(), edge, posting.Set))
+
+	query := `
+		{
+			me(_uid_:0x01) {
+				name@fr @facets
+			}
+		}
+	`
+	js := processToJSON(t, query)
+	require.JSONEq(t,
+		`{"me":[{"@facets":{"name@fr":{"origin":"english"}},"name@fr":"Michonne la Française"}]}`,
+		js)
 }
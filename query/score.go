@@ -0,0 +1,57 @@
+/*
+ * Copyright 2016 Dgraph Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * 		http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+import (
+	"sort"
+
+	"github.com/dgraph-io/dgraph/task"
+)
+
+// setScores records the relevance of the UIDs found by a full-text search.
+// The scores are kept by UID, as the lists get modified by filtering and
+// pagination.
+func (sg *SubGraph) setScores(ul *task.List, scores []float64) {
+	sg.scores = make(map[uint64]float64, len(scores))
+	for i, uid := range ul.Uids {
+		sg.scores[uid] = scores[i]
+	}
+}
+
+// byScore sorts UIDs by decreasing score, and then by UID.
+type byScore struct {
+	uids   []uint64
+	scores map[uint64]float64
+}
+
+func (b byScore) Len() int      { return len(b.uids) }
+func (b byScore) Swap(i, j int) { b.uids[i], b.uids[j] = b.uids[j], b.uids[i] }
+func (b byScore) Less(i, j int) bool {
+	si, sj := b.scores[b.uids[i]], b.scores[b.uids[j]]
+	if si != sj {
+		return si > sj
+	}
+	return b.uids[i] < b.uids[j]
+}
+
+// rankedUIDs returns the UIDs in sg.DestUIDs, most relevant first.
+func (sg *SubGraph) rankedUIDs() []uint64 {
+	uids := make([]uint64, len(sg.DestUIDs.Uids))
+	copy(uids, sg.DestUIDs.Uids)
+	sort.Sort(byScore{uids: uids, scores: sg.scores})
+	return uids
+}
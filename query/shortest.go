@@ -0,0 +1,340 @@
+/*
+ * Copyright 2016 Dgraph Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * 		http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+import (
+	"context"
+
+	"github.com/dgraph-io/dgraph/algo"
+	"github.com/dgraph-io/dgraph/gql"
+	"github.com/dgraph-io/dgraph/posting"
+	"github.com/dgraph-io/dgraph/schema"
+	"github.com/dgraph-io/dgraph/task"
+	"github.com/dgraph-io/dgraph/types"
+	"github.com/dgraph-io/dgraph/worker"
+	"github.com/dgraph-io/dgraph/x"
+)
+
+// defaultMaxVisited is the maximum number of nodes reached while searching for
+// a shortest path, unless the query gives another one with maxvisited.
+const defaultMaxVisited = 100000
+
+// pathEdge records the edge through which a node was reached, and the number
+// of edges between the node and the end the search started from.
+type pathEdge struct {
+	from uint64
+	to   uint64
+	attr string
+	hops int
+}
+
+// checkShortestPath checks that the children of a shortest path query are
+// only the predicates to follow.
+func checkShortestPath(gq *gql.GraphQuery) error {
+	for _, gchild := range gq.Children {
+		if len(gchild.Children) > 0 {
+			return x.Errorf("Nested blocks are not allowed in shortest path: %v",
+				gchild.Attr)
+		}
+	}
+	return nil
+}
+
+// expandFrontier fetches the neighbours of the UIDs in frontier over each of
+// the given predicates, along with the facet named weight if it's set. The
+// results are in the same order as attrs.
+func expandFrontier(ctx context.Context, frontier []uint64, attrs []string,
+	weight string) ([]*task.Result, error) {
+	results := make([]*task.Result, len(attrs))
+	errChan := make(chan error, len(attrs))
+	for i, attr := range attrs {
+		go func(i int, attr string) {
+			taskQuery := &task.Query{Attr: attr, Uids: frontier}
+			if len(weight) > 0 {
+				taskQuery.FacetParam = &task.FacetParams{Keys: []string{weight}}
+			}
+			var err error
+			results[i], err = worker.ProcessTaskOverNetwork(ctx, taskQuery)
+			errChan <- err
+		}(i, attr)
+	}
+
+	for _ = range attrs {
+		select {
+		case err := <-errChan:
+			if err != nil {
+				x.TraceError(ctx, x.Wrapf(err, "Error while processing task"))
+				return nil, err
+			}
+		case <-ctx.Done():
+			x.TraceError(ctx, x.Wrapf(ctx.Err(), "Context done before full execution"))
+			return nil, ctx.Err()
+		}
+	}
+	return results, nil
+}
+
+// pathSearch holds the arguments of a shortest path search.
+type pathSearch struct {
+	*gql.ShortestPath
+	attrs []string
+}
+
+func (ps *pathSearch) checkVisited(n int) error {
+	if n > ps.MaxVisited {
+		return x.Errorf("Shortest path search reached more than %d nodes",
+			ps.MaxVisited)
+	}
+	return nil
+}
+
+// bfs returns the edges along a shortest path, found with a breadth first
+// search. If all the predicates have reverse edges, the search goes from both
+// ends, expanding the smaller frontier each time. It returns false if there
+// is no path of at most ps.Depth edges.
+func (ps *pathSearch) bfs(ctx context.Context) ([]pathEdge, bool, error) {
+	bidi := true
+	rattrs := make([]string, len(ps.attrs))
+	for i, attr := range ps.attrs {
+		bidi = bidi && schema.IsReversed(attr)
+		rattrs[i] = posting.ReverseAttr(attr)
+	}
+
+	// fwd holds the edge into each node reached from ps.From, and bwd the edge
+	// out of each node reached from ps.To, backwards.
+	fwd := map[uint64]pathEdge{ps.From: {}}
+	bwd := map[uint64]pathEdge{ps.To: {}}
+	fwdFrontier, bwdFrontier := []uint64{ps.From}, []uint64{ps.To}
+	var fwdHops, bwdHops int
+	meet, meetHops := ps.From, 0
+	if ps.From != ps.To {
+		meet = 0
+	}
+	for meet == 0 {
+		if ps.Depth > 0 && fwdHops+bwdHops >= ps.Depth {
+			return nil, false, nil
+		}
+		forward := !bidi || len(fwdFrontier) <= len(bwdFrontier)
+		frontier, attrs, seen, other := fwdFrontier, ps.attrs, fwd, bwd
+		hops := fwdHops + 1
+		if !forward {
+			frontier, attrs, seen, other = bwdFrontier, rattrs, bwd, fwd
+			hops = bwdHops + 1
+		}
+		if len(frontier) == 0 {
+			return nil, false, nil
+		}
+		results, err := expandFrontier(ctx, frontier, attrs, "")
+		if err != nil {
+			return nil, false, err
+		}
+
+		// Go over the results in order, so that the path found doesn't depend
+		// on which predicate got processed first.
+		var next []uint64
+		for i, result := range results {
+			for j, ul := range result.UidMatrix {
+				src := frontier[j]
+				for _, uid := range ul.Uids {
+					if _, ok := seen[uid]; ok {
+						continue
+					}
+					e := pathEdge{from: src, to: uid, attr: ps.attrs[i], hops: hops}
+					if !forward {
+						e.from, e.to = uid, src
+					}
+					seen[uid] = e
+					next = append(next, uid)
+					// The shortest path goes through the node reached from the
+					// other end with the fewest edges.
+					if o, ok := other[uid]; ok && (meet == 0 || o.hops < meetHops) {
+						meet, meetHops = uid, o.hops
+					}
+				}
+			}
+		}
+		if err := ps.checkVisited(len(fwd) + len(bwd)); err != nil {
+			return nil, false, err
+		}
+		if forward {
+			fwdFrontier, fwdHops = next, hops
+		} else {
+			bwdFrontier, bwdHops = next, hops
+		}
+	}
+
+	var path []pathEdge
+	for uid := meet; uid != ps.From; uid = fwd[uid].from {
+		path = append([]pathEdge{fwd[uid]}, path...)
+	}
+	for uid := meet; uid != ps.To; uid = bwd[uid].to {
+		path = append(path, bwd[uid])
+	}
+	return path, true, nil
+}
+
+// edgeWeight returns the value of the facet named key, which edges without it
+// have as one.
+func edgeWeight(facets *task.Facets, key string) (float64, error) {
+	f := findFacet(facets, key)
+	if f == nil {
+		return 1, nil
+	}
+	v, err := facetValue(f)
+	if err != nil {
+		return 0, err
+	}
+	ftype, _ := types.TypeForName("float")
+	fv, err := ftype.(types.Scalar).Convert(v)
+	if err != nil {
+		return 0, x.Wrapf(err, "Weight facet %s should be a number", key)
+	}
+	w := float64(*fv.(*types.Float))
+	if w < 0 {
+		return 0, x.Errorf("Weight facet %s should not be negative. Got: %v", key, w)
+	}
+	return w, nil
+}
+
+// dijkstra returns the edges along the path with the least total weight, as
+// given by the facet ps.Weight on the edges. Like bfs, it expands a whole
+// frontier with one task query per hop: the frontier is made of the nodes
+// whose distance went down in the last hop, and the search ends once it's
+// empty. The nodes more than ps.Depth edges away along the lightest path to
+// them aren't expanded, nor are the ones farther than ps.To already. It
+// returns false if there is no path.
+func (ps *pathSearch) dijkstra(ctx context.Context) ([]pathEdge, bool, error) {
+	prev := map[uint64]pathEdge{ps.From: {}}
+	dist := map[uint64]float64{ps.From: 0}
+	frontier := []uint64{ps.From}
+	for len(frontier) > 0 {
+		results, err := expandFrontier(ctx, frontier, ps.attrs, ps.Weight)
+		if err != nil {
+			return nil, false, err
+		}
+
+		// Go over the results in order, so that the path found doesn't depend
+		// on which predicate got processed first.
+		next := new(task.List)
+		improved := make(map[uint64]bool)
+		for i, result := range results {
+			for j, ul := range result.UidMatrix {
+				src := frontier[j]
+				for k, uid := range ul.Uids {
+					var facets *task.Facets
+					if len(result.FacetMatrix) > 0 {
+						facets = result.FacetMatrix[j].FacetsList[k]
+					}
+					w, err := edgeWeight(facets, ps.Weight)
+					if err != nil {
+						return nil, false, err
+					}
+					d := dist[src] + w
+					if old, ok := dist[uid]; ok && old <= d {
+						continue
+					}
+					dist[uid] = d
+					prev[uid] = pathEdge{from: src, to: uid, attr: ps.attrs[i],
+						hops: prev[src].hops + 1}
+					if !improved[uid] {
+						improved[uid] = true
+						next.Uids = append(next.Uids, uid)
+					}
+				}
+			}
+		}
+		if err := ps.checkVisited(len(dist)); err != nil {
+			return nil, false, err
+		}
+
+		// The weights aren't negative, so the nodes as far as ps.To or farther
+		// can't lead to a lighter path to it.
+		algo.Sort(next)
+		toDist, reached := dist[ps.To]
+		frontier = frontier[:0]
+		for _, uid := range next.Uids {
+			if reached && dist[uid] >= toDist {
+				continue
+			}
+			if ps.Depth > 0 && prev[uid].hops >= ps.Depth {
+				continue
+			}
+			frontier = append(frontier, uid)
+		}
+	}
+
+	if _, ok := dist[ps.To]; !ok {
+		return nil, false, nil
+	}
+	var path []pathEdge
+	for uid := ps.To; uid != ps.From; uid = prev[uid].from {
+		path = append([]pathEdge{prev[uid]}, path...)
+	}
+	return path, true, nil
+}
+
+// shortestPath finds the shortest path from the root UID to the one given by
+// sg.Params.ShortestPath, following the predicates given as children of sg.
+// The children of sg are then replaced by the path found, so that each level
+// of the result holds one edge of the path. If there is no path, the result is
+// empty.
+func (sg *SubGraph) shortestPath(ctx context.Context) error {
+	x.AssertTrue(len(sg.DestUIDs.Uids) == 1)
+	ps := &pathSearch{ShortestPath: sg.Params.ShortestPath}
+	sp := *ps.ShortestPath
+	ps.ShortestPath = &sp
+	ps.From = sg.DestUIDs.Uids[0]
+	if ps.MaxVisited == 0 {
+		ps.MaxVisited = defaultMaxVisited
+	}
+	for _, child := range sg.Children {
+		ps.attrs = append(ps.attrs, child.Attr)
+	}
+	sg.Children = nil
+
+	var path []pathEdge
+	var found bool
+	var err error
+	if len(ps.Weight) > 0 {
+		path, found, err = ps.dijkstra(ctx)
+	} else {
+		path, found, err = ps.bfs(ctx)
+	}
+	if err != nil {
+		return err
+	}
+	if !found {
+		x.Trace(ctx, "No path found from %#x to %#x", ps.From, ps.To)
+		sg.DestUIDs = new(task.List)
+		return nil
+	}
+
+	sg.Params.GetUID = true
+	cur := sg
+	for _, e := range path {
+		child := &SubGraph{
+			Attr:      e.attr,
+			Params:    params{GetUID: true},
+			SrcUIDs:   &task.List{Uids: []uint64{e.from}},
+			uidMatrix: []*task.List{{Uids: []uint64{e.to}}},
+		}
+		child.DestUIDs = child.uidMatrix[0]
+		cur.Children = append(cur.Children, child)
+		cur = child
+	}
+	return nil
+}
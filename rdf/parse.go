@@ -19,6 +19,7 @@ package rdf
 import (
 	"fmt"
 	"log"
+	"sort"
 	"strconv"
 	"strings"
 	"unicode"
@@ -28,6 +29,7 @@ import (
 	"github.com/dgraph-io/dgraph/lex"
 	"github.com/dgraph-io/dgraph/task"
 	"github.com/dgraph-io/dgraph/types"
+	"github.com/dgraph-io/dgraph/x"
 )
 
 var emptyEdge task.DirectedEdge
@@ -40,6 +42,8 @@ type NQuad struct {
 	ObjectValue []byte
 	ObjectType  byte
 	Label       string
+	Facets      []*task.Facet // Sorted by key.
+	Lang        string        // Language of the value, like en, if it has one.
 }
 
 // Gets the uid corresponding to an xid from the posting list which stores the
@@ -64,6 +68,8 @@ func (nq NQuad) ToEdge() (*task.DirectedEdge, error) {
 		Attr:   nq.Predicate,
 		Label:  nq.Label,
 		Entity: sid,
+		Facets: nq.Facets,
+		Lang:   nq.Lang,
 	}
 
 	// An edge can have an id or a value.
@@ -99,6 +105,8 @@ func (nq NQuad) ToEdgeUsing(newToUid map[string]uint64) (*task.DirectedEdge, err
 		Entity: uid,
 		Attr:   nq.Predicate,
 		Label:  nq.Label,
+		Facets: nq.Facets,
+		Lang:   nq.Lang,
 	}
 
 	if len(nq.ObjectId) == 0 {
@@ -158,11 +166,22 @@ func Parse(line string) (rnq NQuad, rerr error) {
 		case itemObject:
 			rnq.ObjectId = stripBracketsAndTrim(item.Val)
 
+		case itemStar:
+			if len(rnq.Predicate) == 0 {
+				rnq.Predicate = x.Star
+			} else {
+				rnq.ObjectValue = []byte(x.Star)
+			}
+
 		case itemLiteral:
 			oval = strings.Replace(item.Val, "'", "\"", -1)
 
 		case itemLanguage:
-			rnq.Predicate += "." + item.Val
+			// The value is kept under its predicate along with its language,
+			// and read as pred@lang. Data loaded before this was kept under a
+			// predicate of its own, like pred.lang, and is still read that way
+			// until it's loaded again.
+			rnq.Lang = item.Val
 
 		case itemObjectType:
 			if len(oval) == 0 {
@@ -198,6 +217,12 @@ func Parse(line string) (rnq NQuad, rerr error) {
 
 		case itemLabel:
 			rnq.Label = stripBracketsAndTrim(item.Val)
+
+		case itemFacets:
+			var err error
+			if rnq.Facets, err = parseFacets(item.Val); err != nil {
+				return rnq, err
+			}
 		}
 	}
 
@@ -215,14 +240,115 @@ func Parse(line string) (rnq NQuad, rerr error) {
 	if len(rnq.ObjectId) == 0 && rnq.ObjectValue == nil {
 		return rnq, fmt.Errorf("No Object in NQuad. Input: [%s]", line)
 	}
+	if rnq.Predicate == x.Star && string(rnq.ObjectValue) != x.Star {
+		return rnq, fmt.Errorf("Only * can follow * as the predicate. Input: [%s]", line)
+	}
 	if !sane(rnq.Subject) || !sane(rnq.Predicate) || !sane(rnq.ObjectId) ||
 		!sane(rnq.Label) {
 		return rnq, fmt.Errorf("NQuad failed sanity check:%+v", rnq)
 	}
+	if strings.HasPrefix(rnq.Predicate, "~") {
+		// These are used for reverse edges, which are maintained by us.
+		return rnq, fmt.Errorf("Predicate cannot start with ~. Input: [%s]", line)
+	}
 
 	return rnq, nil
 }
 
+// parseFacets parses the facets of an edge, given the text between the
+// brackets, like since=2014, close=true. Quoted values are strings, and the
+// type of the other values is guessed from their text.
+func parseFacets(s string) ([]*task.Facet, error) {
+	var facets []*task.Facet
+	for _, kv := range splitFacets(s) {
+		if len(strings.TrimSpace(kv)) == 0 {
+			continue
+		}
+		idx := strings.Index(kv, "=")
+		if idx < 0 {
+			return nil, fmt.Errorf("Expected key=value for facet, but got: %q", kv)
+		}
+		key := strings.TrimSpace(kv[:idx])
+		if len(key) == 0 || !sane(key) {
+			return nil, fmt.Errorf("Invalid facet key in: %q", kv)
+		}
+		f, err := facetValue(strings.TrimSpace(kv[idx+1:]))
+		if err != nil {
+			return nil, err
+		}
+		f.Key = key
+		facets = append(facets, f)
+	}
+	sort.Sort(byKey(facets))
+	for i := 1; i < len(facets); i++ {
+		if facets[i-1].Key == facets[i].Key {
+			return nil, fmt.Errorf("Repeated facet key: %s", facets[i].Key)
+		}
+	}
+	return facets, nil
+}
+
+type byKey []*task.Facet
+
+func (f byKey) Len() int           { return len(f) }
+func (f byKey) Swap(i, j int)      { f[i], f[j] = f[j], f[i] }
+func (f byKey) Less(i, j int) bool { return f[i].Key < f[j].Key }
+
+// splitFacets splits s at the commas which are not inside a quoted value.
+func splitFacets(s string) []string {
+	var out []string
+	var quoted bool
+	start := 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '"':
+			quoted = !quoted
+		case ',':
+			if !quoted {
+				out = append(out, s[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(out, s[start:])
+}
+
+// facetTypes are the types tried, in order, for a facet value which isn't
+// quoted, and isn't true or false.
+var facetTypes = []types.TypeID{types.Int32ID, types.FloatID, types.DateTimeID}
+
+// facetValue returns a facet holding the binary form of the value in text.
+func facetValue(text string) (*task.Facet, error) {
+	if len(text) >= 2 && text[0] == '"' && text[len(text)-1] == '"' {
+		unq, err := strconv.Unquote(text)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid facet value: %s", text)
+		}
+		return &task.Facet{Value: []byte(unq), ValType: uint32(types.StringID)}, nil
+	}
+	if len(text) == 0 {
+		return nil, fmt.Errorf("Empty facet value")
+	}
+	if text == "true" || text == "false" {
+		b, _ := types.Bool(text == "true").MarshalBinary()
+		return &task.Facet{Value: b, ValType: uint32(types.BoolID)}, nil
+	}
+	for _, t := range facetTypes {
+		v := types.ValueForType(t)
+		if err := v.UnmarshalText([]byte(text)); err != nil {
+			continue
+		}
+		b, err := v.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		return &task.Facet{Value: b, ValType: uint32(t)}, nil
+	}
+	return &task.Facet{Value: []byte(text), ValType: uint32(types.StringID)}, nil
+}
+
 func isNewline(r rune) bool {
 	return r == '\n' || r == '\r'
 }
@@ -20,6 +20,9 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/dgraph-io/dgraph/task"
+	"github.com/dgraph-io/dgraph/x"
 )
 
 var testNQuads = []struct {
@@ -103,9 +106,10 @@ var testNQuads = []struct {
 		input: `_:alice <name> "Alice In Wonderland"@en-0 .`,
 		nq: NQuad{
 			Subject:     "_:alice",
-			Predicate:   "name.en-0",
+			Predicate:   "name",
 			ObjectId:    "",
 			ObjectValue: []byte("Alice In Wonderland"),
+			Lang:        "en-0",
 		},
 	},
 	{
@@ -122,9 +126,10 @@ var testNQuads = []struct {
 		input: `<http://www.w3.org/2001/sw/RDFCore/nedges/> <http://purl.org/dc/terms/title> "N-Edges"@en-US .`,
 		nq: NQuad{
 			Subject:     "http://www.w3.org/2001/sw/RDFCore/nedges/",
-			Predicate:   "http://purl.org/dc/terms/title.en-US",
+			Predicate:   "http://purl.org/dc/terms/title",
 			ObjectId:    "",
 			ObjectValue: []byte("N-Edges"),
+			Lang:        "en-US",
 		},
 	},
 	{
@@ -148,6 +153,10 @@ var testNQuads = []struct {
 		input:       "<alice> <knows> .",
 		expectedErr: true,
 	},
+	{
+		input:       "<alice> <~knows> <bob> .",
+		expectedErr: true,
+	},
 	{
 		input:       "_uid_: 0x01 <knows> <something> .",
 		expectedErr: true,
@@ -192,10 +201,6 @@ var testNQuads = []struct {
 		input:       `_:alice <age> "thirteen"^^<xs:int> .`,
 		expectedErr: true,
 	},
-	{
-		input:       `<alice> <knows> * .`,
-		expectedErr: true,
-	},
 	{
 		input:       `<alice> <knows> <*> .`,
 		expectedErr: true,
@@ -317,6 +322,90 @@ var testNQuads = []struct {
 			ObjectValue: []byte(`mov\"enpick`),
 		},
 	},
+	{
+		input: `_:alice <friend> <bob> (since=2014, close=true, name="Bob, \"B\"") .`,
+		nq: NQuad{
+			Subject:   "_:alice",
+			Predicate: "friend",
+			ObjectId:  "bob",
+			Facets: []*task.Facet{
+				{Key: "close", Value: []byte{1}, ValType: 3},
+				{Key: "name", Value: []byte(`Bob, "B"`), ValType: 5},
+				{Key: "since", Value: []byte{0xde, 0x07, 0, 0}, ValType: 1},
+			},
+		},
+	},
+	{
+		input: `_:alice <friend> <bob> <label> (since=2014) .`,
+		nq: NQuad{
+			Subject:   "_:alice",
+			Predicate: "friend",
+			ObjectId:  "bob",
+			Label:     "label",
+			Facets: []*task.Facet{
+				{Key: "since", Value: []byte{0xde, 0x07, 0, 0}, ValType: 1},
+			},
+		},
+	},
+	{
+		input: `_:alice <name> "Alice" (origin=french) .`,
+		nq: NQuad{
+			Subject:     "_:alice",
+			Predicate:   "name",
+			ObjectValue: []byte("Alice"),
+			Facets: []*task.Facet{
+				{Key: "origin", Value: []byte("french"), ValType: 5},
+			},
+		},
+	},
+	{
+		input:       `_:alice <friend> <bob> (since=2014) <label> .`,
+		expectedErr: true,
+	},
+	{
+		input:       `_:alice <friend> <bob> (since=2014, since=2015) .`,
+		expectedErr: true,
+	},
+	{
+		input:       `_:alice <friend> <bob> (since) .`,
+		expectedErr: true,
+	},
+	{
+		input:       `_:alice <friend> <bob> (since=2014 .`,
+		expectedErr: true,
+	},
+	{
+		input:       `_:alice <friend> (since=2014) <bob> .`,
+		expectedErr: true,
+	},
+	{
+		input: `<alice> <friend> * .`,
+		nq: NQuad{
+			Subject:     "alice",
+			Predicate:   "friend",
+			ObjectValue: []byte(x.Star),
+		},
+	},
+	{
+		input: `<alice> * * .`,
+		nq: NQuad{
+			Subject:     "alice",
+			Predicate:   x.Star,
+			ObjectValue: []byte(x.Star),
+		},
+	},
+	{
+		input:       `<alice> * <bob> .`,
+		expectedErr: true,
+	},
+	{
+		input:       `* <friend> <bob> .`,
+		expectedErr: true,
+	},
+	{
+		input:       `<alice> <friend> * <label> * .`,
+		expectedErr: true,
+	},
 }
 
 func TestLex(t *testing.T) {
@@ -36,6 +36,8 @@ const (
 	itemLanguage                           // language, 11
 	itemObjectType                         // object type, 12
 	itemValidEnd                           // end with dot, 13
+	itemFacets                             // facets, 14
+	itemStar                               // star, 15
 )
 
 // These constants keep a track of the depth while parsing an rdf N-Quad.
@@ -44,6 +46,7 @@ const (
 	atPredicate
 	atObject
 	atLabel
+	atFacets
 )
 
 func run(l *lex.Lexer) {
@@ -93,6 +96,22 @@ Loop:
 			l.Emit(itemText)
 			return lexObject
 
+		case r == '*':
+			if l.Depth != atPredicate && l.Depth != atObject {
+				return l.Errorf("Invalid input: %c at lexText", r)
+			}
+			l.Backup()
+			l.Emit(itemText)
+			return lexStar
+
+		case r == '(':
+			if l.Depth < atLabel || l.Depth > atFacets {
+				return l.Errorf("Invalid input: %c at lexText", r)
+			}
+			l.Backup()
+			l.Emit(itemText)
+			return lexFacets
+
 		case r == lex.EOF:
 			break Loop
 
@@ -311,6 +330,15 @@ func lexObject(l *lex.Lexer) lex.StateFn {
 	return l.Errorf("Invalid char: %v at lexObject", r)
 }
 
+// lexStar lexes the * of a deletion, which can be the predicate or the object.
+// A * predicate can only be followed by a * object, which the parser checks.
+func lexStar(l *lex.Lexer) lex.StateFn {
+	l.Next() // Consume the *.
+	l.Depth++
+	l.Emit(itemStar)
+	return lexText
+}
+
 func lexLabel(l *lex.Lexer) lex.StateFn {
 	r := l.Next()
 	// Graph label can either be an IRI or a blank node according to spec.
@@ -332,6 +360,33 @@ func lexLabel(l *lex.Lexer) lex.StateFn {
 	return l.Errorf("Invalid char: %v at lexLabel", r)
 }
 
+// lexFacets lexes the facets of an edge, like (since=2014, close=true), which
+// come after the object or the label. The text inside the brackets is emitted
+// as it is, and split into facets by the parser.
+func lexFacets(l *lex.Lexer) lex.StateFn {
+	l.Next() // Consume the (.
+	l.Ignore()
+	var quoted bool
+	for {
+		r := l.Next()
+		switch {
+		case r == lex.EOF || isNewline(r):
+			return l.Errorf("Unclosed facets")
+		case r == '\\' && quoted:
+			l.Next() // Skip over the escaped rune.
+		case r == '"':
+			quoted = !quoted
+		case r == ')' && !quoted:
+			l.Backup()
+			l.Emit(itemFacets)
+			l.Next()
+			l.Ignore()
+			l.Depth = atFacets + 1 // Nothing but the dot can follow.
+			return lexText
+		}
+	}
+}
+
 func isClosingBracket(r rune) bool {
 	return r == '>'
 }
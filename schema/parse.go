@@ -114,9 +114,13 @@ func processScalarBlock(l *lex.Lexer) error {
 				next = <-l.Items
 				if next.Typ == itemAt {
 					index := <-l.Items
-					if index.Typ == itemIndex {
+					switch index.Typ {
+					case itemIndex:
 						indexedFields[name] = true
-					} else {
+					case itemFullTextIndex:
+						indexedFields[name] = true
+						fullTextFields[name] = true
+					default:
 						return x.Errorf("Invalid index specification")
 					}
 				}
@@ -162,9 +166,13 @@ func processScalar(l *lex.Lexer) error {
 				next = <-l.Items
 				if next.Typ == itemAt {
 					index := <-l.Items
-					if index.Typ == itemIndex {
+					switch index.Typ {
+					case itemIndex:
 						indexedFields[name] = true
-					} else {
+					case itemFullTextIndex:
+						indexedFields[name] = true
+						fullTextFields[name] = true
+					default:
 						return x.Errorf("Invalid index specification")
 					}
 				}
@@ -195,14 +203,33 @@ func processObject(l *lex.Lexer) error {
 		return x.Errorf("Missing left curly brace")
 	}
 
+	var name string // Name of the last field, for the @reverse and @count directives.
 L:
 	for item := range l.Items {
 		switch item.Typ {
 		case itemRightCurl:
 			break L
+		case itemAt:
+			next := <-l.Items
+			switch next.Typ {
+			case itemReverse:
+				if _, ok := getScalar(obj.Fields[name]); ok {
+					return x.Errorf("Cannot reverse scalar field %v in object %v",
+						name, objName)
+				}
+				reversedFields[name] = true
+			case itemCount:
+				if _, ok := getScalar(obj.Fields[name]); ok {
+					return x.Errorf("Cannot count scalar field %v in object %v",
+						name, objName)
+				}
+				countedFields[name] = true
+			default:
+				return x.Errorf("Invalid directive specification")
+			}
 		case itemObjectName:
 			{
-				var name, typ string
+				var typ string
 				name = item.Val
 
 				next := <-l.Items
@@ -86,3 +86,61 @@ func TestSchemaIndex_Error2(t *testing.T) {
 	indexedFields = make(map[string]bool)
 	require.Error(t, Parse("testfiles/test_schema_index3"))
 }
+
+// Correct specification of full-text indexes.
+func TestSchemaFullText(t *testing.T) {
+	str = make(map[string]types.Type)
+	indexedFields = make(map[string]bool)
+	fullTextFields = make(map[string]bool)
+	require.NoError(t, Parse("testfiles/test_schema_fulltext1"))
+	require.True(t, IsFullText("description"))
+	require.True(t, IsIndexed("description"))
+	require.True(t, IsFullText("bio"))
+	require.True(t, IsIndexed("name"))
+	require.False(t, IsFullText("name"))
+}
+
+// Only full-text indexes can be asked for by kind.
+func TestSchemaFullText_Error(t *testing.T) {
+	str = make(map[string]types.Type)
+	indexedFields = make(map[string]bool)
+	fullTextFields = make(map[string]bool)
+	require.Error(t, Parse("testfiles/test_schema_fulltext2"))
+}
+
+// Correct specification of reverse edges.
+func TestSchemaReverse(t *testing.T) {
+	str = make(map[string]types.Type)
+	reversedFields = make(map[string]bool)
+	require.NoError(t, Parse("testfiles/test_schema_reverse1"))
+	require.True(t, IsReversed("friend"))
+	require.False(t, IsReversed("follows"))
+	require.False(t, IsReversed("name"))
+}
+
+// Scalar fields can't be reversed.
+func TestSchemaReverse_Error(t *testing.T) {
+	str = make(map[string]types.Type)
+	reversedFields = make(map[string]bool)
+	require.Error(t, Parse("testfiles/test_schema_reverse2"))
+}
+
+// Correct specification of the count index.
+func TestSchemaCount(t *testing.T) {
+	str = make(map[string]types.Type)
+	reversedFields = make(map[string]bool)
+	countedFields = make(map[string]bool)
+	require.NoError(t, Parse("testfiles/test_schema_count1"))
+	require.True(t, IsCounted("friend"))
+	require.True(t, IsReversed("friend"))
+	require.True(t, IsCounted("follows"))
+	require.False(t, IsCounted("knows"))
+	require.False(t, IsCounted("name"))
+}
+
+// Scalar fields can't have a count index.
+func TestSchemaCount_Error(t *testing.T) {
+	str = make(map[string]types.Type)
+	countedFields = make(map[string]bool)
+	require.Error(t, Parse("testfiles/test_schema_count2"))
+}
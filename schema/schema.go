@@ -29,11 +29,20 @@ var (
 	str map[string]types.Type
 	// Map containing fields that are indexed.
 	indexedFields map[string]bool
+	// Map containing string fields which are also indexed for full-text search.
+	fullTextFields map[string]bool
+	// Map containing UID fields which also store reverse edges.
+	reversedFields map[string]bool
+	// Map containing UID fields which have an index on their number of edges.
+	countedFields map[string]bool
 )
 
 func init() {
 	str = make(map[string]types.Type)
 	indexedFields = make(map[string]bool)
+	fullTextFields = make(map[string]bool)
+	reversedFields = make(map[string]bool)
+	countedFields = make(map[string]bool)
 }
 
 // IsIndexed returns if a given predicated is indexed or not.
@@ -41,6 +50,22 @@ func IsIndexed(str string) bool {
 	return indexedFields[str]
 }
 
+// IsFullText returns if a given predicate has a full-text index or not.
+func IsFullText(str string) bool {
+	return fullTextFields[str]
+}
+
+// IsReversed returns if a given predicate has reverse edges or not.
+func IsReversed(str string) bool {
+	return reversedFields[str]
+}
+
+// IsCounted returns if a given predicate has an index on the number of its
+// edges or not.
+func IsCounted(str string) bool {
+	return countedFields[str]
+}
+
 // ScalarList returns the list of scalars in the geiven object.
 func ScalarList(obj string) []Item {
 	var res []Item
@@ -79,3 +104,13 @@ func IndexedFields() []string {
 	}
 	return out
 }
+
+// CountedFields returns a list of fields with an index on their number of
+// edges.
+func CountedFields() []string {
+	out := make([]string, 0, len(countedFields))
+	for k := range countedFields {
+		out = append(out, k)
+	}
+	return out
+}
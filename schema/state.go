@@ -44,7 +44,10 @@ const (
 	itemCollon
 	itemAt
 	itemIndex
-	itemDummy // Used if index specification is missing
+	itemDummy         // Used if index specification is missing
+	itemReverse       // reverse directive on an object field
+	itemCount         // count directive on an object field
+	itemFullTextIndex // index with full-text search, written @index(fulltext)
 )
 
 // lexText lexes the input string and calls other lex functions.
@@ -229,12 +232,19 @@ L1:
 				l.Backup()
 				// l.Pos would be index of the end of operation type + 1.
 				word := l.Input[l.Start:l.Pos]
-				if word == "index" {
+				if word != "index" {
+					return l.Errorf("Invalid mention of index")
+				}
+				if l.Peek() != '(' {
 					l.Emit(itemIndex)
 					break L1
-				} else {
-					return l.Errorf("Invalid mention of index")
 				}
+				l.AcceptUntil(func(r rune) bool { return r == ')' || isEndOfLine(r) })
+				if l.Next() != ')' || l.Input[l.Start:l.Pos] != "index(fulltext)" {
+					return l.Errorf("Invalid index kind %s", l.Input[l.Start:l.Pos])
+				}
+				l.Emit(itemFullTextIndex)
+				break L1
 			}
 			break L1
 		default:
@@ -305,12 +315,19 @@ L1:
 				l.Backup()
 				// l.Pos would be index of the end of operation type + 1.
 				word := l.Input[l.Start:l.Pos]
-				if word == "index" {
+				if word != "index" {
+					return l.Errorf("Invalid mention of index")
+				}
+				if l.Peek() != '(' {
 					l.Emit(itemIndex)
 					break L1
-				} else {
-					return l.Errorf("Invalid mention of index")
 				}
+				l.AcceptUntil(func(r rune) bool { return r == ')' || isEndOfLine(r) })
+				if l.Next() != ')' || l.Input[l.Start:l.Pos] != "index(fulltext)" {
+					return l.Errorf("Invalid index kind %s", l.Input[l.Start:l.Pos])
+				}
+				l.Emit(itemFullTextIndex)
+				break L1
 			}
 			break L1
 		default:
@@ -360,7 +377,27 @@ L:
 		break
 	}
 
-	return lexObjectBlock
+	// Check for the mention of @reverse and @count.
+	for {
+		switch r := l.Next(); {
+		case isSpace(r):
+			l.Ignore()
+		case r == '@':
+			l.Emit(itemAt)
+			l.AcceptRun(isNameSuffix)
+			switch word := l.Input[l.Start:l.Pos]; word {
+			case "reverse":
+				l.Emit(itemReverse)
+			case "count":
+				l.Emit(itemCount)
+			default:
+				return l.Errorf("Invalid mention of %s", word)
+			}
+		default:
+			l.Backup()
+			return lexObjectBlock
+		}
+	}
 
 }
 
@@ -25,6 +25,13 @@
 		KV
 		KC
 		GroupKeys
+		Facet
+		Facets
+		FacetsList
+		FacetParams
+		Txn
+		Order
+		Lease
 */
 package task
 
@@ -73,7 +80,9 @@ type Query struct {
 	// Exactly one of uids and terms is populated.
 	Uids []uint64 `protobuf:"fixed64,6,rep,packed,name=uids" json:"uids,omitempty"`
 	// Function to generate or filter UIDs.
-	SrcFunc []string `protobuf:"bytes,7,rep,name=src_func,json=srcFunc" json:"src_func,omitempty"`
+	SrcFunc    []string     `protobuf:"bytes,7,rep,name=src_func,json=srcFunc" json:"src_func,omitempty"`
+	FacetParam *FacetParams `protobuf:"bytes,8,opt,name=facet_param,json=facetParam" json:"facet_param,omitempty"`
+	Langs      []string     `protobuf:"bytes,9,rep,name=langs" json:"langs,omitempty"`
 }
 
 func (m *Query) Reset()                    { *m = Query{} }
@@ -81,11 +90,21 @@ func (m *Query) String() string            { return proto.CompactTextString(m) }
 func (*Query) ProtoMessage()               {}
 func (*Query) Descriptor() ([]byte, []int) { return fileDescriptorTask, []int{2} }
 
+func (m *Query) GetFacetParam() *FacetParams {
+	if m != nil {
+		return m.FacetParam
+	}
+	return nil
+}
+
 type Result struct {
-	UidMatrix     []*List  `protobuf:"bytes,1,rep,name=uid_matrix,json=uidMatrix" json:"uid_matrix,omitempty"`
-	Values        []*Value `protobuf:"bytes,2,rep,name=values" json:"values,omitempty"`
-	Counts        []uint32 `protobuf:"varint,3,rep,packed,name=counts" json:"counts,omitempty"`
-	IntersectDest bool     `protobuf:"varint,4,opt,name=intersectDest,proto3" json:"intersectDest,omitempty"`
+	UidMatrix     []*List       `protobuf:"bytes,1,rep,name=uid_matrix,json=uidMatrix" json:"uid_matrix,omitempty"`
+	Values        []*Value      `protobuf:"bytes,2,rep,name=values" json:"values,omitempty"`
+	Counts        []uint32      `protobuf:"varint,3,rep,packed,name=counts" json:"counts,omitempty"`
+	IntersectDest bool          `protobuf:"varint,4,opt,name=intersectDest,proto3" json:"intersectDest,omitempty"`
+	Scores        []float64     `protobuf:"fixed64,5,rep,packed,name=scores" json:"scores,omitempty"`
+	FacetMatrix   []*FacetsList `protobuf:"bytes,6,rep,name=facet_matrix,json=facetMatrix" json:"facet_matrix,omitempty"`
+	Predicates    []string      `protobuf:"bytes,7,rep,name=predicates" json:"predicates,omitempty"`
 }
 
 func (m *Result) Reset()                    { *m = Result{} }
@@ -93,6 +112,13 @@ func (m *Result) String() string            { return proto.CompactTextString(m)
 func (*Result) ProtoMessage()               {}
 func (*Result) Descriptor() ([]byte, []int) { return fileDescriptorTask, []int{3} }
 
+func (m *Result) GetFacetMatrix() []*FacetsList {
+	if m != nil {
+		return m.FacetMatrix
+	}
+	return nil
+}
+
 func (m *Result) GetUidMatrix() []*List {
 	if m != nil {
 		return m.UidMatrix
@@ -108,10 +134,12 @@ func (m *Result) GetValues() []*Value {
 }
 
 type Sort struct {
-	Attr      string  `protobuf:"bytes,1,opt,name=attr,proto3" json:"attr,omitempty"`
-	UidMatrix []*List `protobuf:"bytes,2,rep,name=uid_matrix,json=uidMatrix" json:"uid_matrix,omitempty"`
-	Count     int32   `protobuf:"varint,3,opt,name=count,proto3" json:"count,omitempty"`
-	Offset    int32   `protobuf:"varint,4,opt,name=offset,proto3" json:"offset,omitempty"`
+	Attr      string   `protobuf:"bytes,1,opt,name=attr,proto3" json:"attr,omitempty"`
+	UidMatrix []*List  `protobuf:"bytes,2,rep,name=uid_matrix,json=uidMatrix" json:"uid_matrix,omitempty"`
+	Count     int32    `protobuf:"varint,3,opt,name=count,proto3" json:"count,omitempty"`
+	Offset    int32    `protobuf:"varint,4,opt,name=offset,proto3" json:"offset,omitempty"`
+	Desc      bool     `protobuf:"varint,5,opt,name=desc,proto3" json:"desc,omitempty"`
+	ThenBy    []*Order `protobuf:"bytes,6,rep,name=then_by,json=thenBy" json:"then_by,omitempty"`
 }
 
 func (m *Sort) Reset()                    { *m = Sort{} }
@@ -119,6 +147,13 @@ func (m *Sort) String() string            { return proto.CompactTextString(m) }
 func (*Sort) ProtoMessage()               {}
 func (*Sort) Descriptor() ([]byte, []int) { return fileDescriptorTask, []int{4} }
 
+func (m *Sort) GetThenBy() []*Order {
+	if m != nil {
+		return m.ThenBy
+	}
+	return nil
+}
+
 func (m *Sort) GetUidMatrix() []*List {
 	if m != nil {
 		return m.UidMatrix
@@ -204,12 +239,14 @@ func (m *MembershipUpdate) GetMembers() []*Membership {
 }
 
 type DirectedEdge struct {
-	Entity    uint64 `protobuf:"fixed64,1,opt,name=entity,proto3" json:"entity,omitempty"`
-	Attr      string `protobuf:"bytes,2,opt,name=attr,proto3" json:"attr,omitempty"`
-	Value     []byte `protobuf:"bytes,3,opt,name=value,proto3" json:"value,omitempty"`
-	ValueType uint32 `protobuf:"varint,4,opt,name=value_type,json=valueType,proto3" json:"value_type,omitempty"`
-	ValueId   uint64 `protobuf:"fixed64,5,opt,name=value_id,json=valueId,proto3" json:"value_id,omitempty"`
-	Label     string `protobuf:"bytes,6,opt,name=label,proto3" json:"label,omitempty"`
+	Entity    uint64   `protobuf:"fixed64,1,opt,name=entity,proto3" json:"entity,omitempty"`
+	Attr      string   `protobuf:"bytes,2,opt,name=attr,proto3" json:"attr,omitempty"`
+	Value     []byte   `protobuf:"bytes,3,opt,name=value,proto3" json:"value,omitempty"`
+	ValueType uint32   `protobuf:"varint,4,opt,name=value_type,json=valueType,proto3" json:"value_type,omitempty"`
+	ValueId   uint64   `protobuf:"fixed64,5,opt,name=value_id,json=valueId,proto3" json:"value_id,omitempty"`
+	Label     string   `protobuf:"bytes,6,opt,name=label,proto3" json:"label,omitempty"`
+	Facets    []*Facet `protobuf:"bytes,7,rep,name=facets" json:"facets,omitempty"`
+	Lang      string   `protobuf:"bytes,8,opt,name=lang,proto3" json:"lang,omitempty"`
 }
 
 func (m *DirectedEdge) Reset()                    { *m = DirectedEdge{} }
@@ -217,10 +254,19 @@ func (m *DirectedEdge) String() string            { return proto.CompactTextStri
 func (*DirectedEdge) ProtoMessage()               {}
 func (*DirectedEdge) Descriptor() ([]byte, []int) { return fileDescriptorTask, []int{10} }
 
+func (m *DirectedEdge) GetFacets() []*Facet {
+	if m != nil {
+		return m.Facets
+	}
+	return nil
+}
+
 type Mutations struct {
 	GroupId uint32          `protobuf:"varint,1,opt,name=group_id,json=groupId,proto3" json:"group_id,omitempty"`
 	Set     []*DirectedEdge `protobuf:"bytes,2,rep,name=set" json:"set,omitempty"`
 	Del     []*DirectedEdge `protobuf:"bytes,3,rep,name=del" json:"del,omitempty"`
+	StartTs uint64          `protobuf:"varint,4,opt,name=start_ts,json=startTs,proto3" json:"start_ts,omitempty"`
+	Lookups []*Query        `protobuf:"bytes,5,rep,name=lookups" json:"lookups,omitempty"`
 }
 
 func (m *Mutations) Reset()                    { *m = Mutations{} }
@@ -228,6 +274,13 @@ func (m *Mutations) String() string            { return proto.CompactTextString(
 func (*Mutations) ProtoMessage()               {}
 func (*Mutations) Descriptor() ([]byte, []int) { return fileDescriptorTask, []int{11} }
 
+func (m *Mutations) GetLookups() []*Query {
+	if m != nil {
+		return m.Lookups
+	}
+	return nil
+}
+
 func (m *Mutations) GetSet() []*DirectedEdge {
 	if m != nil {
 		return m.Set
@@ -243,9 +296,12 @@ func (m *Mutations) GetDel() []*DirectedEdge {
 }
 
 type Proposal struct {
-	Id         uint32      `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
-	Mutations  *Mutations  `protobuf:"bytes,2,opt,name=mutations" json:"mutations,omitempty"`
-	Membership *Membership `protobuf:"bytes,3,opt,name=membership" json:"membership,omitempty"`
+	Id           uint32      `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Mutations    *Mutations  `protobuf:"bytes,2,opt,name=mutations" json:"mutations,omitempty"`
+	Membership   *Membership `protobuf:"bytes,3,opt,name=membership" json:"membership,omitempty"`
+	Txn          *Txn        `protobuf:"bytes,4,opt,name=txn" json:"txn,omitempty"`
+	Lease        *Lease      `protobuf:"bytes,5,opt,name=lease" json:"lease,omitempty"`
+	RebuildIndex string      `protobuf:"bytes,6,opt,name=rebuild_index,json=rebuildIndex,proto3" json:"rebuild_index,omitempty"`
 }
 
 func (m *Proposal) Reset()                    { *m = Proposal{} }
@@ -253,6 +309,13 @@ func (m *Proposal) String() string            { return proto.CompactTextString(m
 func (*Proposal) ProtoMessage()               {}
 func (*Proposal) Descriptor() ([]byte, []int) { return fileDescriptorTask, []int{12} }
 
+func (m *Proposal) GetLease() *Lease {
+	if m != nil {
+		return m.Lease
+	}
+	return nil
+}
+
 func (m *Proposal) GetMutations() *Mutations {
 	if m != nil {
 		return m.Mutations
@@ -267,6 +330,13 @@ func (m *Proposal) GetMembership() *Membership {
 	return nil
 }
 
+func (m *Proposal) GetTxn() *Txn {
+	if m != nil {
+		return m.Txn
+	}
+	return nil
+}
+
 type KV struct {
 	Key []byte `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
 	Val []byte `protobuf:"bytes,2,opt,name=val,proto3" json:"val,omitempty"`
@@ -304,6 +374,91 @@ func (m *GroupKeys) GetKeys() []*KC {
 	return nil
 }
 
+type Facet struct {
+	Key     string `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Value   []byte `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+	ValType uint32 `protobuf:"varint,3,opt,name=val_type,json=valType,proto3" json:"val_type,omitempty"`
+}
+
+func (m *Facet) Reset()                    { *m = Facet{} }
+func (m *Facet) String() string            { return proto.CompactTextString(m) }
+func (*Facet) ProtoMessage()               {}
+func (*Facet) Descriptor() ([]byte, []int) { return fileDescriptorTask, []int{16} }
+
+type Facets struct {
+	Facets []*Facet `protobuf:"bytes,1,rep,name=facets" json:"facets,omitempty"`
+}
+
+func (m *Facets) Reset()                    { *m = Facets{} }
+func (m *Facets) String() string            { return proto.CompactTextString(m) }
+func (*Facets) ProtoMessage()               {}
+func (*Facets) Descriptor() ([]byte, []int) { return fileDescriptorTask, []int{17} }
+
+func (m *Facets) GetFacets() []*Facet {
+	if m != nil {
+		return m.Facets
+	}
+	return nil
+}
+
+type FacetsList struct {
+	FacetsList []*Facets `protobuf:"bytes,1,rep,name=facets_list,json=facetsList" json:"facets_list,omitempty"`
+}
+
+func (m *FacetsList) Reset()                    { *m = FacetsList{} }
+func (m *FacetsList) String() string            { return proto.CompactTextString(m) }
+func (*FacetsList) ProtoMessage()               {}
+func (*FacetsList) Descriptor() ([]byte, []int) { return fileDescriptorTask, []int{18} }
+
+func (m *FacetsList) GetFacetsList() []*Facets {
+	if m != nil {
+		return m.FacetsList
+	}
+	return nil
+}
+
+type FacetParams struct {
+	AllKeys bool     `protobuf:"varint,1,opt,name=all_keys,json=allKeys,proto3" json:"all_keys,omitempty"`
+	Keys    []string `protobuf:"bytes,2,rep,name=keys" json:"keys,omitempty"`
+}
+
+func (m *FacetParams) Reset()                    { *m = FacetParams{} }
+func (m *FacetParams) String() string            { return proto.CompactTextString(m) }
+func (*FacetParams) ProtoMessage()               {}
+func (*FacetParams) Descriptor() ([]byte, []int) { return fileDescriptorTask, []int{19} }
+
+type Txn struct {
+	GroupId  uint32 `protobuf:"varint,1,opt,name=group_id,json=groupId,proto3" json:"group_id,omitempty"`
+	StartTs  uint64 `protobuf:"varint,2,opt,name=start_ts,json=startTs,proto3" json:"start_ts,omitempty"`
+	CommitTs uint64 `protobuf:"varint,3,opt,name=commit_ts,json=commitTs,proto3" json:"commit_ts,omitempty"`
+	Primary  bool   `protobuf:"varint,4,opt,name=primary,proto3" json:"primary,omitempty"`
+}
+
+func (m *Txn) Reset()                    { *m = Txn{} }
+func (m *Txn) String() string            { return proto.CompactTextString(m) }
+func (*Txn) ProtoMessage()               {}
+func (*Txn) Descriptor() ([]byte, []int) { return fileDescriptorTask, []int{20} }
+
+type Order struct {
+	Attr string `protobuf:"bytes,1,opt,name=attr,proto3" json:"attr,omitempty"`
+	Desc bool   `protobuf:"varint,2,opt,name=desc,proto3" json:"desc,omitempty"`
+}
+
+func (m *Order) Reset()                    { *m = Order{} }
+func (m *Order) String() string            { return proto.CompactTextString(m) }
+func (*Order) ProtoMessage()               {}
+func (*Order) Descriptor() ([]byte, []int) { return fileDescriptorTask, []int{21} }
+
+type Lease struct {
+	From uint64 `protobuf:"varint,1,opt,name=from,proto3" json:"from,omitempty"`
+	To   uint64 `protobuf:"varint,2,opt,name=to,proto3" json:"to,omitempty"`
+}
+
+func (m *Lease) Reset()                    { *m = Lease{} }
+func (m *Lease) String() string            { return proto.CompactTextString(m) }
+func (*Lease) ProtoMessage()               {}
+func (*Lease) Descriptor() ([]byte, []int) { return fileDescriptorTask, []int{22} }
+
 func init() {
 	proto.RegisterType((*List)(nil), "task.List")
 	proto.RegisterType((*Value)(nil), "task.Value")
@@ -321,6 +476,13 @@ func init() {
 	proto.RegisterType((*KV)(nil), "task.KV")
 	proto.RegisterType((*KC)(nil), "task.KC")
 	proto.RegisterType((*GroupKeys)(nil), "task.GroupKeys")
+	proto.RegisterType((*Facet)(nil), "task.Facet")
+	proto.RegisterType((*Facets)(nil), "task.Facets")
+	proto.RegisterType((*FacetsList)(nil), "task.FacetsList")
+	proto.RegisterType((*FacetParams)(nil), "task.FacetParams")
+	proto.RegisterType((*Txn)(nil), "task.Txn")
+	proto.RegisterType((*Order)(nil), "task.Order")
+	proto.RegisterType((*Lease)(nil), "task.Lease")
 }
 func (m *List) Marshal() (data []byte, err error) {
 	size := m.Size()
@@ -476,6 +638,31 @@ func (m *Query) MarshalTo(data []byte) (int, error) {
 			i += copy(data[i:], s)
 		}
 	}
+	if m.FacetParam != nil {
+		data[i] = 0x42
+		i++
+		i = encodeVarintTask(data, i, uint64(m.FacetParam.Size()))
+		n7, err := m.FacetParam.MarshalTo(data[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n7
+	}
+	if len(m.Langs) > 0 {
+		for _, s := range m.Langs {
+			data[i] = 0x4a
+			i++
+			l = len(s)
+			for l >= 1<<7 {
+				data[i] = uint8(uint64(l)&0x7f | 0x80)
+				l >>= 7
+				i++
+			}
+			data[i] = uint8(l)
+			i++
+			i += copy(data[i:], s)
+		}
+	}
 	return i, nil
 }
 
@@ -545,6 +732,57 @@ func (m *Result) MarshalTo(data []byte) (int, error) {
 		}
 		i++
 	}
+	if len(m.Scores) > 0 {
+		data[i] = 0x2a
+		i++
+		i = encodeVarintTask(data, i, uint64(len(m.Scores)*8))
+		for _, num := range m.Scores {
+			f1 := math.Float64bits(float64(num))
+			data[i] = uint8(f1)
+			i++
+			data[i] = uint8(f1 >> 8)
+			i++
+			data[i] = uint8(f1 >> 16)
+			i++
+			data[i] = uint8(f1 >> 24)
+			i++
+			data[i] = uint8(f1 >> 32)
+			i++
+			data[i] = uint8(f1 >> 40)
+			i++
+			data[i] = uint8(f1 >> 48)
+			i++
+			data[i] = uint8(f1 >> 56)
+			i++
+		}
+	}
+	if len(m.FacetMatrix) > 0 {
+		for _, msg := range m.FacetMatrix {
+			data[i] = 0x32
+			i++
+			i = encodeVarintTask(data, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(data[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
+	if len(m.Predicates) > 0 {
+		for _, s := range m.Predicates {
+			data[i] = 0x3a
+			i++
+			l = len(s)
+			for l >= 1<<7 {
+				data[i] = uint8(uint64(l)&0x7f | 0x80)
+				l >>= 7
+				i++
+			}
+			data[i] = uint8(l)
+			i++
+			i += copy(data[i:], s)
+		}
+	}
 	return i, nil
 }
 
@@ -591,6 +829,28 @@ func (m *Sort) MarshalTo(data []byte) (int, error) {
 		i++
 		i = encodeVarintTask(data, i, uint64(m.Offset))
 	}
+	if m.Desc {
+		data[i] = 0x28
+		i++
+		if m.Desc {
+			data[i] = 1
+		} else {
+			data[i] = 0
+		}
+		i++
+	}
+	if len(m.ThenBy) > 0 {
+		for _, msg := range m.ThenBy {
+			data[i] = 0x32
+			i++
+			i = encodeVarintTask(data, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(data[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
 	return i, nil
 }
 
@@ -861,6 +1121,24 @@ func (m *DirectedEdge) MarshalTo(data []byte) (int, error) {
 		i = encodeVarintTask(data, i, uint64(len(m.Label)))
 		i += copy(data[i:], m.Label)
 	}
+	if len(m.Facets) > 0 {
+		for _, msg := range m.Facets {
+			data[i] = 0x3a
+			i++
+			i = encodeVarintTask(data, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(data[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
+	if len(m.Lang) > 0 {
+		data[i] = 0x42
+		i++
+		i = encodeVarintTask(data, i, uint64(len(m.Lang)))
+		i += copy(data[i:], m.Lang)
+	}
 	return i, nil
 }
 
@@ -908,6 +1186,23 @@ func (m *Mutations) MarshalTo(data []byte) (int, error) {
 			i += n
 		}
 	}
+	if m.StartTs != 0 {
+		data[i] = 0x20
+		i++
+		i = encodeVarintTask(data, i, uint64(m.StartTs))
+	}
+	if len(m.Lookups) > 0 {
+		for _, msg := range m.Lookups {
+			data[i] = 0x2a
+			i++
+			i = encodeVarintTask(data, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(data[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
 	return i, nil
 }
 
@@ -951,6 +1246,32 @@ func (m *Proposal) MarshalTo(data []byte) (int, error) {
 		}
 		i += n6
 	}
+	if m.Txn != nil {
+		data[i] = 0x22
+		i++
+		i = encodeVarintTask(data, i, uint64(m.Txn.Size()))
+		n8, err := m.Txn.MarshalTo(data[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n8
+	}
+	if m.Lease != nil {
+		data[i] = 0x2a
+		i++
+		i = encodeVarintTask(data, i, uint64(m.Lease.Size()))
+		n9, err := m.Lease.MarshalTo(data[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n9
+	}
+	if len(m.RebuildIndex) > 0 {
+		data[i] = 0x32
+		i++
+		i = encodeVarintTask(data, i, uint64(len(m.RebuildIndex)))
+		i += copy(data[i:], m.RebuildIndex)
+	}
 	return i, nil
 }
 
@@ -1049,69 +1370,312 @@ func (m *GroupKeys) MarshalTo(data []byte) (int, error) {
 	return i, nil
 }
 
-func encodeFixed64Task(data []byte, offset int, v uint64) int {
-	data[offset] = uint8(v)
-	data[offset+1] = uint8(v >> 8)
-	data[offset+2] = uint8(v >> 16)
-	data[offset+3] = uint8(v >> 24)
-	data[offset+4] = uint8(v >> 32)
-	data[offset+5] = uint8(v >> 40)
-	data[offset+6] = uint8(v >> 48)
-	data[offset+7] = uint8(v >> 56)
-	return offset + 8
-}
-func encodeFixed32Task(data []byte, offset int, v uint32) int {
-	data[offset] = uint8(v)
-	data[offset+1] = uint8(v >> 8)
-	data[offset+2] = uint8(v >> 16)
-	data[offset+3] = uint8(v >> 24)
-	return offset + 4
-}
-func encodeVarintTask(data []byte, offset int, v uint64) int {
-	for v >= 1<<7 {
-		data[offset] = uint8(v&0x7f | 0x80)
-		v >>= 7
-		offset++
-	}
-	data[offset] = uint8(v)
-	return offset + 1
-}
-func (m *List) Size() (n int) {
-	var l int
-	_ = l
-	if len(m.Uids) > 0 {
-		n += 1 + sovTask(uint64(len(m.Uids)*8)) + len(m.Uids)*8
+func (m *Facet) Marshal() (data []byte, err error) {
+	size := m.Size()
+	data = make([]byte, size)
+	n, err := m.MarshalTo(data)
+	if err != nil {
+		return nil, err
 	}
-	return n
+	return data[:n], nil
 }
 
-func (m *Value) Size() (n int) {
+func (m *Facet) MarshalTo(data []byte) (int, error) {
+	var i int
+	_ = i
 	var l int
 	_ = l
-	l = len(m.Val)
-	if l > 0 {
-		n += 1 + l + sovTask(uint64(l))
+	if len(m.Key) > 0 {
+		data[i] = 0xa
+		i++
+		i = encodeVarintTask(data, i, uint64(len(m.Key)))
+		i += copy(data[i:], m.Key)
+	}
+	if len(m.Value) > 0 {
+		data[i] = 0x12
+		i++
+		i = encodeVarintTask(data, i, uint64(len(m.Value)))
+		i += copy(data[i:], m.Value)
 	}
 	if m.ValType != 0 {
-		n += 1 + sovTask(uint64(m.ValType))
+		data[i] = 0x18
+		i++
+		i = encodeVarintTask(data, i, uint64(m.ValType))
 	}
-	return n
+	return i, nil
 }
 
-func (m *Query) Size() (n int) {
+func (m *Facets) Marshal() (data []byte, err error) {
+	size := m.Size()
+	data = make([]byte, size)
+	n, err := m.MarshalTo(data)
+	if err != nil {
+		return nil, err
+	}
+	return data[:n], nil
+}
+
+func (m *Facets) MarshalTo(data []byte) (int, error) {
+	var i int
+	_ = i
 	var l int
 	_ = l
-	l = len(m.Attr)
-	if l > 0 {
-		n += 1 + l + sovTask(uint64(l))
-	}
-	if m.Count != 0 {
-		n += 1 + sovTask(uint64(m.Count))
-	}
-	if m.Offset != 0 {
-		n += 1 + sovTask(uint64(m.Offset))
-	}
-	if m.AfterUid != 0 {
+	if len(m.Facets) > 0 {
+		for _, msg := range m.Facets {
+			data[i] = 0xa
+			i++
+			i = encodeVarintTask(data, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(data[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
+	return i, nil
+}
+
+func (m *FacetsList) Marshal() (data []byte, err error) {
+	size := m.Size()
+	data = make([]byte, size)
+	n, err := m.MarshalTo(data)
+	if err != nil {
+		return nil, err
+	}
+	return data[:n], nil
+}
+
+func (m *FacetsList) MarshalTo(data []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.FacetsList) > 0 {
+		for _, msg := range m.FacetsList {
+			data[i] = 0xa
+			i++
+			i = encodeVarintTask(data, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(data[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
+	return i, nil
+}
+
+func (m *FacetParams) Marshal() (data []byte, err error) {
+	size := m.Size()
+	data = make([]byte, size)
+	n, err := m.MarshalTo(data)
+	if err != nil {
+		return nil, err
+	}
+	return data[:n], nil
+}
+
+func (m *FacetParams) MarshalTo(data []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.AllKeys {
+		data[i] = 0x8
+		i++
+		if m.AllKeys {
+			data[i] = 1
+		} else {
+			data[i] = 0
+		}
+		i++
+	}
+	if len(m.Keys) > 0 {
+		for _, s := range m.Keys {
+			data[i] = 0x12
+			i++
+			l = len(s)
+			for l >= 1<<7 {
+				data[i] = uint8(uint64(l)&0x7f | 0x80)
+				l >>= 7
+				i++
+			}
+			data[i] = uint8(l)
+			i++
+			i += copy(data[i:], s)
+		}
+	}
+	return i, nil
+}
+
+func (m *Txn) Marshal() (data []byte, err error) {
+	size := m.Size()
+	data = make([]byte, size)
+	n, err := m.MarshalTo(data)
+	if err != nil {
+		return nil, err
+	}
+	return data[:n], nil
+}
+
+func (m *Txn) MarshalTo(data []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.GroupId != 0 {
+		data[i] = 0x8
+		i++
+		i = encodeVarintTask(data, i, uint64(m.GroupId))
+	}
+	if m.StartTs != 0 {
+		data[i] = 0x10
+		i++
+		i = encodeVarintTask(data, i, uint64(m.StartTs))
+	}
+	if m.CommitTs != 0 {
+		data[i] = 0x18
+		i++
+		i = encodeVarintTask(data, i, uint64(m.CommitTs))
+	}
+	if m.Primary {
+		data[i] = 0x20
+		i++
+		if m.Primary {
+			data[i] = 1
+		} else {
+			data[i] = 0
+		}
+		i++
+	}
+	return i, nil
+}
+
+func (m *Order) Marshal() (data []byte, err error) {
+	size := m.Size()
+	data = make([]byte, size)
+	n, err := m.MarshalTo(data)
+	if err != nil {
+		return nil, err
+	}
+	return data[:n], nil
+}
+
+func (m *Order) MarshalTo(data []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Attr) > 0 {
+		data[i] = 0xa
+		i++
+		i = encodeVarintTask(data, i, uint64(len(m.Attr)))
+		i += copy(data[i:], m.Attr)
+	}
+	if m.Desc {
+		data[i] = 0x10
+		i++
+		if m.Desc {
+			data[i] = 1
+		} else {
+			data[i] = 0
+		}
+		i++
+	}
+	return i, nil
+}
+
+func (m *Lease) Marshal() (data []byte, err error) {
+	size := m.Size()
+	data = make([]byte, size)
+	n, err := m.MarshalTo(data)
+	if err != nil {
+		return nil, err
+	}
+	return data[:n], nil
+}
+
+func (m *Lease) MarshalTo(data []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.From != 0 {
+		data[i] = 0x8
+		i++
+		i = encodeVarintTask(data, i, uint64(m.From))
+	}
+	if m.To != 0 {
+		data[i] = 0x10
+		i++
+		i = encodeVarintTask(data, i, uint64(m.To))
+	}
+	return i, nil
+}
+
+func encodeFixed64Task(data []byte, offset int, v uint64) int {
+	data[offset] = uint8(v)
+	data[offset+1] = uint8(v >> 8)
+	data[offset+2] = uint8(v >> 16)
+	data[offset+3] = uint8(v >> 24)
+	data[offset+4] = uint8(v >> 32)
+	data[offset+5] = uint8(v >> 40)
+	data[offset+6] = uint8(v >> 48)
+	data[offset+7] = uint8(v >> 56)
+	return offset + 8
+}
+func encodeFixed32Task(data []byte, offset int, v uint32) int {
+	data[offset] = uint8(v)
+	data[offset+1] = uint8(v >> 8)
+	data[offset+2] = uint8(v >> 16)
+	data[offset+3] = uint8(v >> 24)
+	return offset + 4
+}
+func encodeVarintTask(data []byte, offset int, v uint64) int {
+	for v >= 1<<7 {
+		data[offset] = uint8(v&0x7f | 0x80)
+		v >>= 7
+		offset++
+	}
+	data[offset] = uint8(v)
+	return offset + 1
+}
+func (m *List) Size() (n int) {
+	var l int
+	_ = l
+	if len(m.Uids) > 0 {
+		n += 1 + sovTask(uint64(len(m.Uids)*8)) + len(m.Uids)*8
+	}
+	return n
+}
+
+func (m *Value) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.Val)
+	if l > 0 {
+		n += 1 + l + sovTask(uint64(l))
+	}
+	if m.ValType != 0 {
+		n += 1 + sovTask(uint64(m.ValType))
+	}
+	return n
+}
+
+func (m *Query) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.Attr)
+	if l > 0 {
+		n += 1 + l + sovTask(uint64(l))
+	}
+	if m.Count != 0 {
+		n += 1 + sovTask(uint64(m.Count))
+	}
+	if m.Offset != 0 {
+		n += 1 + sovTask(uint64(m.Offset))
+	}
+	if m.AfterUid != 0 {
 		n += 9
 	}
 	if m.DoCount {
@@ -1126,6 +1690,16 @@ func (m *Query) Size() (n int) {
 			n += 1 + l + sovTask(uint64(l))
 		}
 	}
+	if m.FacetParam != nil {
+		l = m.FacetParam.Size()
+		n += 1 + l + sovTask(uint64(l))
+	}
+	if len(m.Langs) > 0 {
+		for _, s := range m.Langs {
+			l = len(s)
+			n += 1 + l + sovTask(uint64(l))
+		}
+	}
 	return n
 }
 
@@ -1154,6 +1728,21 @@ func (m *Result) Size() (n int) {
 	if m.IntersectDest {
 		n += 2
 	}
+	if len(m.Scores) > 0 {
+		n += 1 + sovTask(uint64(len(m.Scores)*8)) + len(m.Scores)*8
+	}
+	if len(m.FacetMatrix) > 0 {
+		for _, e := range m.FacetMatrix {
+			l = e.Size()
+			n += 1 + l + sovTask(uint64(l))
+		}
+	}
+	if len(m.Predicates) > 0 {
+		for _, s := range m.Predicates {
+			l = len(s)
+			n += 1 + l + sovTask(uint64(l))
+		}
+	}
 	return n
 }
 
@@ -1176,6 +1765,15 @@ func (m *Sort) Size() (n int) {
 	if m.Offset != 0 {
 		n += 1 + sovTask(uint64(m.Offset))
 	}
+	if m.Desc {
+		n += 2
+	}
+	if len(m.ThenBy) > 0 {
+		for _, e := range m.ThenBy {
+			l = e.Size()
+			n += 1 + l + sovTask(uint64(l))
+		}
+	}
 	return n
 }
 
@@ -1297,6 +1895,16 @@ func (m *DirectedEdge) Size() (n int) {
 	if l > 0 {
 		n += 1 + l + sovTask(uint64(l))
 	}
+	if len(m.Facets) > 0 {
+		for _, e := range m.Facets {
+			l = e.Size()
+			n += 1 + l + sovTask(uint64(l))
+		}
+	}
+	l = len(m.Lang)
+	if l > 0 {
+		n += 1 + l + sovTask(uint64(l))
+	}
 	return n
 }
 
@@ -1318,7 +1926,16 @@ func (m *Mutations) Size() (n int) {
 			n += 1 + l + sovTask(uint64(l))
 		}
 	}
-	return n
+	if m.StartTs != 0 {
+		n += 1 + sovTask(uint64(m.StartTs))
+	}
+	if len(m.Lookups) > 0 {
+		for _, e := range m.Lookups {
+			l = e.Size()
+			n += 1 + l + sovTask(uint64(l))
+		}
+	}
+	return n
 }
 
 func (m *Proposal) Size() (n int) {
@@ -1335,6 +1952,18 @@ func (m *Proposal) Size() (n int) {
 		l = m.Membership.Size()
 		n += 1 + l + sovTask(uint64(l))
 	}
+	if m.Txn != nil {
+		l = m.Txn.Size()
+		n += 1 + l + sovTask(uint64(l))
+	}
+	if m.Lease != nil {
+		l = m.Lease.Size()
+		n += 1 + l + sovTask(uint64(l))
+	}
+	l = len(m.RebuildIndex)
+	if l > 0 {
+		n += 1 + l + sovTask(uint64(l))
+	}
 	return n
 }
 
@@ -1381,6 +2010,105 @@ func (m *GroupKeys) Size() (n int) {
 	return n
 }
 
+func (m *Facet) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.Key)
+	if l > 0 {
+		n += 1 + l + sovTask(uint64(l))
+	}
+	l = len(m.Value)
+	if l > 0 {
+		n += 1 + l + sovTask(uint64(l))
+	}
+	if m.ValType != 0 {
+		n += 1 + sovTask(uint64(m.ValType))
+	}
+	return n
+}
+
+func (m *Facets) Size() (n int) {
+	var l int
+	_ = l
+	if len(m.Facets) > 0 {
+		for _, e := range m.Facets {
+			l = e.Size()
+			n += 1 + l + sovTask(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *FacetsList) Size() (n int) {
+	var l int
+	_ = l
+	if len(m.FacetsList) > 0 {
+		for _, e := range m.FacetsList {
+			l = e.Size()
+			n += 1 + l + sovTask(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *FacetParams) Size() (n int) {
+	var l int
+	_ = l
+	if m.AllKeys {
+		n += 2
+	}
+	if len(m.Keys) > 0 {
+		for _, s := range m.Keys {
+			l = len(s)
+			n += 1 + l + sovTask(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *Txn) Size() (n int) {
+	var l int
+	_ = l
+	if m.GroupId != 0 {
+		n += 1 + sovTask(uint64(m.GroupId))
+	}
+	if m.StartTs != 0 {
+		n += 1 + sovTask(uint64(m.StartTs))
+	}
+	if m.CommitTs != 0 {
+		n += 1 + sovTask(uint64(m.CommitTs))
+	}
+	if m.Primary {
+		n += 2
+	}
+	return n
+}
+
+func (m *Order) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.Attr)
+	if l > 0 {
+		n += 1 + l + sovTask(uint64(l))
+	}
+	if m.Desc {
+		n += 2
+	}
+	return n
+}
+
+func (m *Lease) Size() (n int) {
+	var l int
+	_ = l
+	if m.From != 0 {
+		n += 1 + sovTask(uint64(m.From))
+	}
+	if m.To != 0 {
+		n += 1 + sovTask(uint64(m.To))
+	}
+	return n
+}
+
 func sovTask(x uint64) (n int) {
 	for {
 		n++
@@ -1822,6 +2550,68 @@ func (m *Query) Unmarshal(data []byte) error {
 			}
 			m.SrcFunc = append(m.SrcFunc, string(data[iNdEx:postIndex]))
 			iNdEx = postIndex
+		case 8:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field FacetParam", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTask
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthTask
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.FacetParam == nil {
+				m.FacetParam = &FacetParams{}
+			}
+			if err := m.FacetParam.Unmarshal(data[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 9:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Langs", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTask
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTask
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Langs = append(m.Langs, string(data[iNdEx:postIndex]))
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipTask(data[iNdEx:])
@@ -2016,6 +2806,126 @@ func (m *Result) Unmarshal(data []byte) error {
 				}
 			}
 			m.IntersectDest = bool(v != 0)
+		case 5:
+			if wireType == 2 {
+				var packedLen int
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowTask
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := data[iNdEx]
+					iNdEx++
+					packedLen |= (int(b) & 0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				if packedLen < 0 {
+					return ErrInvalidLengthTask
+				}
+				postIndex := iNdEx + packedLen
+				if postIndex > l {
+					return io.ErrUnexpectedEOF
+				}
+				for iNdEx < postIndex {
+					var v uint64
+					if (iNdEx + 8) > l {
+						return io.ErrUnexpectedEOF
+					}
+					iNdEx += 8
+					v = uint64(data[iNdEx-8])
+					v |= uint64(data[iNdEx-7]) << 8
+					v |= uint64(data[iNdEx-6]) << 16
+					v |= uint64(data[iNdEx-5]) << 24
+					v |= uint64(data[iNdEx-4]) << 32
+					v |= uint64(data[iNdEx-3]) << 40
+					v |= uint64(data[iNdEx-2]) << 48
+					v |= uint64(data[iNdEx-1]) << 56
+					v2 := float64(math.Float64frombits(v))
+					m.Scores = append(m.Scores, v2)
+				}
+			} else if wireType == 1 {
+				var v uint64
+				if (iNdEx + 8) > l {
+					return io.ErrUnexpectedEOF
+				}
+				iNdEx += 8
+				v = uint64(data[iNdEx-8])
+				v |= uint64(data[iNdEx-7]) << 8
+				v |= uint64(data[iNdEx-6]) << 16
+				v |= uint64(data[iNdEx-5]) << 24
+				v |= uint64(data[iNdEx-4]) << 32
+				v |= uint64(data[iNdEx-3]) << 40
+				v |= uint64(data[iNdEx-2]) << 48
+				v |= uint64(data[iNdEx-1]) << 56
+				v2 := float64(math.Float64frombits(v))
+				m.Scores = append(m.Scores, v2)
+			} else {
+				return fmt.Errorf("proto: wrong wireType = %d for field Scores", wireType)
+			}
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field FacetMatrix", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTask
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthTask
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.FacetMatrix = append(m.FacetMatrix, &FacetsList{})
+			if err := m.FacetMatrix[len(m.FacetMatrix)-1].Unmarshal(data[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 7:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Predicates", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTask
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTask
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Predicates = append(m.Predicates, string(data[iNdEx:postIndex]))
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipTask(data[iNdEx:])
@@ -2164,9 +3074,60 @@ func (m *Sort) Unmarshal(data []byte) error {
 					break
 				}
 			}
-		default:
-			iNdEx = preIndex
-			skippy, err := skipTask(data[iNdEx:])
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Desc", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTask
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				v |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Desc = bool(v != 0)
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ThenBy", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTask
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthTask
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ThenBy = append(m.ThenBy, &Order{})
+			if err := m.ThenBy[len(m.ThenBy)-1].Unmarshal(data[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipTask(data[iNdEx:])
 			if err != nil {
 				return err
 			}
@@ -2649,7 +3610,908 @@ func (m *Membership) Unmarshal(data []byte) error {
 			if wireType != 0 {
 				return fmt.Errorf("proto: wrong wireType = %d for field AmDead", wireType)
 			}
-			var v int
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTask
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				v |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.AmDead = bool(v != 0)
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field LastUpdate", wireType)
+			}
+			m.LastUpdate = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTask
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				m.LastUpdate |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipTask(data[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthTask
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *MembershipUpdate) Unmarshal(data []byte) error {
+	l := len(data)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowTask
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := data[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: MembershipUpdate: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: MembershipUpdate: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Members", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTask
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthTask
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Members = append(m.Members, &Membership{})
+			if err := m.Members[len(m.Members)-1].Unmarshal(data[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field LastUpdate", wireType)
+			}
+			m.LastUpdate = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTask
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				m.LastUpdate |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Redirect", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTask
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				v |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Redirect = bool(v != 0)
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field RedirectAddr", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTask
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTask
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.RedirectAddr = string(data[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipTask(data[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthTask
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *DirectedEdge) Unmarshal(data []byte) error {
+	l := len(data)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowTask
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := data[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: DirectedEdge: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: DirectedEdge: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 1 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Entity", wireType)
+			}
+			m.Entity = 0
+			if (iNdEx + 8) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += 8
+			m.Entity = uint64(data[iNdEx-8])
+			m.Entity |= uint64(data[iNdEx-7]) << 8
+			m.Entity |= uint64(data[iNdEx-6]) << 16
+			m.Entity |= uint64(data[iNdEx-5]) << 24
+			m.Entity |= uint64(data[iNdEx-4]) << 32
+			m.Entity |= uint64(data[iNdEx-3]) << 40
+			m.Entity |= uint64(data[iNdEx-2]) << 48
+			m.Entity |= uint64(data[iNdEx-1]) << 56
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Attr", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTask
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTask
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Attr = string(data[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Value", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTask
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthTask
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Value = append(m.Value[:0], data[iNdEx:postIndex]...)
+			if m.Value == nil {
+				m.Value = []byte{}
+			}
+			iNdEx = postIndex
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ValueType", wireType)
+			}
+			m.ValueType = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTask
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				m.ValueType |= (uint32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 5:
+			if wireType != 1 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ValueId", wireType)
+			}
+			m.ValueId = 0
+			if (iNdEx + 8) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += 8
+			m.ValueId = uint64(data[iNdEx-8])
+			m.ValueId |= uint64(data[iNdEx-7]) << 8
+			m.ValueId |= uint64(data[iNdEx-6]) << 16
+			m.ValueId |= uint64(data[iNdEx-5]) << 24
+			m.ValueId |= uint64(data[iNdEx-4]) << 32
+			m.ValueId |= uint64(data[iNdEx-3]) << 40
+			m.ValueId |= uint64(data[iNdEx-2]) << 48
+			m.ValueId |= uint64(data[iNdEx-1]) << 56
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Label", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTask
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTask
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Label = string(data[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 7:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Facets", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTask
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthTask
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Facets = append(m.Facets, &Facet{})
+			if err := m.Facets[len(m.Facets)-1].Unmarshal(data[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 8:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Lang", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTask
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTask
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Lang = string(data[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipTask(data[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthTask
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Mutations) Unmarshal(data []byte) error {
+	l := len(data)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowTask
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := data[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Mutations: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Mutations: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field GroupId", wireType)
+			}
+			m.GroupId = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTask
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				m.GroupId |= (uint32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Set", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTask
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthTask
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Set = append(m.Set, &DirectedEdge{})
+			if err := m.Set[len(m.Set)-1].Unmarshal(data[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Del", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTask
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthTask
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Del = append(m.Del, &DirectedEdge{})
+			if err := m.Del[len(m.Del)-1].Unmarshal(data[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field StartTs", wireType)
+			}
+			m.StartTs = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTask
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				m.StartTs |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Lookups", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTask
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthTask
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Lookups = append(m.Lookups, &Query{})
+			if err := m.Lookups[len(m.Lookups)-1].Unmarshal(data[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipTask(data[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthTask
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Proposal) Unmarshal(data []byte) error {
+	l := len(data)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowTask
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := data[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Proposal: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Proposal: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Id", wireType)
+			}
+			m.Id = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTask
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				m.Id |= (uint32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Mutations", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTask
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthTask
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Mutations == nil {
+				m.Mutations = &Mutations{}
+			}
+			if err := m.Mutations.Unmarshal(data[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Membership", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTask
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthTask
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Membership == nil {
+				m.Membership = &Membership{}
+			}
+			if err := m.Membership.Unmarshal(data[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Txn", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTask
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthTask
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Txn == nil {
+				m.Txn = &Txn{}
+			}
+			if err := m.Txn.Unmarshal(data[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Lease", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTask
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthTask
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Lease == nil {
+				m.Lease = &Lease{}
+			}
+			if err := m.Lease.Unmarshal(data[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field RebuildIndex", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTask
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTask
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.RebuildIndex = string(data[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipTask(data[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthTask
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *KV) Unmarshal(data []byte) error {
+	l := len(data)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowTask
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := data[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: KV: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: KV: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Key", wireType)
+			}
+			var byteLen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTask
@@ -2659,17 +4521,28 @@ func (m *Membership) Unmarshal(data []byte) error {
 				}
 				b := data[iNdEx]
 				iNdEx++
-				v |= (int(b) & 0x7F) << shift
+				byteLen |= (int(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			m.AmDead = bool(v != 0)
-		case 6:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field LastUpdate", wireType)
+			if byteLen < 0 {
+				return ErrInvalidLengthTask
 			}
-			m.LastUpdate = 0
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Key = append(m.Key[:0], data[iNdEx:postIndex]...)
+			if m.Key == nil {
+				m.Key = []byte{}
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Val", wireType)
+			}
+			var byteLen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTask
@@ -2679,11 +4552,23 @@ func (m *Membership) Unmarshal(data []byte) error {
 				}
 				b := data[iNdEx]
 				iNdEx++
-				m.LastUpdate |= (uint64(b) & 0x7F) << shift
+				byteLen |= (int(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
+			if byteLen < 0 {
+				return ErrInvalidLengthTask
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Val = append(m.Val[:0], data[iNdEx:postIndex]...)
+			if m.Val == nil {
+				m.Val = []byte{}
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipTask(data[iNdEx:])
@@ -2705,7 +4590,7 @@ func (m *Membership) Unmarshal(data []byte) error {
 	}
 	return nil
 }
-func (m *MembershipUpdate) Unmarshal(data []byte) error {
+func (m *KC) Unmarshal(data []byte) error {
 	l := len(data)
 	iNdEx := 0
 	for iNdEx < l {
@@ -2728,17 +4613,17 @@ func (m *MembershipUpdate) Unmarshal(data []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: MembershipUpdate: wiretype end group for non-group")
+			return fmt.Errorf("proto: KC: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: MembershipUpdate: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: KC: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Members", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Key", wireType)
 			}
-			var msglen int
+			var byteLen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTask
@@ -2748,28 +4633,28 @@ func (m *MembershipUpdate) Unmarshal(data []byte) error {
 				}
 				b := data[iNdEx]
 				iNdEx++
-				msglen |= (int(b) & 0x7F) << shift
+				byteLen |= (int(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			if byteLen < 0 {
 				return ErrInvalidLengthTask
 			}
-			postIndex := iNdEx + msglen
+			postIndex := iNdEx + byteLen
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Members = append(m.Members, &Membership{})
-			if err := m.Members[len(m.Members)-1].Unmarshal(data[iNdEx:postIndex]); err != nil {
-				return err
+			m.Key = append(m.Key[:0], data[iNdEx:postIndex]...)
+			if m.Key == nil {
+				m.Key = []byte{}
 			}
 			iNdEx = postIndex
 		case 2:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field LastUpdate", wireType)
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Checksum", wireType)
 			}
-			m.LastUpdate = 0
+			var byteLen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTask
@@ -2779,16 +4664,78 @@ func (m *MembershipUpdate) Unmarshal(data []byte) error {
 				}
 				b := data[iNdEx]
 				iNdEx++
-				m.LastUpdate |= (uint64(b) & 0x7F) << shift
+				byteLen |= (int(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-		case 3:
+			if byteLen < 0 {
+				return ErrInvalidLengthTask
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Checksum = append(m.Checksum[:0], data[iNdEx:postIndex]...)
+			if m.Checksum == nil {
+				m.Checksum = []byte{}
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipTask(data[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthTask
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *GroupKeys) Unmarshal(data []byte) error {
+	l := len(data)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowTask
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := data[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: GroupKeys: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: GroupKeys: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Redirect", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field GroupId", wireType)
 			}
-			var v int
+			m.GroupId = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTask
@@ -2798,17 +4745,16 @@ func (m *MembershipUpdate) Unmarshal(data []byte) error {
 				}
 				b := data[iNdEx]
 				iNdEx++
-				v |= (int(b) & 0x7F) << shift
+				m.GroupId |= (uint64(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			m.Redirect = bool(v != 0)
-		case 4:
+		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field RedirectAddr", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Keys", wireType)
 			}
-			var stringLen uint64
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTask
@@ -2818,20 +4764,22 @@ func (m *MembershipUpdate) Unmarshal(data []byte) error {
 				}
 				b := data[iNdEx]
 				iNdEx++
-				stringLen |= (uint64(b) & 0x7F) << shift
+				msglen |= (int(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
+			if msglen < 0 {
 				return ErrInvalidLengthTask
 			}
-			postIndex := iNdEx + intStringLen
+			postIndex := iNdEx + msglen
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.RedirectAddr = string(data[iNdEx:postIndex])
+			m.Keys = append(m.Keys, &KC{})
+			if err := m.Keys[len(m.Keys)-1].Unmarshal(data[iNdEx:postIndex]); err != nil {
+				return err
+			}
 			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
@@ -2854,7 +4802,7 @@ func (m *MembershipUpdate) Unmarshal(data []byte) error {
 	}
 	return nil
 }
-func (m *DirectedEdge) Unmarshal(data []byte) error {
+func (m *Facet) Unmarshal(data []byte) error {
 	l := len(data)
 	iNdEx := 0
 	for iNdEx < l {
@@ -2877,32 +4825,15 @@ func (m *DirectedEdge) Unmarshal(data []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: DirectedEdge: wiretype end group for non-group")
+			return fmt.Errorf("proto: Facet: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: DirectedEdge: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: Facet: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
-			if wireType != 1 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Entity", wireType)
-			}
-			m.Entity = 0
-			if (iNdEx + 8) > l {
-				return io.ErrUnexpectedEOF
-			}
-			iNdEx += 8
-			m.Entity = uint64(data[iNdEx-8])
-			m.Entity |= uint64(data[iNdEx-7]) << 8
-			m.Entity |= uint64(data[iNdEx-6]) << 16
-			m.Entity |= uint64(data[iNdEx-5]) << 24
-			m.Entity |= uint64(data[iNdEx-4]) << 32
-			m.Entity |= uint64(data[iNdEx-3]) << 40
-			m.Entity |= uint64(data[iNdEx-2]) << 48
-			m.Entity |= uint64(data[iNdEx-1]) << 56
-		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Attr", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Key", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -2927,9 +4858,9 @@ func (m *DirectedEdge) Unmarshal(data []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Attr = string(data[iNdEx:postIndex])
+			m.Key = string(data[iNdEx:postIndex])
 			iNdEx = postIndex
-		case 3:
+		case 2:
 			if wireType != 2 {
 				return fmt.Errorf("proto: wrong wireType = %d for field Value", wireType)
 			}
@@ -2960,11 +4891,11 @@ func (m *DirectedEdge) Unmarshal(data []byte) error {
 				m.Value = []byte{}
 			}
 			iNdEx = postIndex
-		case 4:
+		case 3:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field ValueType", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field ValType", wireType)
 			}
-			m.ValueType = 0
+			m.ValType = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTask
@@ -2974,33 +4905,66 @@ func (m *DirectedEdge) Unmarshal(data []byte) error {
 				}
 				b := data[iNdEx]
 				iNdEx++
-				m.ValueType |= (uint32(b) & 0x7F) << shift
+				m.ValType |= (uint32(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-		case 5:
-			if wireType != 1 {
-				return fmt.Errorf("proto: wrong wireType = %d for field ValueId", wireType)
+		default:
+			iNdEx = preIndex
+			skippy, err := skipTask(data[iNdEx:])
+			if err != nil {
+				return err
 			}
-			m.ValueId = 0
-			if (iNdEx + 8) > l {
+			if skippy < 0 {
+				return ErrInvalidLengthTask
+			}
+			if (iNdEx + skippy) > l {
 				return io.ErrUnexpectedEOF
 			}
-			iNdEx += 8
-			m.ValueId = uint64(data[iNdEx-8])
-			m.ValueId |= uint64(data[iNdEx-7]) << 8
-			m.ValueId |= uint64(data[iNdEx-6]) << 16
-			m.ValueId |= uint64(data[iNdEx-5]) << 24
-			m.ValueId |= uint64(data[iNdEx-4]) << 32
-			m.ValueId |= uint64(data[iNdEx-3]) << 40
-			m.ValueId |= uint64(data[iNdEx-2]) << 48
-			m.ValueId |= uint64(data[iNdEx-1]) << 56
-		case 6:
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Facets) Unmarshal(data []byte) error {
+	l := len(data)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowTask
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := data[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Facets: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Facets: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Label", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Facets", wireType)
 			}
-			var stringLen uint64
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTask
@@ -3010,20 +4974,22 @@ func (m *DirectedEdge) Unmarshal(data []byte) error {
 				}
 				b := data[iNdEx]
 				iNdEx++
-				stringLen |= (uint64(b) & 0x7F) << shift
+				msglen |= (int(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
+			if msglen < 0 {
 				return ErrInvalidLengthTask
 			}
-			postIndex := iNdEx + intStringLen
+			postIndex := iNdEx + msglen
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Label = string(data[iNdEx:postIndex])
+			m.Facets = append(m.Facets, &Facet{})
+			if err := m.Facets[len(m.Facets)-1].Unmarshal(data[iNdEx:postIndex]); err != nil {
+				return err
+			}
 			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
@@ -3046,7 +5012,7 @@ func (m *DirectedEdge) Unmarshal(data []byte) error {
 	}
 	return nil
 }
-func (m *Mutations) Unmarshal(data []byte) error {
+func (m *FacetsList) Unmarshal(data []byte) error {
 	l := len(data)
 	iNdEx := 0
 	for iNdEx < l {
@@ -3069,65 +5035,15 @@ func (m *Mutations) Unmarshal(data []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: Mutations: wiretype end group for non-group")
+			return fmt.Errorf("proto: FacetsList: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: Mutations: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: FacetsList: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field GroupId", wireType)
-			}
-			m.GroupId = 0
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowTask
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := data[iNdEx]
-				iNdEx++
-				m.GroupId |= (uint32(b) & 0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-		case 2:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Set", wireType)
-			}
-			var msglen int
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowTask
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := data[iNdEx]
-				iNdEx++
-				msglen |= (int(b) & 0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-			if msglen < 0 {
-				return ErrInvalidLengthTask
-			}
-			postIndex := iNdEx + msglen
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			m.Set = append(m.Set, &DirectedEdge{})
-			if err := m.Set[len(m.Set)-1].Unmarshal(data[iNdEx:postIndex]); err != nil {
-				return err
-			}
-			iNdEx = postIndex
-		case 3:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Del", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field FacetsList", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -3151,8 +5067,8 @@ func (m *Mutations) Unmarshal(data []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Del = append(m.Del, &DirectedEdge{})
-			if err := m.Del[len(m.Del)-1].Unmarshal(data[iNdEx:postIndex]); err != nil {
+			m.FacetsList = append(m.FacetsList, &Facets{})
+			if err := m.FacetsList[len(m.FacetsList)-1].Unmarshal(data[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
@@ -3177,7 +5093,7 @@ func (m *Mutations) Unmarshal(data []byte) error {
 	}
 	return nil
 }
-func (m *Proposal) Unmarshal(data []byte) error {
+func (m *FacetParams) Unmarshal(data []byte) error {
 	l := len(data)
 	iNdEx := 0
 	for iNdEx < l {
@@ -3200,17 +5116,17 @@ func (m *Proposal) Unmarshal(data []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: Proposal: wiretype end group for non-group")
+			return fmt.Errorf("proto: FacetParams: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: Proposal: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: FacetParams: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Id", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field AllKeys", wireType)
 			}
-			m.Id = 0
+			var v int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTask
@@ -3220,49 +5136,17 @@ func (m *Proposal) Unmarshal(data []byte) error {
 				}
 				b := data[iNdEx]
 				iNdEx++
-				m.Id |= (uint32(b) & 0x7F) << shift
+				v |= (int(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
+			m.AllKeys = bool(v != 0)
 		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Mutations", wireType)
-			}
-			var msglen int
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowTask
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := data[iNdEx]
-				iNdEx++
-				msglen |= (int(b) & 0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-			if msglen < 0 {
-				return ErrInvalidLengthTask
-			}
-			postIndex := iNdEx + msglen
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			if m.Mutations == nil {
-				m.Mutations = &Mutations{}
-			}
-			if err := m.Mutations.Unmarshal(data[iNdEx:postIndex]); err != nil {
-				return err
-			}
-			iNdEx = postIndex
-		case 3:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Membership", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Keys", wireType)
 			}
-			var msglen int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTask
@@ -3272,24 +5156,20 @@ func (m *Proposal) Unmarshal(data []byte) error {
 				}
 				b := data[iNdEx]
 				iNdEx++
-				msglen |= (int(b) & 0x7F) << shift
+				stringLen |= (uint64(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthTask
 			}
-			postIndex := iNdEx + msglen
+			postIndex := iNdEx + intStringLen
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.Membership == nil {
-				m.Membership = &Membership{}
-			}
-			if err := m.Membership.Unmarshal(data[iNdEx:postIndex]); err != nil {
-				return err
-			}
+			m.Keys = append(m.Keys, string(data[iNdEx:postIndex]))
 			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
@@ -3312,7 +5192,7 @@ func (m *Proposal) Unmarshal(data []byte) error {
 	}
 	return nil
 }
-func (m *KV) Unmarshal(data []byte) error {
+func (m *Txn) Unmarshal(data []byte) error {
 	l := len(data)
 	iNdEx := 0
 	for iNdEx < l {
@@ -3335,17 +5215,17 @@ func (m *KV) Unmarshal(data []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: KV: wiretype end group for non-group")
+			return fmt.Errorf("proto: Txn: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: KV: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: Txn: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Key", wireType)
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field GroupId", wireType)
 			}
-			var byteLen int
+			m.GroupId = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTask
@@ -3355,28 +5235,16 @@ func (m *KV) Unmarshal(data []byte) error {
 				}
 				b := data[iNdEx]
 				iNdEx++
-				byteLen |= (int(b) & 0x7F) << shift
+				m.GroupId |= (uint32(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if byteLen < 0 {
-				return ErrInvalidLengthTask
-			}
-			postIndex := iNdEx + byteLen
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			m.Key = append(m.Key[:0], data[iNdEx:postIndex]...)
-			if m.Key == nil {
-				m.Key = []byte{}
-			}
-			iNdEx = postIndex
 		case 2:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Val", wireType)
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field StartTs", wireType)
 			}
-			var byteLen int
+			m.StartTs = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTask
@@ -3386,23 +5254,50 @@ func (m *KV) Unmarshal(data []byte) error {
 				}
 				b := data[iNdEx]
 				iNdEx++
-				byteLen |= (int(b) & 0x7F) << shift
+				m.StartTs |= (uint64(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if byteLen < 0 {
-				return ErrInvalidLengthTask
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CommitTs", wireType)
 			}
-			postIndex := iNdEx + byteLen
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
+			m.CommitTs = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTask
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				m.CommitTs |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
 			}
-			m.Val = append(m.Val[:0], data[iNdEx:postIndex]...)
-			if m.Val == nil {
-				m.Val = []byte{}
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Primary", wireType)
 			}
-			iNdEx = postIndex
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTask
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				v |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Primary = bool(v != 0)
 		default:
 			iNdEx = preIndex
 			skippy, err := skipTask(data[iNdEx:])
@@ -3424,7 +5319,7 @@ func (m *KV) Unmarshal(data []byte) error {
 	}
 	return nil
 }
-func (m *KC) Unmarshal(data []byte) error {
+func (m *Order) Unmarshal(data []byte) error {
 	l := len(data)
 	iNdEx := 0
 	for iNdEx < l {
@@ -3447,17 +5342,17 @@ func (m *KC) Unmarshal(data []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: KC: wiretype end group for non-group")
+			return fmt.Errorf("proto: Order: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: KC: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: Order: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Key", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Attr", wireType)
 			}
-			var byteLen int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTask
@@ -3467,28 +5362,26 @@ func (m *KC) Unmarshal(data []byte) error {
 				}
 				b := data[iNdEx]
 				iNdEx++
-				byteLen |= (int(b) & 0x7F) << shift
+				stringLen |= (uint64(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if byteLen < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthTask
 			}
-			postIndex := iNdEx + byteLen
+			postIndex := iNdEx + intStringLen
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Key = append(m.Key[:0], data[iNdEx:postIndex]...)
-			if m.Key == nil {
-				m.Key = []byte{}
-			}
+			m.Attr = string(data[iNdEx:postIndex])
 			iNdEx = postIndex
 		case 2:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Checksum", wireType)
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Desc", wireType)
 			}
-			var byteLen int
+			var v int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTask
@@ -3498,23 +5391,12 @@ func (m *KC) Unmarshal(data []byte) error {
 				}
 				b := data[iNdEx]
 				iNdEx++
-				byteLen |= (int(b) & 0x7F) << shift
+				v |= (int(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if byteLen < 0 {
-				return ErrInvalidLengthTask
-			}
-			postIndex := iNdEx + byteLen
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			m.Checksum = append(m.Checksum[:0], data[iNdEx:postIndex]...)
-			if m.Checksum == nil {
-				m.Checksum = []byte{}
-			}
-			iNdEx = postIndex
+			m.Desc = bool(v != 0)
 		default:
 			iNdEx = preIndex
 			skippy, err := skipTask(data[iNdEx:])
@@ -3536,7 +5418,7 @@ func (m *KC) Unmarshal(data []byte) error {
 	}
 	return nil
 }
-func (m *GroupKeys) Unmarshal(data []byte) error {
+func (m *Lease) Unmarshal(data []byte) error {
 	l := len(data)
 	iNdEx := 0
 	for iNdEx < l {
@@ -3559,17 +5441,17 @@ func (m *GroupKeys) Unmarshal(data []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: GroupKeys: wiretype end group for non-group")
+			return fmt.Errorf("proto: Lease: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: GroupKeys: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: Lease: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field GroupId", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field From", wireType)
 			}
-			m.GroupId = 0
+			m.From = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTask
@@ -3579,16 +5461,16 @@ func (m *GroupKeys) Unmarshal(data []byte) error {
 				}
 				b := data[iNdEx]
 				iNdEx++
-				m.GroupId |= (uint64(b) & 0x7F) << shift
+				m.From |= (uint64(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
 		case 2:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Keys", wireType)
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field To", wireType)
 			}
-			var msglen int
+			m.To = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTask
@@ -3598,23 +5480,11 @@ func (m *GroupKeys) Unmarshal(data []byte) error {
 				}
 				b := data[iNdEx]
 				iNdEx++
-				msglen |= (int(b) & 0x7F) << shift
+				m.To |= (uint64(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
-				return ErrInvalidLengthTask
-			}
-			postIndex := iNdEx + msglen
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			m.Keys = append(m.Keys, &KC{})
-			if err := m.Keys[len(m.Keys)-1].Unmarshal(data[iNdEx:postIndex]); err != nil {
-				return err
-			}
-			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipTask(data[iNdEx:])
@@ -3744,55 +5614,79 @@ var (
 func init() { proto.RegisterFile("task.proto", fileDescriptorTask) }
 
 var fileDescriptorTask = []byte{
-	// 796 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x9c, 0x95, 0xcd, 0x8e, 0xe3, 0x44,
-	0x10, 0xc7, 0xf1, 0x47, 0x1c, 0xbb, 0x32, 0x81, 0x51, 0x0b, 0x81, 0x19, 0x20, 0x58, 0xde, 0x3d,
-	0x18, 0x24, 0x56, 0x68, 0xf6, 0xc0, 0x79, 0x49, 0x60, 0xb5, 0x0a, 0x83, 0xa0, 0x61, 0xf7, 0x6a,
-	0xf5, 0xb8, 0x3b, 0xbb, 0x56, 0xec, 0x38, 0xea, 0x8f, 0xd1, 0x46, 0xbc, 0x07, 0xda, 0xe3, 0x3e,
-	0x02, 0x8f, 0xc1, 0x91, 0x47, 0x40, 0xc3, 0x8b, 0xa0, 0xae, 0xb6, 0x93, 0xcc, 0x12, 0x90, 0xe0,
-	0xd6, 0xff, 0xea, 0x72, 0x75, 0xd5, 0xaf, 0xaa, 0xdb, 0x00, 0x9a, 0xa9, 0xf5, 0x83, 0xad, 0xec,
-	0x74, 0x47, 0x42, 0xbb, 0xce, 0x2f, 0x20, 0xfc, 0xb6, 0x56, 0x9a, 0x10, 0x08, 0x4d, 0xcd, 0x55,
-	0xea, 0x65, 0x41, 0x11, 0x51, 0x5c, 0xe7, 0x0f, 0x61, 0xf4, 0x8c, 0x35, 0x46, 0x90, 0x73, 0x08,
-	0x6e, 0x58, 0x93, 0x7a, 0x99, 0x57, 0x9c, 0x51, 0xbb, 0x24, 0x29, 0x8c, 0x6f, 0x58, 0xf3, 0xd3,
-	0x6e, 0x2b, 0x52, 0x3f, 0xf3, 0x8a, 0x29, 0x1d, 0x64, 0xfe, 0xab, 0x07, 0xa3, 0x1f, 0x8c, 0x90,
-	0x3b, 0x1b, 0x92, 0x69, 0x2d, 0xf1, 0xb3, 0x84, 0xe2, 0x9a, 0xbc, 0x0b, 0xa3, 0xaa, 0x33, 0x1b,
-	0x8d, 0x5f, 0x8d, 0xa8, 0x13, 0xe4, 0x3d, 0x88, 0xba, 0xd5, 0x4a, 0x09, 0x9d, 0x06, 0x68, 0xee,
-	0x15, 0xf9, 0x10, 0x12, 0xb6, 0xd2, 0x42, 0x96, 0xa6, 0xe6, 0x69, 0x98, 0x79, 0x45, 0x44, 0x63,
-	0x34, 0x3c, 0xad, 0x39, 0xf9, 0x00, 0x62, 0xde, 0x95, 0x2e, 0xda, 0x28, 0xf3, 0x8a, 0x98, 0x8e,
-	0x79, 0x37, 0xc7, 0x78, 0x43, 0x31, 0xd1, 0xa1, 0x18, 0xeb, 0xae, 0x64, 0x55, 0xae, 0xcc, 0xa6,
-	0x4a, 0xc7, 0x59, 0x50, 0x24, 0x74, 0xac, 0x64, 0xf5, 0x8d, 0xd9, 0x54, 0xf9, 0x2f, 0x1e, 0x44,
-	0x54, 0x28, 0xd3, 0x68, 0xf2, 0x29, 0x80, 0xa9, 0x79, 0xd9, 0x32, 0x2d, 0xeb, 0x97, 0x08, 0x63,
-	0x72, 0x09, 0x0f, 0x90, 0x9a, 0xc5, 0x44, 0x13, 0x53, 0xf3, 0x2b, 0xdc, 0x24, 0xf7, 0x20, 0xba,
-	0xb1, 0x74, 0x54, 0xea, 0xa3, 0xdb, 0xc4, 0xb9, 0x21, 0x31, 0xda, 0x6f, 0xd9, 0xca, 0x30, 0x43,
-	0x95, 0x06, 0x59, 0x50, 0x4c, 0x69, 0xaf, 0xc8, 0x7d, 0x98, 0xd6, 0x1b, 0x2d, 0xa4, 0x12, 0x95,
-	0x5e, 0x08, 0xa5, 0xb1, 0xba, 0x98, 0xde, 0x35, 0xe6, 0x0a, 0xc2, 0x1f, 0x3b, 0xa9, 0x4f, 0x92,
-	0xbc, 0x9b, 0xa9, 0xff, 0x6f, 0x99, 0xee, 0xa1, 0x07, 0xa7, 0xa1, 0x87, 0xc7, 0xd0, 0xf3, 0x2f,
-	0x01, 0xec, 0xa1, 0xff, 0x19, 0x48, 0xfe, 0x08, 0x82, 0xef, 0x4c, 0x6b, 0x4f, 0x7b, 0x2e, 0x3b,
-	0xb3, 0xc5, 0x6c, 0xa7, 0xd4, 0x89, 0x61, 0x84, 0x6c, 0xdb, 0x03, 0x37, 0x42, 0x43, 0x93, 0x2c,
-	0x98, 0xb0, 0x9f, 0xb8, 0xc7, 0x30, 0xa1, 0x6c, 0xa5, 0xe7, 0xdd, 0x46, 0x8b, 0x97, 0x9a, 0xbc,
-	0x0d, 0x7e, 0xcd, 0x31, 0x4e, 0x44, 0xfd, 0x9a, 0x1f, 0x42, 0xfb, 0xc7, 0xa1, 0x2d, 0x1d, 0xce,
-	0x25, 0x56, 0x67, 0xe9, 0x70, 0x2e, 0xf3, 0x57, 0x1e, 0xc0, 0x95, 0x68, 0xaf, 0x85, 0x54, 0x2f,
-	0xea, 0xed, 0xff, 0x0f, 0x64, 0x29, 0x35, 0x82, 0x71, 0x21, 0xfb, 0x0e, 0xf5, 0x8a, 0xbc, 0x0f,
-	0x63, 0xd6, 0x96, 0x5c, 0x30, 0xde, 0x0f, 0x5f, 0xc4, 0xda, 0x85, 0x60, 0x9c, 0x7c, 0x02, 0x93,
-	0x86, 0x29, 0x5d, 0x9a, 0x2d, 0x67, 0x5a, 0xa4, 0x51, 0xe6, 0x15, 0x21, 0x05, 0x6b, 0x7a, 0x8a,
-	0x96, 0xfc, 0xb5, 0x07, 0xe7, 0x87, 0xd4, 0x9c, 0x91, 0x7c, 0x06, 0xe3, 0xd6, 0xd9, 0x7a, 0xc6,
-	0xe7, 0x8e, 0xf1, 0xc1, 0x91, 0x0e, 0x0e, 0x6f, 0x9e, 0xe0, 0xbf, 0x79, 0x02, 0xb9, 0x80, 0x58,
-	0x0a, 0x5e, 0x4b, 0x51, 0xb9, 0x96, 0xc7, 0x74, 0xaf, 0xc9, 0x3d, 0x98, 0x0e, 0xeb, 0x12, 0x8b,
-	0x0d, 0xb1, 0xd8, 0xb3, 0xc1, 0xf8, 0xc8, 0xd2, 0x7b, 0xed, 0xc1, 0xd9, 0x02, 0xa5, 0xe0, 0x5f,
-	0xf3, 0xe7, 0xc2, 0x52, 0x10, 0x1b, 0x5d, 0xeb, 0x5d, 0xcf, 0xb0, 0x57, 0xfb, 0xc1, 0xf4, 0xef,
-	0x5e, 0x71, 0x1c, 0x7e, 0x3c, 0xfa, 0x8c, 0x3a, 0x41, 0x3e, 0x06, 0xc0, 0x45, 0xa9, 0xed, 0x9b,
-	0x11, 0x22, 0xf6, 0x04, 0x2d, 0xf6, 0xd5, 0xb0, 0xb7, 0xd3, 0x6d, 0xd7, 0x8e, 0x67, 0x84, 0x0f,
-	0x8a, 0x11, 0x4f, 0xb0, 0x57, 0x0d, 0xbb, 0x16, 0x0d, 0xa2, 0x4c, 0xa8, 0x13, 0xb9, 0x84, 0xe4,
-	0xca, 0x68, 0xa6, 0xeb, 0x6e, 0x83, 0x77, 0x1b, 0x3b, 0x58, 0xf6, 0x4d, 0x9e, 0xd2, 0x31, 0xea,
-	0x27, 0x9c, 0xdc, 0x87, 0xc0, 0x8e, 0xb8, 0xbb, 0x1f, 0xc4, 0x41, 0x3d, 0x2e, 0x8d, 0xda, 0x6d,
-	0xeb, 0xc5, 0x45, 0x83, 0xa3, 0xf8, 0x0f, 0x5e, 0x5c, 0x34, 0xf9, 0xcf, 0x10, 0x7f, 0x2f, 0xbb,
-	0x6d, 0xa7, 0x58, 0x73, 0x34, 0x51, 0x53, 0x9c, 0xa8, 0xcf, 0x21, 0x69, 0x87, 0x7c, 0x10, 0xc7,
-	0xe4, 0xf2, 0x9d, 0xbe, 0x85, 0x83, 0x99, 0x1e, 0x3c, 0xc8, 0x17, 0x00, 0xed, 0xbe, 0xb5, 0x48,
-	0xea, 0x54, 0xcb, 0x8f, 0x7c, 0xf2, 0x02, 0xfc, 0xe5, 0x33, 0x7b, 0x8d, 0xd6, 0x62, 0x37, 0xbc,
-	0xc4, 0x6b, 0xb1, 0x3b, 0xbe, 0x58, 0xee, 0x6d, 0xce, 0x2f, 0xc1, 0x5f, 0xce, 0x4f, 0x78, 0x5e,
-	0x40, 0x5c, 0xbd, 0x10, 0xd5, 0x5a, 0x99, 0xb6, 0x77, 0xdf, 0xeb, 0x7c, 0x01, 0xc9, 0x63, 0x4b,
-	0x6c, 0x29, 0x76, 0x7f, 0xc7, 0x19, 0x1e, 0x70, 0x7e, 0x04, 0xe1, 0x5a, 0xec, 0x86, 0x27, 0x2f,
-	0x76, 0x19, 0x2f, 0xe7, 0x14, 0xad, 0x5f, 0x9d, 0xff, 0x76, 0x3b, 0xf3, 0x7e, 0xbf, 0x9d, 0x79,
-	0x7f, 0xdc, 0xce, 0xbc, 0x57, 0x7f, 0xce, 0xde, 0xba, 0x8e, 0xf0, 0x5f, 0xf3, 0xf0, 0xaf, 0x00,
-	0x00, 0x00, 0xff, 0xff, 0x3d, 0xbe, 0x77, 0xa5, 0x79, 0x06, 0x00, 0x00,
+	// 1173 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x9c, 0x56, 0xcd, 0x8e, 0x1b, 0x45,
+	0x10, 0x66, 0x7e, 0x3c, 0xf6, 0x94, 0x6d, 0x58, 0x5a, 0x08, 0x86, 0x04, 0x16, 0x33, 0x09, 0x92,
+	0x01, 0x25, 0xa0, 0xcd, 0x81, 0x03, 0x5c, 0x92, 0x5d, 0x12, 0x85, 0x4d, 0x20, 0x34, 0x9b, 0x5c,
+	0x47, 0xbd, 0xd3, 0xed, 0x64, 0xe4, 0xf9, 0xb1, 0xba, 0x7b, 0x22, 0xfb, 0xcc, 0x4b, 0xe4, 0xc8,
+	0x0b, 0x70, 0xe3, 0x21, 0x38, 0x72, 0xe5, 0x16, 0x85, 0xb7, 0xe0, 0x84, 0xba, 0xba, 0xc7, 0x1e,
+	0x87, 0x4d, 0x24, 0xb8, 0x75, 0xfd, 0x4c, 0x77, 0xd5, 0x57, 0x5f, 0x55, 0x0d, 0x80, 0x66, 0x6a,
+	0x79, 0x7d, 0x25, 0x1b, 0xdd, 0x90, 0xd0, 0x9c, 0xd3, 0x4b, 0x10, 0xde, 0x2b, 0x94, 0x26, 0x04,
+	0xc2, 0xb6, 0xe0, 0x2a, 0xf1, 0x66, 0xc1, 0x3c, 0xa2, 0x78, 0x4e, 0x6f, 0xc0, 0xe0, 0x11, 0x2b,
+	0x5b, 0x41, 0x0e, 0x20, 0x78, 0xca, 0xca, 0xc4, 0x9b, 0x79, 0xf3, 0x09, 0x35, 0x47, 0x92, 0xc0,
+	0xf0, 0x29, 0x2b, 0xcf, 0x36, 0x2b, 0x91, 0xf8, 0x33, 0x6f, 0x3e, 0xa5, 0x9d, 0x98, 0xfe, 0xed,
+	0xc1, 0xe0, 0xc7, 0x56, 0xc8, 0x8d, 0xb9, 0x92, 0x69, 0x2d, 0xf1, 0xb3, 0x98, 0xe2, 0x99, 0xbc,
+	0x03, 0x83, 0xbc, 0x69, 0x6b, 0x8d, 0x5f, 0x0d, 0xa8, 0x15, 0xc8, 0xbb, 0x10, 0x35, 0x8b, 0x85,
+	0x12, 0x3a, 0x09, 0x50, 0xed, 0x24, 0x72, 0x19, 0x62, 0xb6, 0xd0, 0x42, 0x66, 0x6d, 0xc1, 0x93,
+	0x70, 0xe6, 0xcd, 0x23, 0x3a, 0x42, 0xc5, 0xc3, 0x82, 0x93, 0xf7, 0x61, 0xc4, 0x9b, 0xcc, 0xde,
+	0x36, 0x98, 0x79, 0xf3, 0x11, 0x1d, 0xf2, 0xe6, 0x18, 0xef, 0xeb, 0x92, 0x89, 0x76, 0xc9, 0x18,
+	0x77, 0x25, 0xf3, 0x6c, 0xd1, 0xd6, 0x79, 0x32, 0x9c, 0x05, 0xf3, 0x98, 0x0e, 0x95, 0xcc, 0x6f,
+	0xb7, 0x75, 0x4e, 0x8e, 0x60, 0xbc, 0x60, 0xb9, 0xd0, 0xd9, 0x8a, 0x49, 0x56, 0x25, 0xa3, 0x99,
+	0x37, 0x1f, 0x1f, 0xbd, 0x7d, 0x1d, 0xb1, 0xba, 0x6d, 0x0c, 0x0f, 0x8c, 0x5e, 0x51, 0x58, 0x6c,
+	0x05, 0x93, 0x48, 0xc9, 0xea, 0xc7, 0x2a, 0x89, 0xf1, 0x2e, 0x2b, 0xa4, 0x3f, 0xfb, 0x10, 0x51,
+	0xa1, 0xda, 0x52, 0x93, 0x4f, 0x01, 0xda, 0x82, 0x67, 0x15, 0xd3, 0xb2, 0x58, 0x23, 0xac, 0xe3,
+	0x23, 0xb0, 0x77, 0x1a, 0xc0, 0x69, 0xdc, 0x16, 0xfc, 0x3e, 0x1a, 0xc9, 0x15, 0x88, 0x9e, 0x1a,
+	0x9c, 0x55, 0xe2, 0xa3, 0xdb, 0xd8, 0xba, 0x21, 0xf6, 0xd4, 0x99, 0x0c, 0x46, 0x98, 0xab, 0x4a,
+	0x82, 0x59, 0x30, 0x9f, 0x52, 0x27, 0x91, 0xab, 0x30, 0x2d, 0x6a, 0x2d, 0xa4, 0x12, 0xb9, 0x3e,
+	0x11, 0x4a, 0x23, 0x4e, 0x23, 0xba, 0xaf, 0x34, 0x5f, 0xab, 0xbc, 0x91, 0x42, 0x25, 0x83, 0x59,
+	0x30, 0xf7, 0xa8, 0x93, 0xc8, 0x0d, 0x98, 0xd8, 0xd4, 0x5d, 0x9c, 0x11, 0x06, 0x70, 0xd0, 0xcb,
+	0x5d, 0x61, 0xb4, 0x16, 0x20, 0x17, 0xef, 0x21, 0xc0, 0x4a, 0x0a, 0x5e, 0xe4, 0x4c, 0x0b, 0xe5,
+	0xc0, 0xec, 0x69, 0xd2, 0x5f, 0x3d, 0x08, 0x7f, 0x6a, 0xa4, 0xbe, 0x90, 0x01, 0xfb, 0xb8, 0xf8,
+	0xaf, 0xc3, 0x65, 0x4b, 0x96, 0xe0, 0x62, 0xb2, 0x84, 0x7b, 0x64, 0x21, 0x10, 0x72, 0xa1, 0x72,
+	0xc7, 0x05, 0x3c, 0x93, 0xab, 0x30, 0xd4, 0x4f, 0x44, 0x9d, 0x9d, 0x6f, 0x92, 0xa8, 0x0f, 0xed,
+	0x0f, 0x92, 0x0b, 0x49, 0x23, 0x63, 0xbb, 0xb5, 0x49, 0xbf, 0x02, 0x30, 0xe1, 0xfe, 0xe7, 0xc2,
+	0xa5, 0x37, 0x21, 0xf8, 0xbe, 0x45, 0x2e, 0x3c, 0x96, 0x4d, 0xbb, 0xc2, 0x3c, 0xa7, 0xd4, 0x0a,
+	0x5d, 0xd3, 0x18, 0xa2, 0x07, 0xb6, 0x69, 0x3a, 0x5a, 0x9a, 0x02, 0x86, 0xae, 0xc7, 0xee, 0xc0,
+	0x98, 0xb2, 0x85, 0x3e, 0x6e, 0x6a, 0x2d, 0xd6, 0x9a, 0xbc, 0x09, 0x7e, 0xc1, 0xf1, 0x9e, 0x88,
+	0xfa, 0x05, 0xdf, 0x5d, 0xed, 0xf7, 0xaf, 0x36, 0xb8, 0x72, 0x2e, 0x93, 0xc0, 0xe1, 0xca, 0xb9,
+	0x4c, 0x9f, 0x79, 0x00, 0xf7, 0x45, 0x75, 0x2e, 0xa4, 0x7a, 0x52, 0xac, 0xfe, 0xff, 0x45, 0x06,
+	0xdf, 0x52, 0x30, 0x2e, 0xa4, 0x63, 0x92, 0x93, 0xc8, 0x7b, 0x30, 0x64, 0x55, 0xc6, 0x05, 0xe3,
+	0x0e, 0xe2, 0x88, 0x55, 0x27, 0x82, 0x71, 0xf2, 0x11, 0x8c, 0x4b, 0xa6, 0x74, 0xd6, 0xae, 0x38,
+	0xd3, 0x22, 0x89, 0x66, 0xde, 0x3c, 0xa4, 0x60, 0x54, 0x0f, 0x51, 0x93, 0xfe, 0xe2, 0xc1, 0xc1,
+	0x2e, 0x34, 0xab, 0x24, 0x9f, 0xc1, 0xb0, 0xb2, 0xba, 0xc4, 0xeb, 0x93, 0x6e, 0xe7, 0x48, 0x3b,
+	0x87, 0x97, 0x5f, 0xf0, 0x5f, 0x7e, 0x81, 0x5c, 0x82, 0x91, 0xa1, 0x9f, 0x14, 0xb9, 0x25, 0xcb,
+	0x88, 0x6e, 0x65, 0x72, 0x05, 0xa6, 0xdd, 0x39, 0xc3, 0x64, 0x43, 0x4c, 0x76, 0xd2, 0x29, 0x6f,
+	0x1a, 0xf4, 0xfe, 0xf4, 0x60, 0x72, 0x82, 0xa2, 0xe0, 0xdf, 0xf2, 0xc7, 0xc2, 0xa0, 0x20, 0x6a,
+	0x5d, 0xe8, 0x8d, 0xc3, 0xd0, 0x49, 0x5b, 0x4a, 0xfb, 0xfb, 0x43, 0x0d, 0x9b, 0x14, 0x9f, 0x9e,
+	0x50, 0x2b, 0x90, 0x0f, 0x01, 0xf0, 0x90, 0x69, 0x33, 0x25, 0x43, 0x84, 0x3d, 0x46, 0x8d, 0x99,
+	0x93, 0x66, 0x1e, 0x59, 0x73, 0x61, 0xf1, 0x8c, 0x70, 0x84, 0xb6, 0xe2, 0x2e, 0xb7, 0xb3, 0xe5,
+	0x5c, 0x94, 0x08, 0x25, 0xce, 0x96, 0x73, 0x51, 0x9a, 0x29, 0x81, 0x4d, 0x68, 0x3b, 0x6e, 0x4b,
+	0x65, 0x6c, 0x52, 0xea, 0x4c, 0x26, 0x3c, 0x33, 0x89, 0x70, 0x86, 0xc5, 0x14, 0xcf, 0xe9, 0x6f,
+	0x1e, 0xc4, 0xf7, 0x5b, 0xcd, 0x74, 0xd1, 0xd4, 0x38, 0x07, 0xb1, 0xf6, 0x99, 0xa3, 0xc7, 0x94,
+	0x0e, 0x51, 0xbe, 0xcb, 0xc9, 0x55, 0x08, 0x4c, 0x5b, 0xd9, 0x9e, 0x24, 0xf6, 0xfa, 0x3e, 0x28,
+	0xd4, 0x98, 0x8d, 0x17, 0x17, 0x65, 0x12, 0xbc, 0xda, 0x8b, 0x8b, 0x12, 0xc7, 0xad, 0x66, 0x52,
+	0x67, 0x5a, 0x61, 0xee, 0x21, 0x1d, 0xa2, 0x7c, 0xa6, 0xc8, 0x27, 0x30, 0x2c, 0x9b, 0x66, 0xd9,
+	0xae, 0xec, 0x30, 0xda, 0x66, 0x82, 0x5b, 0x83, 0x76, 0xb6, 0xf4, 0xb9, 0x07, 0xa3, 0x07, 0xb2,
+	0x59, 0x35, 0x8a, 0x95, 0x3d, 0x3a, 0x4f, 0x91, 0xce, 0xd7, 0x20, 0xae, 0xba, 0x94, 0xb0, 0x16,
+	0xe3, 0xa3, 0xb7, 0x1c, 0x7f, 0x3a, 0x35, 0xdd, 0x79, 0x90, 0x2f, 0x01, 0xaa, 0x2d, 0xaf, 0xb0,
+	0x4c, 0x17, 0xf1, 0xad, 0xe7, 0x43, 0x2e, 0x43, 0xa0, 0xd7, 0x35, 0x86, 0x3e, 0x3e, 0x8a, 0xad,
+	0xeb, 0xd9, 0xba, 0xa6, 0x46, 0x4b, 0x3e, 0x86, 0x41, 0x29, 0x98, 0x12, 0x58, 0xb8, 0x6d, 0xfc,
+	0xf7, 0x8c, 0x8a, 0x5a, 0x8b, 0x65, 0xdd, 0x79, 0x5b, 0x94, 0x3c, 0x2b, 0x6a, 0x2e, 0xd6, 0xae,
+	0x96, 0x13, 0xa7, 0xbc, 0x6b, 0x74, 0xe9, 0x1c, 0xfc, 0xd3, 0x47, 0x66, 0x50, 0x2c, 0xc5, 0xa6,
+	0xdb, 0xae, 0x4b, 0xb1, 0xe9, 0x8f, 0x0e, 0xbb, 0x6f, 0xd3, 0x23, 0xf0, 0x4f, 0x8f, 0x2f, 0xf0,
+	0xbc, 0x04, 0xa3, 0xfc, 0x89, 0xc8, 0x97, 0xaa, 0xad, 0x9c, 0xfb, 0x56, 0x4e, 0x4f, 0x20, 0xbe,
+	0x63, 0x2a, 0x7b, 0x2a, 0x36, 0xff, 0x2e, 0x7b, 0xb8, 0x2b, 0xfb, 0x07, 0x10, 0x2e, 0xc5, 0xa6,
+	0x5b, 0x3e, 0x23, 0x9b, 0xcc, 0xe9, 0x31, 0x45, 0x6d, 0xfa, 0x1d, 0x0c, 0x90, 0x62, 0xfd, 0xc7,
+	0x63, 0xfb, 0xf8, 0x96, 0xf7, 0x7e, 0x9f, 0xf7, 0x96, 0xd8, 0x96, 0xf5, 0xc1, 0xfe, 0xbf, 0xc1,
+	0x35, 0x88, 0xec, 0x4e, 0xe9, 0x91, 0xd9, 0x7b, 0x25, 0x99, 0xd3, 0xaf, 0x01, 0x76, 0x2b, 0x88,
+	0x5c, 0x73, 0x5b, 0x5a, 0x65, 0x65, 0xa1, 0xb4, 0xfb, 0x6e, 0xd2, 0xfb, 0xae, 0x5b, 0xd0, 0xe8,
+	0x9e, 0x7e, 0x03, 0xe3, 0xde, 0xee, 0x36, 0x51, 0xb1, 0xb2, 0xcc, 0x30, 0x51, 0xcf, 0xfe, 0x2d,
+	0xb0, 0xb2, 0x44, 0x68, 0x48, 0x2f, 0xff, 0xd8, 0x65, 0x2d, 0x21, 0x38, 0x5b, 0xd7, 0xaf, 0x6b,
+	0x96, 0x3e, 0xc1, 0xfd, 0x7d, 0x82, 0x5f, 0x86, 0x38, 0x6f, 0xaa, 0xaa, 0x40, 0x5b, 0x80, 0xb6,
+	0x91, 0x55, 0x9c, 0x29, 0xf3, 0xe7, 0xb4, 0x92, 0x45, 0xc5, 0xe4, 0xc6, 0xcd, 0xd7, 0x4e, 0x4c,
+	0xbf, 0x80, 0x01, 0xee, 0xa5, 0x0b, 0xd7, 0x66, 0xb7, 0xdd, 0xfc, 0xdd, 0x76, 0x4b, 0x3f, 0x87,
+	0x01, 0x72, 0xce, 0x18, 0x17, 0xb2, 0xa9, 0x5c, 0x61, 0xf1, 0x6c, 0x3a, 0x46, 0x37, 0x2e, 0x32,
+	0x5f, 0x37, 0xb7, 0x0e, 0x7e, 0x7f, 0x71, 0xe8, 0xfd, 0xf1, 0xe2, 0xd0, 0x7b, 0xfe, 0xe2, 0xd0,
+	0x7b, 0xf6, 0xd7, 0xe1, 0x1b, 0xe7, 0x11, 0xfe, 0x07, 0xde, 0xf8, 0x67, 0x00, 0xc1, 0x2a, 0x6d,
+	0x7f, 0x15, 0x0a, 0x00, 0x00,
 }
@@ -0,0 +1,79 @@
+/*
+ * Copyright 2016 Dgraph Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * 		http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tok
+
+import (
+	"strings"
+
+	"github.com/dgraph-io/dgraph/x"
+)
+
+// DefaultLanguage is the language used for full-text search when none is given.
+const DefaultLanguage = "en"
+
+// language holds what is needed to turn the words of some language into
+// full-text search terms.
+type language struct {
+	stem      func(word string) string
+	stopWords map[string]bool
+}
+
+var languages = map[string]*language{
+	"en": {
+		stem: stemEnglish,
+		stopWords: stopWordSet("a", "an", "and", "are", "as", "at", "be", "but",
+			"by", "for", "if", "in", "into", "is", "it", "no", "not", "of", "on",
+			"or", "such", "that", "the", "their", "then", "there", "these",
+			"they", "this", "to", "was", "will", "with"),
+	},
+}
+
+func stopWordSet(words ...string) map[string]bool {
+	m := make(map[string]bool, len(words))
+	for _, w := range words {
+		m[w] = true
+	}
+	return m
+}
+
+// FullTextTokens returns the terms of s used for full-text search in the given
+// language. The words of s are tokenized, the stop words are dropped and the
+// rest are stemmed. A term is repeated as many times as it occurs in s.
+func FullTextTokens(lang string, s string) ([]string, error) {
+	l, ok := languages[lang]
+	if !ok {
+		// Language tags like en-GB fall back to the language itself.
+		l, ok = languages[strings.ToLower(strings.SplitN(lang, "-", 2)[0])]
+	}
+	if !ok {
+		return nil, x.Errorf("Full-text search is not supported for language: %s", lang)
+	}
+	tokenizer, err := NewTokenizer([]byte(s))
+	if err != nil {
+		return nil, err
+	}
+	defer tokenizer.Destroy()
+
+	var terms []string
+	for _, word := range tokenizer.Tokens() {
+		if l.stopWords[word] {
+			continue
+		}
+		terms = append(terms, l.stem(word))
+	}
+	return terms, nil
+}
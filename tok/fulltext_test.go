@@ -0,0 +1,66 @@
+/*
+ * Copyright 2016 Dgraph Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * 		http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tok
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStemEnglish(t *testing.T) {
+	testData := []struct {
+		in, expected string
+	}{
+		{"caresses", "caress"},
+		{"ponies", "poni"},
+		{"cats", "cat"},
+		{"feed", "feed"},
+		{"agreed", "agre"},
+		{"plastered", "plaster"},
+		{"motoring", "motor"},
+		{"sing", "sing"},
+		{"conflated", "conflat"},
+		{"hopping", "hop"},
+		{"falling", "fall"},
+		{"filing", "file"},
+		{"happy", "happi"},
+		{"relational", "relat"},
+		{"generalization", "gener"},
+		{"adjustment", "adjust"},
+		{"running", "run"},
+		{"shoes", "shoe"},
+		{"is", "is"},
+		{"cafés", "cafés"},
+	}
+	for _, d := range testData {
+		require.Equal(t, d.expected, stemEnglish(d.in), d.in)
+	}
+}
+
+func TestFullTextTokens(t *testing.T) {
+	terms, err := FullTextTokens("en", "The Running of the Shoes, and more shoes!")
+	require.NoError(t, err)
+	require.Equal(t, []string{"run", "shoe", "more", "shoe"}, terms)
+
+	terms, err = FullTextTokens("en-GB", "shoes")
+	require.NoError(t, err)
+	require.Equal(t, []string{"shoe"}, terms)
+
+	_, err = FullTextTokens("xx", "shoes")
+	require.Error(t, err)
+}
@@ -0,0 +1,312 @@
+/*
+ * Copyright 2016 Dgraph Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * 		http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tok
+
+// This is the Porter stemming algorithm for English, as described in
+// http://tartarus.org/martin/PorterStemmer/def.txt. It follows the structure
+// of the reference C implementation, with b[0..k] holding the word being
+// stemmed, and b[0..j] the stem left after removing a suffix.
+
+type porter struct {
+	b    []byte
+	k, j int
+}
+
+// cons returns true if b[i] is a consonant.
+func (p *porter) cons(i int) bool {
+	switch p.b[i] {
+	case 'a', 'e', 'i', 'o', 'u':
+		return false
+	case 'y':
+		if i == 0 {
+			return true
+		}
+		return !p.cons(i - 1)
+	}
+	return true
+}
+
+// m measures the number of consonant sequences in b[0..j]. With c a consonant
+// sequence and v a vowel sequence, b[0..j] looks like [c](vc){m}[v].
+func (p *porter) m() int {
+	n, i := 0, 0
+	for {
+		if i > p.j {
+			return n
+		}
+		if !p.cons(i) {
+			break
+		}
+		i++
+	}
+	i++
+	for {
+		for {
+			if i > p.j {
+				return n
+			}
+			if p.cons(i) {
+				break
+			}
+			i++
+		}
+		i++
+		n++
+		for {
+			if i > p.j {
+				return n
+			}
+			if !p.cons(i) {
+				break
+			}
+			i++
+		}
+		i++
+	}
+}
+
+// vowelInStem returns true if b[0..j] contains a vowel.
+func (p *porter) vowelInStem() bool {
+	for i := 0; i <= p.j; i++ {
+		if !p.cons(i) {
+			return true
+		}
+	}
+	return false
+}
+
+// doublec returns true if b[i-1..i] is a double consonant.
+func (p *porter) doublec(i int) bool {
+	if i < 1 || p.b[i] != p.b[i-1] {
+		return false
+	}
+	return p.cons(i)
+}
+
+// cvc returns true if b[i-2..i] is consonant, vowel, consonant and the last
+// consonant is not w, x or y. This is used to restore an e at the end of short
+// words, like cav(e), lov(e), hop(e).
+func (p *porter) cvc(i int) bool {
+	if i < 2 || !p.cons(i) || p.cons(i-1) || !p.cons(i-2) {
+		return false
+	}
+	switch p.b[i] {
+	case 'w', 'x', 'y':
+		return false
+	}
+	return true
+}
+
+// ends returns true if b[0..k] ends with s, and sets j to the end of the stem.
+func (p *porter) ends(s string) bool {
+	l := len(s)
+	if l > p.k+1 || string(p.b[p.k-l+1:p.k+1]) != s {
+		return false
+	}
+	p.j = p.k - l
+	return true
+}
+
+// setTo replaces b[j+1..k] with s.
+func (p *porter) setTo(s string) {
+	p.b = append(p.b[:p.j+1], s...)
+	p.k = p.j + len(s)
+}
+
+// replace replaces the suffix with s if the stem has at least one consonant
+// sequence.
+func (p *porter) replace(s string) {
+	if p.m() > 0 {
+		p.setTo(s)
+	}
+}
+
+// replaceFirst replaces the first suffix of the given pairs which b[0..k]
+// ends with by its replacement.
+func (p *porter) replaceFirst(pairs [][2]string) {
+	for _, pair := range pairs {
+		if p.ends(pair[0]) {
+			p.replace(pair[1])
+			return
+		}
+	}
+}
+
+// step1ab gets rid of plurals and -ed or -ing suffixes.
+func (p *porter) step1ab() {
+	if p.b[p.k] == 's' {
+		if p.ends("sses") {
+			p.k -= 2
+		} else if p.ends("ies") {
+			p.setTo("i")
+		} else if p.b[p.k-1] != 's' {
+			p.k--
+		}
+	}
+	if p.ends("eed") {
+		if p.m() > 0 {
+			p.k--
+		}
+	} else if (p.ends("ed") || p.ends("ing")) && p.vowelInStem() {
+		p.k = p.j
+		if p.ends("at") {
+			p.setTo("ate")
+		} else if p.ends("bl") {
+			p.setTo("ble")
+		} else if p.ends("iz") {
+			p.setTo("ize")
+		} else if p.doublec(p.k) {
+			switch p.b[p.k] {
+			case 'l', 's', 'z':
+			default:
+				p.k--
+			}
+		} else if p.m() == 1 && p.cvc(p.k) {
+			p.setTo("e")
+		}
+	}
+}
+
+// step1c turns a terminal y into i when there is another vowel in the stem.
+func (p *porter) step1c() {
+	if p.ends("y") && p.vowelInStem() {
+		p.b[p.k] = 'i'
+	}
+}
+
+// step2 maps double suffixes to single ones, like -ization to -ize.
+func (p *porter) step2() {
+	switch p.b[p.k-1] {
+	case 'a':
+		p.replaceFirst([][2]string{{"ational", "ate"}, {"tional", "tion"}})
+	case 'c':
+		p.replaceFirst([][2]string{{"enci", "ence"}, {"anci", "ance"}})
+	case 'e':
+		p.replaceFirst([][2]string{{"izer", "ize"}})
+	case 'l':
+		p.replaceFirst([][2]string{{"bli", "ble"}, {"alli", "al"}, {"entli", "ent"},
+			{"eli", "e"}, {"ousli", "ous"}})
+	case 'o':
+		p.replaceFirst([][2]string{{"ization", "ize"}, {"ation", "ate"}, {"ator", "ate"}})
+	case 's':
+		p.replaceFirst([][2]string{{"alism", "al"}, {"iveness", "ive"}, {"fulness", "ful"},
+			{"ousness", "ous"}})
+	case 't':
+		p.replaceFirst([][2]string{{"aliti", "al"}, {"iviti", "ive"}, {"biliti", "ble"}})
+	case 'g':
+		p.replaceFirst([][2]string{{"logi", "log"}})
+	}
+}
+
+// step3 deals with -ic-, -full, -ness etc.
+func (p *porter) step3() {
+	switch p.b[p.k] {
+	case 'e':
+		p.replaceFirst([][2]string{{"icate", "ic"}, {"ative", ""}, {"alize", "al"}})
+	case 'i':
+		p.replaceFirst([][2]string{{"iciti", "ic"}})
+	case 'l':
+		p.replaceFirst([][2]string{{"ical", "ic"}, {"ful", ""}})
+	case 's':
+		p.replaceFirst([][2]string{{"ness", ""}})
+	}
+}
+
+// step4 takes off -ant, -ence etc. when the stem has more than one consonant
+// sequence.
+func (p *porter) step4() {
+	var suffixes []string
+	switch p.b[p.k-1] {
+	case 'a':
+		suffixes = []string{"al"}
+	case 'c':
+		suffixes = []string{"ance", "ence"}
+	case 'e':
+		suffixes = []string{"er"}
+	case 'i':
+		suffixes = []string{"ic"}
+	case 'l':
+		suffixes = []string{"able", "ible"}
+	case 'n':
+		suffixes = []string{"ant", "ement", "ment", "ent"}
+	case 'o':
+		if p.ends("ion") && p.j >= 0 && (p.b[p.j] == 's' || p.b[p.j] == 't') {
+			break
+		}
+		suffixes = []string{"ou"}
+	case 's':
+		suffixes = []string{"ism"}
+	case 't':
+		suffixes = []string{"ate", "iti"}
+	case 'u':
+		suffixes = []string{"ous"}
+	case 'v':
+		suffixes = []string{"ive"}
+	case 'z':
+		suffixes = []string{"ize"}
+	default:
+		return
+	}
+
+	found := suffixes == nil // The -ion case matched already.
+	for _, s := range suffixes {
+		if p.ends(s) {
+			found = true
+			break
+		}
+	}
+	if found && p.m() > 1 {
+		p.k = p.j
+	}
+}
+
+// step5 removes a final -e and changes -ll to -l, when the stem is long enough.
+func (p *porter) step5() {
+	p.j = p.k
+	if p.b[p.k] == 'e' {
+		a := p.m()
+		if a > 1 || a == 1 && !p.cvc(p.k-1) {
+			p.k--
+		}
+	}
+	if p.b[p.k] == 'l' && p.doublec(p.k) && p.m() > 1 {
+		p.k--
+	}
+}
+
+// stemEnglish returns the stem of an English word, which is expected to be in
+// lower case. Words with characters other than a-z are returned unchanged.
+func stemEnglish(word string) string {
+	if len(word) <= 2 {
+		return word
+	}
+	for i := 0; i < len(word); i++ {
+		if word[i] < 'a' || word[i] > 'z' {
+			return word
+		}
+	}
+	p := &porter{b: []byte(word), k: len(word) - 1}
+	p.step1ab()
+	if p.k > 0 {
+		p.step1c()
+		p.step2()
+		p.step3()
+		p.step4()
+		p.step5()
+	}
+	return string(p.b[:p.k+1])
+}
@@ -3,6 +3,8 @@ package types
 import (
 	"bytes"
 	"encoding/binary"
+	"math"
+	"strings"
 
 	"github.com/dgraph-io/dgraph/tok"
 	"github.com/dgraph-io/dgraph/x"
@@ -11,9 +13,15 @@ import (
 const (
 	// Posting list keys are prefixed with this rune if it is a mutation meant for
 	// the index.
-	indexRune   = ':'
-	dateFormat1 = "2006-01-02"
-	dateFormat2 = "2006-01-02T15:04:05"
+	indexRune = ':'
+	// Exact tokens hold the whole string value, and are prefixed with this
+	// byte to keep them apart from the term tokens. The tokenizer never
+	// outputs it.
+	exactPrefix = "\x01"
+	// Full-text tokens hold a stemmed term, and are prefixed with this byte.
+	fullTextPrefix = "\x02"
+	dateFormat1    = "2006-01-02"
+	dateFormat2    = "2006-01-02T15:04:05"
 )
 
 // IndexKey creates a key for indexing the term for given attribute.
@@ -33,8 +41,31 @@ func TokenFromKey(key []byte) string {
 	return string(key[i+1:])
 }
 
+// ExactToken returns the token for exact matching of the string s.
+func ExactToken(s string) string {
+	return exactPrefix + s
+}
+
+// ExactValue returns the string value held by an exact token, and whether
+// token is an exact token at all.
+func ExactValue(token string) (string, bool) {
+	if !strings.HasPrefix(token, exactPrefix) {
+		return "", false
+	}
+	return token[len(exactPrefix):], true
+}
+
+// FullTextToken returns the token for full-text search of the stemmed term.
+func FullTextToken(term string) string {
+	return fullTextPrefix + term
+}
+
 // DefaultIndexKeys tokenizes data as a string and return keys for indexing.
-func DefaultIndexKeys(attr string, val *String) []string {
+// Only values without a language get the exact token for the whole string,
+// which is used for sorting and matching whole values, so that each UID has at
+// most one. If fullText is set, the keys include the tokens for full-text
+// search in the language of the value, if it is supported.
+func DefaultIndexKeys(attr, lang string, val *String, fullText bool) []string {
 	data := []byte((*val).String())
 	tokenizer, err := tok.NewTokenizer(data)
 	if err != nil {
@@ -50,24 +81,70 @@ func DefaultIndexKeys(attr string, val *String) []string {
 		}
 		tokens = append(tokens, string(s))
 	}
+	if len(lang) == 0 {
+		tokens = append(tokens, ExactToken(string(*val)))
+	}
+	if !fullText {
+		return tokens
+	}
+
+	terms, err := FullTextTerms(lang, val)
+	if err != nil {
+		// Full-text search isn't supported for the language.
+		return tokens
+	}
+	seen := make(map[string]bool)
+	for _, term := range terms {
+		if !seen[term] {
+			seen[term] = true
+			tokens = append(tokens, FullTextToken(term))
+		}
+	}
 	return tokens
 }
 
+// FullTextTerms returns the stemmed terms of val in the language lang, or in
+// the default language if lang is empty. A term is repeated as many times as
+// it occurs.
+func FullTextTerms(lang string, val *String) ([]string, error) {
+	if len(lang) == 0 {
+		lang = tok.DefaultLanguage
+	}
+	return tok.FullTextTokens(lang, string(*val))
+}
+
+// encodeInt32 returns the big-endian bytes of v with the sign bit flipped, so
+// that negative numbers sort before positive ones.
+func encodeInt32(v int32) (string, error) {
+	buf := new(bytes.Buffer)
+	err := binary.Write(buf, binary.BigEndian, uint32(v)^(1<<31))
+	if err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
 // IntIndex indexs int type.
 func IntIndex(attr string, val *Int32) ([]string, error) {
-	buf := new(bytes.Buffer)
-	err := binary.Write(buf, binary.BigEndian, int32(*val))
+	token, err := encodeInt32(int32(*val))
 	if err != nil {
 		return nil, err
 	}
-	return []string{buf.String()}, nil
+	return []string{token}, nil
 }
 
-// FloatIndex indexs float type.
+// FloatIndex indexs float type. The bits of positive numbers have their sign
+// bit flipped, and those of negative numbers are all flipped, so that the
+// tokens sort in the same order as the numbers.
 func FloatIndex(attr string, val *Float) ([]string, error) {
-	in := int32(*val)
+	bits := math.Float64bits(float64(*val))
+	if bits&(1<<63) != 0 {
+		bits = ^bits
+	} else {
+		bits |= 1 << 63
+	}
 	buf := new(bytes.Buffer)
-	err := binary.Write(buf, binary.BigEndian, in)
+	err := binary.Write(buf, binary.BigEndian, bits)
 	if err != nil {
 		return nil, err
 	}
@@ -76,20 +153,18 @@ func FloatIndex(attr string, val *Float) ([]string, error) {
 
 // DateIndex indexs time type.
 func DateIndex(attr string, val *Date) ([]string, error) {
-	buf := new(bytes.Buffer)
-	err := binary.Write(buf, binary.BigEndian, int32((*val).Time.Year()))
+	token, err := encodeInt32(int32((*val).Time.Year()))
 	if err != nil {
 		return nil, err
 	}
-	return []string{buf.String()}, nil
+	return []string{token}, nil
 }
 
 // TimeIndex indexs time type.
 func TimeIndex(attr string, val *Time) ([]string, error) {
-	buf := new(bytes.Buffer)
-	err := binary.Write(buf, binary.BigEndian, int32((*val).Time.Year()))
+	token, err := encodeInt32(int32((*val).Time.Year()))
 	if err != nil {
 		return nil, err
 	}
-	return []string{buf.String()}, nil
+	return []string{token}, nil
 }
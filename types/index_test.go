@@ -0,0 +1,53 @@
+/*
+ * Copyright 2016 Dgraph Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * 		http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package types
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIntIndexOrder(t *testing.T) {
+	vals := []Int32{math.MinInt32, -300, -1, 0, 1, 255, 256, math.MaxInt32}
+	var prev string
+	for i, v := range vals {
+		tokens, err := IntIndex("age", &v)
+		require.NoError(t, err)
+		require.Len(t, tokens, 1)
+		if i > 0 {
+			require.True(t, prev < tokens[0], "%d should sort before %d", vals[i-1], v)
+		}
+		prev = tokens[0]
+	}
+}
+
+func TestFloatIndexOrder(t *testing.T) {
+	vals := []Float{Float(math.Inf(-1)), -1e10, -2.5, -0.5, -1e-10, 0, 1e-10,
+		0.5, 2.5, 1e10, Float(math.Inf(1))}
+	var prev string
+	for i, v := range vals {
+		tokens, err := FloatIndex("score", &v)
+		require.NoError(t, err)
+		require.Len(t, tokens, 1)
+		if i > 0 {
+			require.True(t, prev < tokens[0], "%v should sort before %v", vals[i-1], v)
+		}
+		prev = tokens[0]
+	}
+}
@@ -100,3 +100,35 @@ func (s Scalar) Sort(v []Value, ul *task.List) error {
 	}
 	return x.Errorf("Scalar doesn't support sorting %s", s)
 }
+
+// Less returns true if a is less than b. Both values should be of the type s.
+func (s Scalar) Less(a, b Value) (bool, error) {
+	switch s.ID() {
+	case DateID:
+		return a.(*Date).Time.Before(b.(*Date).Time), nil
+	case DateTimeID:
+		return a.(*Time).Time.Before(b.(*Time).Time), nil
+	case Int32ID:
+		return *(a.(*Int32)) < *(b.(*Int32)), nil
+	case FloatID:
+		return *(a.(*Float)) < *(b.(*Float)), nil
+	case StringID:
+		return *(a.(*String)) < *(b.(*String)), nil
+	case BytesID:
+		return bytes.Compare(*(a.(*Bytes)), *(b.(*Bytes))) < 0, nil
+	}
+	return false, x.Errorf("Scalar doesn't support comparison %s", s)
+}
+
+// LessValues returns true if a sorts before b. Both should be of the same
+// type. The values of types which can't be compared, like bool, are compared
+// by their binary form.
+func LessValues(a, b Value) bool {
+	less, err := a.Type().Less(a, b)
+	if err != nil {
+		ba, _ := a.MarshalBinary()
+		bb, _ := b.MarshalBinary()
+		return bytes.Compare(ba, bb) < 0
+	}
+	return less
+}
@@ -116,3 +116,22 @@ func TestSortDateTimes(t *testing.T) {
 			"2006-01-02T15:04:06Z", "2016-01-02T15:04:05Z"},
 		toString(t, list))
 }
+
+func TestLess(t *testing.T) {
+	list := getInput(Int32ID, []string{"11", "22"})
+	less, err := int32Type.Less(list[0], list[1])
+	require.NoError(t, err)
+	require.True(t, less)
+	less, err = int32Type.Less(list[1], list[0])
+	require.NoError(t, err)
+	require.False(t, less)
+
+	list = getInput(DateID, []string{"2006-01-02", "2005-01-02"})
+	less, err = dateType.Less(list[0], list[1])
+	require.NoError(t, err)
+	require.False(t, less)
+
+	list = getInput(BoolID, []string{"true", "false"})
+	_, err = booleanType.Less(list[0], list[1])
+	require.Error(t, err)
+}
@@ -10,6 +10,7 @@ import (
 	"os"
 	"path"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -36,7 +37,7 @@ func toRDF(buf *bytes.Buffer, item kv) {
 	for _, p := range pl.Postings {
 		x.Check2(buf.Write(pre))
 
-		if p.Uid == math.MaxUint64 && !bytes.Equal(p.Value, nil) {
+		if (p.Uid == math.MaxUint64 || len(p.Lang) > 0) && !bytes.Equal(p.Value, nil) {
 			// Value posting
 			// Convert to appropriate type
 			typ := stype.ValueForType(stype.TypeID(p.ValType))
@@ -45,11 +46,16 @@ func toRDF(buf *bytes.Buffer, item kv) {
 			x.Check(err)
 
 			x.Check2(buf.WriteString(fmt.Sprintf("%q", str)))
-			if p.ValType != 0 {
+			if len(p.Lang) > 0 {
+				x.Check2(buf.WriteString("@" + p.Lang))
+			} else if p.ValType != 0 {
 				x.Check2(buf.WriteString(fmt.Sprintf("^^<xs:%s> ", typ.Type().Name)))
 			}
 			x.Check2(buf.WriteString(" .\n"))
-			break
+			if p.Uid == math.MaxUint64 {
+				break
+			}
+			continue
 		}
 		// Uid list
 		strUID := strconv.FormatUint(p.Uid, 16)
@@ -148,6 +154,21 @@ func backup(gid uint32, bdir string) error {
 			continue
 		}
 		pred, uid := posting.SplitKey(key)
+		if strings.HasPrefix(pred, "~") {
+			// Skip the reverse edges. They get rebuilt from the forward edges.
+			it.Seek([]byte(fmt.Sprintf("%s~", pred)))
+			continue
+		}
+		if pred == posting.PredicateAttr {
+			// Skip the records of the predicates. They get rebuilt from the edges.
+			it.Seek([]byte(fmt.Sprintf("%s~", pred)))
+			continue
+		}
+		if strings.HasPrefix(pred, txnAttr) {
+			// Skip the records of the transactions.
+			it.Seek([]byte(fmt.Sprintf("%s~", pred)))
+			continue
+		}
 		if pred != lastPred && group.BelongsTo(pred) != gid {
 			it.Seek([]byte(fmt.Sprintf("%s~", pred)))
 			continue
@@ -46,6 +46,10 @@ func populateGraphBackup(t *testing.T) {
 
 	edge.Entity = 2
 	addEdge(t, edge, getOrCreate(posting.Key(2, "name")))
+
+	// A value in French too.
+	edge.Lang = "fr"
+	addEdge(t, edge, getOrCreate(posting.Key(2, "name")))
 }
 
 func initTestBackup(t *testing.T, schemaStr string) (string, *store.Store) {
@@ -82,7 +86,8 @@ func TestBackup(t *testing.T) {
 	err = backup(group.BelongsTo("friend"), bdir)
 	require.NoError(t, err)
 
-	// We have 2 name type edges(with index). FP("name")%10 =7.
+	// We have 3 name type edges(with index), one of them in French.
+	// FP("name")%10 =7.
 	err = backup(group.BelongsTo("name"), bdir)
 	require.NoError(t, err)
 
@@ -115,6 +120,11 @@ func TestBackup(t *testing.T) {
 			if !bytes.Equal(nq.ObjectValue, nil) {
 				require.Equal(t, []byte("photon"), nq.ObjectValue)
 			}
+			// The only language we set was French.
+			if nq.Lang != "" {
+				require.Equal(t, "fr", nq.Lang)
+				require.Equal(t, "_uid_:2", nq.Subject)
+			}
 			// The only objectId we set was uid 5.
 			if nq.ObjectId != "" {
 				require.Equal(t, "_uid_:5", nq.ObjectId)
@@ -125,5 +135,5 @@ func TestBackup(t *testing.T) {
 		require.NoError(t, scanner.Err())
 	}
 	// This order will bw presereved due to file naming.
-	require.Equal(t, []int{4, 2}, counts)
+	require.Equal(t, []int{4, 3}, counts)
 }
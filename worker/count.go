@@ -0,0 +1,168 @@
+/*
+ * Copyright 2016 Dgraph Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * 		http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package worker
+
+import (
+	"math"
+	"strconv"
+
+	"github.com/dgraph-io/dgraph/algo"
+	"github.com/dgraph-io/dgraph/posting"
+	"github.com/dgraph-io/dgraph/schema"
+	"github.com/dgraph-io/dgraph/task"
+	"github.com/dgraph-io/dgraph/types"
+	"github.com/dgraph-io/dgraph/x"
+)
+
+// countQuery holds the range of the number of edges asked for by a function
+// like gt(count(friend), 10). Both bounds are inclusive, and the range is
+// empty if lo > hi.
+type countQuery struct {
+	lo, hi uint32
+}
+
+func (q *countQuery) matches(n uint32) bool {
+	return n >= q.lo && n <= q.hi
+}
+
+// parseCountQuery returns the range of counts for a function on the number of
+// edges, which is one of eq, ge, gt, le, lt and between.
+func parseCountQuery(funcArgs []string) (*countQuery, error) {
+	fn := funcArgs[0]
+	nargs := 2
+	if fn == "between" {
+		nargs = 3
+	}
+	if len(funcArgs) != nargs {
+		return nil, x.Errorf("Function %s requires %d arguments, but got %d",
+			fn, nargs, len(funcArgs)-1)
+	}
+	counts := make([]uint32, 0, 2)
+	for _, arg := range funcArgs[1:] {
+		n, err := strconv.ParseUint(arg, 10, 32)
+		if err != nil {
+			return nil, x.Errorf("Invalid count %q for function %s", arg, fn)
+		}
+		counts = append(counts, uint32(n))
+	}
+
+	n := counts[0]
+	q := &countQuery{lo: 0, hi: math.MaxUint32}
+	switch fn {
+	case "eq":
+		q.lo, q.hi = n, n
+	case "ge":
+		q.lo = n
+	case "gt":
+		if n == math.MaxUint32 {
+			q.lo, q.hi = 1, 0
+		} else {
+			q.lo = n + 1
+		}
+	case "le":
+		q.hi = n
+	case "lt":
+		if n == 0 {
+			q.lo, q.hi = 1, 0
+		} else {
+			q.hi = n - 1
+		}
+	case "between":
+		q.lo, q.hi = n, counts[1]
+	default:
+		return nil, x.Errorf("Function %s is not supported on counts", fn)
+	}
+	return q, nil
+}
+
+// processCountFunc returns the UIDs whose number of edges of q.Attr is in the
+// range given by q.SrcFunc. If the attribute has a count index, the UIDs are
+// looked up in it. Else the edges of each of q.Uids are counted, so a root
+// function needs the index.
+func processCountFunc(q *task.Query) (*task.Result, error) {
+	cq, err := parseCountQuery(q.SrcFunc)
+	if err != nil {
+		return nil, err
+	}
+	var out *task.List
+	if schema.IsCounted(q.Attr) {
+		out, err = countFromIndex(q, cq)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		if len(q.Uids) == 0 {
+			return nil, x.Errorf("Attribute %s needs @count in the schema to use count() "+
+				"at the root", q.Attr)
+		}
+		out = new(task.List)
+		for _, uid := range q.Uids {
+			pl, decr := posting.GetOrCreate(posting.Key(uid, q.Attr))
+			n := pl.Length(0)
+			decr()
+			if cq.matches(uint32(n)) {
+				out.Uids = append(out.Uids, uid)
+			}
+		}
+	}
+	return &task.Result{UidMatrix: []*task.List{out}}, nil
+}
+
+// countFromIndex looks up the UIDs in the count index of q.Attr whose number
+// of edges is in the range of cq, among q.Uids if given. The UIDs without any
+// edges aren't in the index, so if the range has zero, the ones outside it
+// are looked up instead and left out of q.Uids.
+func countFromIndex(q *task.Query, cq *countQuery) (*task.List, error) {
+	cattr := posting.CountAttr(q.Attr)
+	withZero := cq.lo == 0
+	if withZero && len(q.Uids) == 0 {
+		return nil, x.Errorf("Function %s on count(%s) at the root must exclude zero",
+			q.SrcFunc[0], q.Attr)
+	}
+	t := posting.GetTokensTable(cattr)
+	if t == nil {
+		return nil, x.Errorf("Attribute %s has no count index", q.Attr)
+	}
+
+	opts := posting.ListOptions{}
+	if len(q.Uids) > 0 {
+		opts.Intersect = &task.List{Uids: q.Uids}
+	}
+	var lists []*task.List
+	for token := t.GetFirst(); len(token) > 0; token = t.GetNext(token) {
+		n := posting.CountFromToken(token)
+		if !withZero && n > cq.hi {
+			break
+		}
+		if cq.matches(n) == withZero {
+			continue
+		}
+		pl, decr := posting.GetOrCreate(types.IndexKey(cattr, token))
+		lists = append(lists, pl.Uids(opts))
+		decr()
+	}
+	uids := algo.MergeSorted(lists)
+	if !withZero {
+		return uids, nil
+	}
+	out := &task.List{Uids: make([]uint64, len(q.Uids))}
+	copy(out.Uids, q.Uids)
+	algo.ApplyFilter(out, func(uid uint64, i int) bool {
+		return algo.IndexOf(uids, uid) < 0
+	})
+	return out, nil
+}
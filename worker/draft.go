@@ -83,6 +83,8 @@ type node struct {
 	wal         *raftwal.Wal
 	messages    chan sendmsg
 	canCampaign bool
+	txns        *txnTable
+	oracle      tsOracle // Of the timestamps, in group 0.
 }
 
 func (n *node) Connect(pid uint64, addr string) {
@@ -152,6 +154,12 @@ func (n *node) ProposeAndWait(ctx context.Context, proposal *task.Proposal) erro
 	// Wait for the proposal to be committed.
 	if proposal.Mutations != nil {
 		x.Trace(ctx, "Waiting for the proposal: mutations.")
+	} else if proposal.Txn != nil {
+		x.Trace(ctx, "Waiting for the proposal: transaction decision.")
+	} else if proposal.Lease != nil {
+		x.Trace(ctx, "Waiting for the proposal: timestamp lease.")
+	} else if len(proposal.RebuildIndex) > 0 {
+		x.Trace(ctx, "Waiting for the proposal: index rebuild.")
 	} else {
 		x.Trace(ctx, "Waiting for the proposal: membership update.")
 	}
@@ -238,6 +246,34 @@ func (n *node) batchAndSendMessages() {
 }
 
 func (n *node) processMutation(e raftpb.Entry, m *task.Mutations) error {
+	if m.StartTs > 0 {
+		// These are applied once the transaction is committed.
+		if err := n.txns.prewrite(m); err != nil {
+			return err
+		}
+		return savePending(n.ctx, n.gid, m)
+	}
+	if err := n.txns.checkUnlocked(m); err != nil {
+		return err
+	}
+	if err := mutate(n.ctx, m); err != nil {
+		x.TraceError(n.ctx, err)
+		return err
+	}
+	return nil
+}
+
+func (n *node) processTxn(e raftpb.Entry, txn *task.Txn) error {
+	if txn.Primary {
+		return recordDecision(n.ctx, txn)
+	}
+	m, err := n.txns.decide(txn)
+	if err != nil {
+		return err
+	}
+	if err := dropPending(n.ctx, n.gid, txn.StartTs); err != nil || m == nil {
+		return err
+	}
 	if err := mutate(n.ctx, m); err != nil {
 		x.TraceError(n.ctx, err)
 		return err
@@ -254,6 +290,11 @@ func (n *node) processMembership(e raftpb.Entry, mm *task.Membership) error {
 	return nil
 }
 
+func (n *node) processLease(e raftpb.Entry, l *task.Lease) error {
+	x.AssertTrue(n.gid == 0)
+	return n.oracle.applyLease(l)
+}
+
 func (n *node) process(e raftpb.Entry) error {
 	if e.Data == nil {
 		return nil
@@ -283,8 +324,14 @@ func (n *node) process(e raftpb.Entry) error {
 
 		if proposal.Mutations != nil {
 			err = n.processMutation(e, proposal.Mutations)
+		} else if proposal.Txn != nil {
+			err = n.processTxn(e, proposal.Txn)
 		} else if proposal.Membership != nil {
 			err = n.processMembership(e, proposal.Membership)
+		} else if proposal.Lease != nil {
+			err = n.processLease(e, proposal.Lease)
+		} else if len(proposal.RebuildIndex) > 0 {
+			err = n.processRebuildIndex(e, proposal.RebuildIndex)
 		}
 		n.props.Done(proposal.Id, err)
 	}
@@ -469,6 +516,7 @@ func newNode(gid uint32, id uint64, myAddr string) *node {
 		props:       props,
 		raftContext: rc,
 		messages:    make(chan sendmsg, 1000),
+		txns:        newTxnTable(),
 	}
 	return n
 }
@@ -538,7 +586,12 @@ func (n *node) InitAndStartNode(wal *raftwal.Wal) {
 			n.canCampaign = true
 		}
 	}
+	// The transactions prewritten before a restart, or on the peer this node
+	// was populated from, are pending still.
+	x.Checkf(restorePending(n.gid, n.txns), "Error while restoring transactions")
 	go n.Run()
+	go n.resolvePeriodically()
+	go n.rebuildStaleIndexes()
 	// TODO: Find a better way to snapshot, so we don't lose the membership
 	// state information, which isn't persisted.
 	// go n.snapshotPeriodically()
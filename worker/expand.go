@@ -0,0 +1,141 @@
+/*
+ * Copyright 2016 Dgraph Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * 		http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package worker
+
+import (
+	"sort"
+
+	"golang.org/x/net/context"
+
+	"github.com/dgraph-io/dgraph/posting"
+	"github.com/dgraph-io/dgraph/posting/types"
+	"github.com/dgraph-io/dgraph/task"
+	"github.com/dgraph-io/dgraph/x"
+)
+
+// predicatesResult returns the predicates recorded for any of the given UIDs,
+// in sorted order.
+func predicatesResult(uids []uint64) *task.Result {
+	seen := make(map[string]bool)
+	for _, uid := range uids {
+		pl, decr := posting.GetOrCreate(posting.Key(uid, posting.PredicateAttr))
+		pl.Iterate(0, func(p *types.Posting) bool {
+			seen[string(p.Value)] = true
+			return true
+		})
+		decr()
+	}
+	out := &task.Result{}
+	for attr := range seen {
+		out.Predicates = append(out.Predicates, attr)
+	}
+	sort.Strings(out.Predicates)
+	return out
+}
+
+// PredicatesOverNetwork returns the predicates recorded for any of the given
+// UIDs, in sorted order. The predicates are recorded by the groups serving
+// them, so all of the groups are asked.
+func PredicatesOverNetwork(ctx context.Context, uids []uint64) ([]string, error) {
+	q := &task.Query{
+		Attr: posting.PredicateAttr,
+		Uids: uids,
+	}
+	var addrs []string
+	for _, gid := range groups().KnownGroups() {
+		if groups().ServesGroup(gid) {
+			continue
+		}
+		if addr := groups().AnyServer(gid); addr != "" {
+			addrs = append(addrs, addr)
+		}
+	}
+
+	type reply struct {
+		result *task.Result
+		err    error
+	}
+	replies := make(chan reply, len(addrs))
+	for _, addr := range addrs {
+		go func(addr string) {
+			pl := pools().get(addr)
+			conn, err := pl.Get()
+			if err != nil {
+				replies <- reply{err: x.Wrapf(err, "PredicatesOverNetwork: while retrieving connection.")}
+				return
+			}
+			defer pl.Put(conn)
+			result, err := NewWorkerClient(conn).ServeTask(ctx, q)
+			replies <- reply{result, err}
+		}(addr)
+	}
+
+	// The local store holds the records of all the groups served here.
+	seen := make(map[string]bool)
+	for _, attr := range predicatesResult(uids).Predicates {
+		seen[attr] = true
+	}
+	var rerr error
+	for range addrs {
+		r := <-replies
+		if r.err != nil {
+			x.TraceError(ctx, x.Wrapf(r.err, "Error while fetching predicates"))
+			rerr = r.err
+			continue
+		}
+		for _, attr := range r.result.Predicates {
+			seen[attr] = true
+		}
+	}
+	if rerr != nil {
+		return nil, rerr
+	}
+	out := make([]string, 0, len(seen))
+	for attr := range seen {
+		out = append(out, attr)
+	}
+	sort.Strings(out)
+	return out, nil
+}
+
+// expandStarEdges replaces each deletion of all the predicates of an entity,
+// S * *, by a deletion of all the values of each predicate recorded for it,
+// S P *, which are then sent to the groups serving them. The groups remove
+// the records as they apply the deletions.
+func expandStarEdges(ctx context.Context, edges []*task.DirectedEdge) ([]*task.DirectedEdge,
+	error) {
+	var out []*task.DirectedEdge
+	for _, edge := range edges {
+		if edge.Attr != x.Star {
+			out = append(out, edge)
+			continue
+		}
+		attrs, err := PredicatesOverNetwork(ctx, []uint64{edge.Entity})
+		if err != nil {
+			return nil, err
+		}
+		for _, attr := range attrs {
+			out = append(out, &task.DirectedEdge{
+				Entity: edge.Entity,
+				Attr:   attr,
+				Value:  []byte(x.Star),
+				Label:  edge.Label,
+			})
+		}
+	}
+	return out, nil
+}
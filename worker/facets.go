@@ -0,0 +1,93 @@
+/*
+ * Copyright 2016 Dgraph Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * 		http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package worker
+
+import (
+	"math"
+
+	"github.com/dgraph-io/dgraph/posting"
+	"github.com/dgraph-io/dgraph/posting/types"
+	"github.com/dgraph-io/dgraph/task"
+)
+
+// facetsList returns the facets on the edges of pl to each of the given UIDs,
+// which should be sorted. If there are no UIDs, it holds the facets on the
+// value of pl in the first of langs which there is one for instead.
+func facetsList(pl *posting.List, uids []uint64, langs []string,
+	param *task.FacetParams) *task.FacetsList {
+	if len(uids) == 0 {
+		uids = []uint64{valueUid(pl, langs)}
+	}
+	out := &task.FacetsList{FacetsList: make([]*task.Facets, len(uids))}
+	for i := range out.FacetsList {
+		out.FacetsList[i] = new(task.Facets)
+	}
+	var idx int
+	pl.Iterate(uids[0]-1, func(p *types.Posting) bool {
+		for idx < len(uids) && uids[idx] < p.Uid {
+			idx++
+		}
+		if idx == len(uids) {
+			return false
+		}
+		if uids[idx] == p.Uid {
+			out.FacetsList[idx].Facets = selectFacets(p.Facets, param)
+		}
+		return true
+	})
+	return out
+}
+
+// valueUid returns the UID of the posting holding the value of pl in the
+// first of langs which there is one for, as picked by ValueFor.
+func valueUid(pl *posting.List, langs []string) uint64 {
+	for _, lang := range langs {
+		if lang == "." {
+			lang = ""
+		}
+		if _, _, err := pl.LangValue(lang); err != nil {
+			continue
+		}
+		if len(lang) > 0 {
+			return posting.LangUid(lang)
+		}
+		break
+	}
+	return math.MaxUint64
+}
+
+// selectFacets returns the facets with the keys asked for in param. Both the
+// facets and the keys are sorted.
+func selectFacets(facets []*task.Facet, param *task.FacetParams) []*task.Facet {
+	if param.AllKeys {
+		return facets
+	}
+	var out []*task.Facet
+	var i int
+	for _, f := range facets {
+		for i < len(param.Keys) && param.Keys[i] < f.Key {
+			i++
+		}
+		if i == len(param.Keys) {
+			break
+		}
+		if param.Keys[i] == f.Key {
+			out = append(out, f)
+		}
+	}
+	return out
+}
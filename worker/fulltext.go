@@ -0,0 +1,154 @@
+/*
+ * Copyright 2016 Dgraph Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * 		http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package worker
+
+import (
+	"math"
+
+	"github.com/dgraph-io/dgraph/algo"
+	"github.com/dgraph-io/dgraph/posting"
+	"github.com/dgraph-io/dgraph/schema"
+	"github.com/dgraph-io/dgraph/task"
+	"github.com/dgraph-io/dgraph/tok"
+	"github.com/dgraph-io/dgraph/types"
+	"github.com/dgraph-io/dgraph/x"
+)
+
+// Parameters of the BM25 ranking function.
+const (
+	bm25K1 = 1.2
+	bm25B  = 0.75
+)
+
+// IsTextFunc returns true if name is one of the full-text search functions,
+// like anyoftext(description, "red shoes").
+func IsTextFunc(name string) bool {
+	return name == "anyoftext" || name == "alloftext"
+}
+
+// textQuery holds the terms of a full-text search.
+type textQuery struct {
+	fn    string
+	terms []string // Distinct stemmed terms.
+	// Number of documents containing each term.
+	docFreq []int
+}
+
+// getTextTokens returns the index tokens for the terms of a full-text search,
+// along with the query to score the results with.
+func getTextTokens(attr string, funcArgs []string) ([]string, *textQuery, error) {
+	fn := funcArgs[0]
+	if len(funcArgs) != 2 {
+		return nil, nil, x.Errorf("Function %s requires 2 arguments, but got %d",
+			fn, len(funcArgs))
+	}
+	if !schema.IsFullText(attr) {
+		return nil, nil, x.Errorf("Attribute %s has no full-text index", attr)
+	}
+	if scalar, ok := schema.TypeOf(attr).(types.Scalar); !ok ||
+		scalar.ID() != types.StringID {
+		return nil, nil, x.Errorf("Function %s is only supported for string attributes",
+			fn)
+	}
+	terms, err := tok.FullTextTokens(tok.DefaultLanguage, funcArgs[1])
+	if err != nil {
+		return nil, nil, err
+	}
+
+	q := &textQuery{fn: fn}
+	var tokens []string
+	seen := make(map[string]bool)
+	for _, term := range terms {
+		if seen[term] {
+			continue
+		}
+		seen[term] = true
+		q.terms = append(q.terms, term)
+		tokens = append(tokens, types.FullTextToken(term))
+	}
+	q.docFreq = make([]int, len(q.terms))
+	return tokens, q, nil
+}
+
+// apply replaces the per term UID lists in out by the UIDs matching the
+// search, along with their scores if withScores is set.
+func (q *textQuery) apply(attr string, out *task.Result, withScores bool) {
+	var uids *task.List
+	if q.fn == "alloftext" {
+		uids = algo.IntersectSorted(out.UidMatrix)
+	} else {
+		uids = algo.MergeSorted(out.UidMatrix)
+	}
+	out.UidMatrix = []*task.List{uids}
+	out.Values = []*task.Value{{Val: x.Nilbyte}}
+	if withScores {
+		out.Scores = q.scores(attr, uids)
+	}
+}
+
+// scores returns the BM25 score of each of the UIDs. The term frequencies and
+// document lengths come from the stored values, and the number of documents
+// and their average length from the counters of the full-text index.
+func (q *textQuery) scores(attr string, uids *task.List) []float64 {
+	if len(uids.Uids) == 0 {
+		return nil
+	}
+	numDocs, numTerms := posting.FullTextStats(attr)
+	var avgLen float64
+	if numDocs > 0 {
+		avgLen = float64(numTerms) / float64(numDocs)
+	}
+
+	idf := make(map[string]float64)
+	for i, term := range q.terms {
+		df := float64(q.docFreq[i])
+		n := math.Max(float64(numDocs), df)
+		idf[term] = math.Log(1 + (n-df+0.5)/(df+0.5))
+	}
+
+	stype, _ := types.TypeForName("string")
+	freqs := make([]map[string]int, len(uids.Uids))
+	lengths := make([]int, len(uids.Uids))
+	for i, uid := range uids.Uids {
+		freqs[i] = make(map[string]int)
+		v, err := fetchValue(uid, attr, stype.(types.Scalar))
+		if err != nil {
+			continue
+		}
+		terms, err := types.FullTextTerms("", v.(*types.String))
+		if err != nil {
+			continue
+		}
+		for _, term := range terms {
+			freqs[i][term]++
+		}
+		lengths[i] = len(terms)
+	}
+
+	scores := make([]float64, len(uids.Uids))
+	for i := range uids.Uids {
+		norm := 1 - bm25B
+		if avgLen > 0 {
+			norm += bm25B * float64(lengths[i]) / avgLen
+		}
+		for _, term := range q.terms {
+			tf := float64(freqs[i][term])
+			scores[i] += idf[term] * tf * (bm25K1 + 1) / (tf + bm25K1*norm)
+		}
+	}
+	return scores
+}
@@ -0,0 +1,82 @@
+/*
+ * Copyright 2016 Dgraph Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * 		http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package worker
+
+import (
+	"time"
+
+	"github.com/coreos/etcd/raft/raftpb"
+	"golang.org/x/net/context"
+
+	"github.com/dgraph-io/dgraph/group"
+	"github.com/dgraph-io/dgraph/posting"
+	"github.com/dgraph-io/dgraph/schema"
+	"github.com/dgraph-io/dgraph/task"
+	"github.com/dgraph-io/dgraph/x"
+)
+
+// staleIndexes returns the attributes of the group whose index was last built
+// with another kind of index, or not at all, as when the schema asks for a new
+// one.
+func (n *node) staleIndexes() []string {
+	var attrs []string
+	for _, attr := range schema.IndexedFields() {
+		if group.BelongsTo(attr) == n.gid && posting.IndexStale(attr) {
+			attrs = append(attrs, attr)
+		}
+	}
+	return attrs
+}
+
+// rebuildStaleIndexes has the leader of the group propose a rebuild of each
+// stale index, until there are none left. The rebuilds are applied by all the
+// replicas, in order with the mutations.
+func (n *node) rebuildStaleIndexes() {
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			attrs := n.staleIndexes()
+			if len(attrs) == 0 {
+				return
+			}
+			if n.AmLeader() {
+				n.proposeRebuilds(attrs)
+			}
+		case <-n.done:
+			return
+		}
+	}
+}
+
+func (n *node) proposeRebuilds(attrs []string) {
+	for _, attr := range attrs {
+		ctx, cancel := context.WithTimeout(n.ctx, time.Minute)
+		err := n.ProposeAndWait(ctx, &task.Proposal{RebuildIndex: attr})
+		cancel()
+		if err != nil {
+			x.TraceError(n.ctx, x.Wrapf(err, "Error while rebuilding index for attr %s", attr))
+			return
+		}
+	}
+}
+
+// processRebuildIndex rebuilds the index of attr, once its rebuild is applied.
+func (n *node) processRebuildIndex(e raftpb.Entry, attr string) error {
+	return posting.RebuildIndex(n.ctx, attr)
+}
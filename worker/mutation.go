@@ -104,35 +104,99 @@ func addToMutationMap(mutationMap map[uint32]*task.Mutations,
 	}
 }
 
+// addLookupsToMutationMap sends each lookup to the group serving its
+// attribute, which checks it for conflicts.
+func addLookupsToMutationMap(mutationMap map[uint32]*task.Mutations, lookups []*task.Query) {
+	for _, q := range lookups {
+		gid := group.BelongsTo(q.Attr)
+		mu := mutationMap[gid]
+		if mu == nil {
+			mu = &task.Mutations{GroupId: gid}
+			mutationMap[gid] = mu
+		}
+		mu.Lookups = append(mu.Lookups, q)
+	}
+}
+
+// StartTs returns the start timestamp for a transaction which reads before
+// writing, like an upsert. Its mutations are then sent with it and the
+// lookups it read, so that it's aborted if any of them changed since.
+func StartTs(ctx context.Context) (uint64, error) {
+	return timestamp(ctx)
+}
+
 // MutateOverNetwork checks which group should be running the mutations
 // according to fingerprint of the predicate and sends it to that instance.
+// The mutations are applied in a transaction, so that they are either applied
+// in all the groups, or in none of them. It starts at m.StartTs if it's set.
 func MutateOverNetwork(ctx context.Context, m *task.Mutations) error {
 	mutationMap := make(map[uint32]*task.Mutations)
 
+	dels, err := expandStarEdges(ctx, m.Del)
+	if err != nil {
+		return err
+	}
 	addToMutationMap(mutationMap, m.Set, set)
-	addToMutationMap(mutationMap, m.Del, del)
+	addToMutationMap(mutationMap, dels, del)
+	addLookupsToMutationMap(mutationMap, m.Lookups)
+	if len(mutationMap) == 0 {
+		return nil
+	}
 
+	startTs := m.StartTs
+	if startTs == 0 {
+		if startTs, err = timestamp(ctx); err != nil {
+			return err
+		}
+	}
+	// The prewrites don't use the context of the request, so that all of them
+	// are done with before the transaction is decided, and none of them locks
+	// its keys after it was aborted.
+	pctx, pcancel := context.WithTimeout(context.Background(), txnTimeout)
+	defer pcancel()
+	gids := make([]uint32, 0, len(mutationMap))
 	errors := make(chan error, len(mutationMap))
 	for gid, mu := range mutationMap {
-		proposeOrSend(ctx, gid, mu, errors)
+		mu.StartTs = startTs
+		gids = append(gids, gid)
+		go proposeOrSend(pctx, gid, mu, errors)
 	}
 
-	// Wait for all the goroutines to reply back.
-	// We return if an error was returned or the parent called ctx.Done()
+	// Wait for all the groups to prewrite the mutations, so that the abort
+	// doesn't overtake any of them. We abort if an error was returned or the
+	// parent called ctx.Done() meanwhile.
+	var rerr error
 	for i := 0; i < len(mutationMap); i++ {
-		select {
-		case err := <-errors:
-			if err != nil {
-				x.TraceError(ctx, x.Wrapf(err, "Error while running all mutations"))
-				return err
-			}
-		case <-ctx.Done():
-			return ctx.Err()
+		if err := <-errors; err != nil && rerr == nil {
+			x.TraceError(ctx, x.Wrapf(err, "Error while running all mutations"))
+			rerr = err
 		}
 	}
-	close(errors)
+	if rerr == nil {
+		rerr = ctx.Err()
+	}
 
-	return nil
+	txn := &task.Txn{StartTs: startTs}
+	if rerr == nil {
+		txn.CommitTs, rerr = timestamp(ctx)
+	}
+	// The transaction is decided once its primary record is written, even if
+	// the request is cancelled. It may have been aborted there already, if it
+	// was taken for an orphan.
+	dctx, cancel := context.WithTimeout(context.Background(), txnTimeout)
+	defer cancel()
+	decided, err := decidePrimary(dctx, txn)
+	if err != nil {
+		// The groups resolve it from the record once it times out.
+		return err
+	}
+	if rerr == nil && decided.CommitTs == 0 {
+		rerr = errConflict
+	}
+	if err := decideOverNetwork(decided, gids); err != nil && rerr == nil {
+		rerr = err
+	}
+	return rerr
 }
 
 // Mutate is used to apply mutations over the network on other instances.
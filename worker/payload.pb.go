@@ -119,6 +119,9 @@ type WorkerClient interface {
 	JoinCluster(ctx context.Context, in *task.RaftContext, opts ...grpc.CallOption) (*Payload, error)
 	UpdateMembership(ctx context.Context, in *task.MembershipUpdate, opts ...grpc.CallOption) (*task.MembershipUpdate, error)
 	Backup(ctx context.Context, in *BackupPayload, opts ...grpc.CallOption) (*BackupPayload, error)
+	// Transaction RPCs.
+	CommitOrAbort(ctx context.Context, in *task.Txn, opts ...grpc.CallOption) (*Payload, error)
+	Timestamps(ctx context.Context, in *task.Num, opts ...grpc.CallOption) (*task.List, error)
 }
 
 type workerClient struct {
@@ -242,6 +245,24 @@ func (c *workerClient) Backup(ctx context.Context, in *BackupPayload, opts ...gr
 	return out, nil
 }
 
+func (c *workerClient) CommitOrAbort(ctx context.Context, in *task.Txn, opts ...grpc.CallOption) (*Payload, error) {
+	out := new(Payload)
+	err := grpc.Invoke(ctx, "/worker.Worker/CommitOrAbort", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workerClient) Timestamps(ctx context.Context, in *task.Num, opts ...grpc.CallOption) (*task.List, error) {
+	out := new(task.List)
+	err := grpc.Invoke(ctx, "/worker.Worker/Timestamps", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // Server API for Worker service
 
 type WorkerServer interface {
@@ -258,6 +279,9 @@ type WorkerServer interface {
 	JoinCluster(context.Context, *task.RaftContext) (*Payload, error)
 	UpdateMembership(context.Context, *task.MembershipUpdate) (*task.MembershipUpdate, error)
 	Backup(context.Context, *BackupPayload) (*BackupPayload, error)
+	// Transaction RPCs.
+	CommitOrAbort(context.Context, *task.Txn) (*Payload, error)
+	Timestamps(context.Context, *task.Num) (*task.List, error)
 }
 
 func RegisterWorkerServer(s *grpc.Server, srv WorkerServer) {
@@ -447,6 +471,42 @@ func _Worker_Backup_Handler(srv interface{}, ctx context.Context, dec func(inter
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Worker_CommitOrAbort_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(task.Txn)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkerServer).CommitOrAbort(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/worker.Worker/CommitOrAbort",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkerServer).CommitOrAbort(ctx, req.(*task.Txn))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Worker_Timestamps_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(task.Num)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkerServer).Timestamps(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/worker.Worker/Timestamps",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkerServer).Timestamps(ctx, req.(*task.Num))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _Worker_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "worker.Worker",
 	HandlerType: (*WorkerServer)(nil),
@@ -487,6 +547,14 @@ var _Worker_serviceDesc = grpc.ServiceDesc{
 			MethodName: "Backup",
 			Handler:    _Worker_Backup_Handler,
 		},
+		{
+			MethodName: "CommitOrAbort",
+			Handler:    _Worker_CommitOrAbort_Handler,
+		},
+		{
+			MethodName: "Timestamps",
+			Handler:    _Worker_Timestamps_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -916,37 +984,39 @@ var (
 func init() { proto.RegisterFile("worker/payload.proto", fileDescriptorPayload) }
 
 var fileDescriptorPayload = []byte{
-	// 505 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x8c, 0x92, 0xdf, 0x8e, 0xd2, 0x4e,
-	0x14, 0xc7, 0xdb, 0xa5, 0xbf, 0x02, 0x87, 0xe5, 0xb7, 0xf5, 0xc4, 0x35, 0x6b, 0xa3, 0x84, 0x34,
-	0xd1, 0x10, 0xff, 0x14, 0xdd, 0xf5, 0xc2, 0x78, 0xc7, 0x02, 0x1a, 0x5c, 0x40, 0x6c, 0x17, 0xbd,
-	0x34, 0x03, 0x9d, 0x85, 0x09, 0x7f, 0xa6, 0x3b, 0x33, 0x55, 0x79, 0x13, 0xdf, 0xc3, 0x97, 0xf0,
-	0xd2, 0x47, 0x30, 0xf8, 0x08, 0xbe, 0x80, 0xa1, 0x53, 0xd6, 0x68, 0x30, 0xf1, 0xa6, 0x39, 0xe7,
-	0xd3, 0xef, 0xf9, 0x9e, 0x93, 0x33, 0x07, 0xae, 0x7f, 0xe0, 0x62, 0x46, 0x45, 0x3d, 0x26, 0xab,
-	0x39, 0x27, 0x91, 0x1f, 0x0b, 0xae, 0x38, 0xda, 0x9a, 0xba, 0xf7, 0x27, 0x4c, 0x4d, 0x93, 0x91,
-	0x3f, 0xe6, 0x8b, 0x7a, 0x34, 0x11, 0x24, 0x9e, 0x3e, 0x64, 0x3c, 0x8b, 0xea, 0x8a, 0xc8, 0x59,
-	0xfa, 0xd1, 0x45, 0xde, 0x6d, 0xc8, 0x0f, 0xb4, 0x0b, 0x22, 0x58, 0x2d, 0xa2, 0xc8, 0x91, 0x59,
-	0x35, 0x6b, 0xfb, 0x81, 0x15, 0x11, 0x45, 0xbc, 0xcf, 0x26, 0x94, 0x4f, 0xc9, 0x78, 0x96, 0xc4,
-	0x5b, 0xd5, 0x21, 0xd8, 0x82, 0x5e, 0xbe, 0x63, 0x51, 0xaa, 0xb3, 0x82, 0xff, 0x04, 0xbd, 0xec,
-	0x44, 0x78, 0x13, 0x0a, 0x13, 0xc1, 0x93, 0x78, 0xf3, 0x63, 0xaf, 0x6a, 0xd6, 0xca, 0x41, 0x3e,
-	0xcd, 0x3b, 0x11, 0x3e, 0x01, 0x5b, 0x2a, 0xa2, 0x12, 0x79, 0x94, 0xab, 0x9a, 0xb5, 0xff, 0x8f,
-	0x6f, 0xf9, 0x7a, 0x50, 0xff, 0x37, 0x63, 0x3f, 0x4c, 0x35, 0x41, 0xa6, 0xf5, 0x9e, 0x81, 0xad,
-	0x09, 0x16, 0xc0, 0xea, 0xbf, 0xea, 0xb7, 0x1d, 0x03, 0x4b, 0x90, 0x0f, 0x87, 0xcd, 0x66, 0x3b,
-	0x0c, 0x1d, 0x13, 0xcb, 0x50, 0x6c, 0x0d, 0x07, 0xdd, 0x4e, 0xb3, 0x71, 0xde, 0x76, 0xf6, 0x10,
-	0xc0, 0x7e, 0xde, 0xe8, 0x74, 0xdb, 0x2d, 0x27, 0x77, 0xfc, 0x23, 0x07, 0xf6, 0xdb, 0xb4, 0x07,
-	0xde, 0x03, 0xab, 0x3d, 0x9e, 0x72, 0x3c, 0xd8, 0x36, 0xcd, 0xda, 0xb9, 0x7f, 0x02, 0xcf, 0xc0,
-	0x3b, 0x00, 0x0d, 0x29, 0xd9, 0x64, 0x39, 0x64, 0x91, 0xc4, 0xa2, 0x9f, 0xae, 0xa9, 0x9f, 0x2c,
-	0x5c, 0xd0, 0x61, 0x97, 0x49, 0xe5, 0x19, 0xf8, 0x00, 0xec, 0x5e, 0xa2, 0x88, 0xa2, 0x78, 0xa0,
-	0x79, 0x9a, 0x31, 0xbe, 0x94, 0xbb, 0x4c, 0x6b, 0x50, 0x0c, 0xa9, 0x78, 0x4f, 0xcf, 0x89, 0x9c,
-	0x61, 0x49, 0x17, 0xbc, 0x4e, 0xa8, 0x58, 0xb9, 0xfb, 0x3a, 0x09, 0xa8, 0x4c, 0xe6, 0x1b, 0x5f,
-	0x1f, 0xca, 0x03, 0x41, 0x23, 0x36, 0x26, 0x8a, 0x6e, 0x1e, 0x62, 0x6b, 0xff, 0x62, 0xb3, 0xc7,
-	0x33, 0xba, 0x92, 0x6e, 0x41, 0x83, 0xb3, 0x37, 0x9e, 0xf1, 0xc8, 0xc4, 0xbb, 0x60, 0x85, 0x5c,
-	0x28, 0xcc, 0xa6, 0xdb, 0xc4, 0xae, 0xf3, 0x2b, 0xbe, 0xf2, 0x7d, 0x0c, 0xa5, 0x80, 0x5c, 0xa8,
-	0x1e, 0x95, 0x92, 0x4c, 0xe8, 0x3f, 0x6d, 0xe2, 0x04, 0x4a, 0x2f, 0x39, 0x5b, 0x36, 0xe7, 0x89,
-	0x54, 0x54, 0xe0, 0xb5, 0x6c, 0x52, 0x72, 0xa1, 0x9a, 0x7c, 0xa9, 0xe8, 0x47, 0xb5, 0xab, 0xa8,
-	0x05, 0xce, 0x30, 0x8e, 0x88, 0xa2, 0x3d, 0xba, 0x18, 0x51, 0x21, 0xa7, 0x2c, 0xc6, 0x1b, 0xd9,
-	0x86, 0xae, 0x88, 0x56, 0xb8, 0x7f, 0xe1, 0x9e, 0x81, 0x4f, 0xc1, 0xd6, 0x77, 0x81, 0x87, 0x3b,
-	0xef, 0xc4, 0xdd, 0x8d, 0x3d, 0xe3, 0xd4, 0xf9, 0xb2, 0xae, 0x98, 0x5f, 0xd7, 0x15, 0xf3, 0xdb,
-	0xba, 0x62, 0x7e, 0xfa, 0x5e, 0x31, 0x46, 0x76, 0x7a, 0xe3, 0x27, 0x3f, 0x03, 0x00, 0x00, 0xff,
-	0xff, 0xf9, 0x71, 0xf5, 0x40, 0x30, 0x03, 0x00, 0x00,
+	// 530 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x8c, 0x53, 0xd1, 0x6e, 0xd3, 0x4a,
+	0x10, 0xb5, 0x5b, 0x5f, 0x37, 0x99, 0x34, 0xb7, 0x66, 0x45, 0x51, 0xb1, 0x20, 0x8a, 0x2c, 0x81,
+	0x22, 0xa0, 0x0e, 0xb4, 0x3c, 0x20, 0xde, 0x52, 0x27, 0xa0, 0xd0, 0x24, 0x0d, 0x76, 0x02, 0x8f,
+	0x68, 0x13, 0x6f, 0x93, 0x55, 0xe2, 0xac, 0xbb, 0xbb, 0x86, 0xe6, 0x4f, 0xf8, 0x0f, 0x7e, 0x02,
+	0xde, 0xf8, 0x04, 0x14, 0x7e, 0x04, 0xd9, 0xeb, 0x14, 0x81, 0x12, 0x89, 0x17, 0x6b, 0xe6, 0xf8,
+	0xcc, 0x99, 0xd1, 0x99, 0x59, 0xb8, 0xfd, 0x89, 0xf1, 0x19, 0xe1, 0xf5, 0x18, 0x2f, 0xe7, 0x0c,
+	0x87, 0x6e, 0xcc, 0x99, 0x64, 0xc8, 0x54, 0xa8, 0xfd, 0x78, 0x42, 0xe5, 0x34, 0x19, 0xb9, 0x63,
+	0x16, 0xd5, 0xc3, 0x09, 0xc7, 0xf1, 0xf4, 0x98, 0xb2, 0x3c, 0xaa, 0x4b, 0x2c, 0x66, 0xd9, 0x47,
+	0x15, 0x39, 0xf7, 0x61, 0xaf, 0xaf, 0x54, 0x10, 0x02, 0xa3, 0x89, 0x25, 0x3e, 0xd2, 0xab, 0x7a,
+	0x6d, 0xdf, 0x37, 0x42, 0x2c, 0xb1, 0xf3, 0x45, 0x87, 0xf2, 0x19, 0x1e, 0xcf, 0x92, 0x78, 0xcd,
+	0x3a, 0x04, 0x93, 0x93, 0xab, 0x0f, 0x34, 0xcc, 0x78, 0x86, 0xff, 0x1f, 0x27, 0x57, 0xed, 0x10,
+	0xdd, 0x85, 0xc2, 0x84, 0xb3, 0x24, 0x4e, 0x7f, 0xec, 0x54, 0xf5, 0x5a, 0xd9, 0xdf, 0xcb, 0xf2,
+	0x76, 0x88, 0x9e, 0x83, 0x29, 0x24, 0x96, 0x89, 0x38, 0xda, 0xad, 0xea, 0xb5, 0xff, 0x4f, 0xee,
+	0xb9, 0x6a, 0x50, 0xf7, 0x0f, 0x61, 0x37, 0xc8, 0x38, 0x7e, 0xce, 0x75, 0x5e, 0x82, 0xa9, 0x10,
+	0x54, 0x00, 0xa3, 0x77, 0xd1, 0x6b, 0x59, 0x1a, 0x2a, 0xc1, 0x5e, 0x30, 0xf4, 0xbc, 0x56, 0x10,
+	0x58, 0x3a, 0x2a, 0x43, 0xb1, 0x39, 0xec, 0x77, 0xda, 0x5e, 0x63, 0xd0, 0xb2, 0x76, 0x10, 0x80,
+	0xf9, 0xaa, 0xd1, 0xee, 0xb4, 0x9a, 0xd6, 0xee, 0xc9, 0x37, 0x03, 0xcc, 0xf7, 0x59, 0x0f, 0xf4,
+	0x08, 0x8c, 0xd6, 0x78, 0xca, 0xd0, 0xc1, 0xba, 0x69, 0xde, 0xce, 0xfe, 0x1b, 0x70, 0x34, 0xf4,
+	0x00, 0xa0, 0x21, 0x04, 0x9d, 0x2c, 0x86, 0x34, 0x14, 0xa8, 0xe8, 0x66, 0x36, 0xf5, 0x92, 0xc8,
+	0x06, 0x15, 0x76, 0xa8, 0x90, 0x8e, 0x86, 0x9e, 0x80, 0xd9, 0x4d, 0x24, 0x96, 0x04, 0x1d, 0x28,
+	0x3c, 0xcb, 0x28, 0x5b, 0x88, 0x4d, 0xa2, 0x35, 0x28, 0x06, 0x84, 0x7f, 0x24, 0x03, 0x2c, 0x66,
+	0xa8, 0xa4, 0x0a, 0xde, 0x26, 0x84, 0x2f, 0xed, 0x7d, 0x95, 0xf8, 0x44, 0x24, 0xf3, 0x54, 0xd7,
+	0x85, 0x72, 0x9f, 0x93, 0x90, 0x8e, 0xb1, 0x24, 0xe9, 0x22, 0xd6, 0xf2, 0xaf, 0x53, 0x1f, 0xcf,
+	0xc9, 0x52, 0xd8, 0x05, 0x05, 0x9c, 0xbf, 0x73, 0xb4, 0xa7, 0x3a, 0x7a, 0x08, 0x46, 0xc0, 0xb8,
+	0x44, 0xf9, 0x74, 0x69, 0x6c, 0x5b, 0xbf, 0xe3, 0x1b, 0xdd, 0x67, 0x50, 0xf2, 0xf1, 0xa5, 0xec,
+	0x12, 0x21, 0xf0, 0x84, 0xfc, 0x93, 0x13, 0xa7, 0x50, 0x7a, 0xc3, 0xe8, 0xc2, 0x9b, 0x27, 0x42,
+	0x12, 0x8e, 0x6e, 0xe5, 0x93, 0xe2, 0x4b, 0xe9, 0xb1, 0x85, 0x24, 0xd7, 0x72, 0x53, 0x51, 0x13,
+	0xac, 0x61, 0x1c, 0x62, 0x49, 0xba, 0x24, 0x1a, 0x11, 0x2e, 0xa6, 0x34, 0x46, 0x77, 0x72, 0x87,
+	0x6e, 0x10, 0xc5, 0xb0, 0xb7, 0xe0, 0x8e, 0x86, 0x5e, 0x80, 0xa9, 0xee, 0x02, 0x1d, 0x6e, 0xbc,
+	0x13, 0x7b, 0x33, 0xec, 0x68, 0xe8, 0x18, 0xca, 0x1e, 0x8b, 0x22, 0x2a, 0x2f, 0x78, 0x63, 0x94,
+	0x1a, 0x93, 0x6f, 0x70, 0x70, 0xbd, 0xd8, 0xb2, 0xed, 0x01, 0x8d, 0x88, 0x90, 0x38, 0x8a, 0xb7,
+	0x6f, 0xfb, 0xcc, 0xfa, 0xba, 0xaa, 0xe8, 0xdf, 0x57, 0x15, 0xfd, 0xc7, 0xaa, 0xa2, 0x7f, 0xfe,
+	0x59, 0xd1, 0x46, 0x66, 0xf6, 0x72, 0x4e, 0x7f, 0x0d, 0x00, 0xf5, 0x4a, 0xf5, 0xe8, 0x86, 0x03,
+	0x00, 0x00,
 }
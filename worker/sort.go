@@ -1,6 +1,9 @@
 package worker
 
 import (
+	"bytes"
+	"sort"
+
 	"golang.org/x/net/context"
 
 	"github.com/dgraph-io/dgraph/group"
@@ -20,7 +23,7 @@ func SortOverNetwork(ctx context.Context, q *task.Sort) (*task.SortResult, error
 
 	if groups().ServesGroup(gid) {
 		// No need for a network call, as this should be run from within this instance.
-		return processSort(q)
+		return processSort(ctx, q)
 	}
 
 	// Send this over the network.
@@ -71,7 +74,7 @@ func (w *grpcWorker) Sort(ctx context.Context, s *task.Sort) (*task.SortResult,
 	c := make(chan error, 1)
 	go func() {
 		var err error
-		reply, err = processSort(s)
+		reply, err = processSort(ctx, s)
 		c <- err
 	}()
 
@@ -88,29 +91,51 @@ var (
 	errDone     = x.Errorf("Done processing buckets")
 )
 
-// processSort does either a coarse or a fine sort.
-func processSort(ts *task.Sort) (*task.SortResult, error) {
+// processSort does either a coarse or a fine sort. If ts.Count isn't
+// positive, all the UIDs with a value are returned. If it's negative, the last
+// -ts.Count UIDs are, which are found by going through the buckets backwards.
+func processSort(ctx context.Context, ts *task.Sort) (*task.SortResult, error) {
 	attr := ts.Attr
-	x.AssertTruef(ts.Count > 0,
-		("We do not yet support negative or infinite count with sorting: %s %d. " +
-			"Try flipping order and return first few elements instead."),
-		attr, ts.Count)
-
+	sType := schema.TypeOf(attr)
+	if sType == nil || !sType.IsScalar() {
+		return &emptySortResult, x.Errorf("Cannot sort attribute %s of type object.", attr)
+	}
+	// Strings are indexed by their terms too, but only the exact tokens hold
+	// the whole values. Only values without a language have one, so each UID
+	// is sorted once.
+	exactOnly := sType.(types.Scalar).ID() == types.StringID
+
+	count, offset := int(ts.Count), int(ts.Offset)
+	last := count < 0
+	if last {
+		count, offset = -count, 0
+	}
 	n := len(ts.UidMatrix)
 	out := make([]intersectedList, n)
 	for i := 0; i < n; i++ {
 		// offsets[i] is the offset for i-th posting list. It gets decremented as we
 		// iterate over buckets.
-		out[i].offset = int(ts.Offset)
+		out[i].offset = offset
 		out[i].ulist = &task.List{Uids: []uint64{}}
 	}
 
-	// Iterate over every bucket in TokensTable.
+	// Iterate over every bucket in TokensTable, backwards if the order is
+	// descending, or if the last UIDs are asked for.
 	t := posting.GetTokensTable(attr)
+	if t == nil {
+		return &emptySortResult, x.Errorf("Attribute %s is not indexed.", attr)
+	}
+	first, next := t.GetFirst, t.GetNext
+	if ts.Desc != last {
+		first, next = t.GetLast, t.GetPrev
+	}
 
 BUCKETS:
-	for token := t.GetFirst(); len(token) > 0; token = t.GetNext(token) {
-		err := intersectBucket(ts, attr, token, out)
+	for token := first(); len(token) > 0; token = next(token) {
+		if _, ok := types.ExactValue(token); exactOnly && !ok {
+			continue
+		}
+		err := intersectBucket(ctx, ts, attr, token, count, last, out)
 		switch err {
 		case errDone:
 			break BUCKETS
@@ -123,6 +148,12 @@ BUCKETS:
 
 	r := new(task.SortResult)
 	for _, il := range out {
+		if last {
+			uids := il.ulist.Uids
+			for i, j := 0, len(uids)-1; i < j; i, j = i+1, j-1 {
+				uids[i], uids[j] = uids[j], uids[i]
+			}
+		}
 		r.UidMatrix = append(r.UidMatrix, il.ulist)
 	}
 	return r, nil
@@ -133,8 +164,11 @@ type intersectedList struct {
 	ulist  *task.List
 }
 
-func intersectBucket(ts *task.Sort, attr, token string, out []intersectedList) error {
-	count := int(ts.Count)
+// intersectBucket adds the UIDs of each list in ts.UidMatrix which are in the
+// bucket of token to out, in order, until there are count of them. If last is
+// set, they are added in the reverse order.
+func intersectBucket(ctx context.Context, ts *task.Sort, attr, token string,
+	count int, last bool, out []intersectedList) error {
 	sType := schema.TypeOf(attr)
 	if !sType.IsScalar() {
 		return x.Errorf("Cannot sort attribute %s of type object.", attr)
@@ -165,7 +199,9 @@ func intersectBucket(ts *task.Sort, attr, token string, out []intersectedList) e
 		}
 
 		// Sort results by value before applying offset.
-		sortByValue(attr, result, scalar)
+		if err := sortBucket(ctx, ts, result, scalar, last); err != nil {
+			return err
+		}
 
 		if il.offset > 0 {
 			result.Uids = result.Uids[il.offset:n]
@@ -187,6 +223,10 @@ func intersectBucket(ts *task.Sort, attr, token string, out []intersectedList) e
 		}
 	} // end for loop
 
+	if count <= 0 {
+		// All of the buckets are needed.
+		return errContinue
+	}
 	// Check out[i] sizes for all i.
 	for i := 0; i < len(ts.UidMatrix); i++ { // Iterate over UID lists.
 		if len(out[i].ulist.Uids) < count {
@@ -197,17 +237,116 @@ func intersectBucket(ts *task.Sort, attr, token string, out []intersectedList) e
 	return errDone
 }
 
-// sortByValue fetches values and sort UIDList.
-func sortByValue(attr string, ul *task.List, scalar types.Scalar) error {
-	values := make([]types.Value, len(ul.Uids))
+// sortRow is a UID with its values of the predicates it's sorted by.
+type sortRow struct {
+	uid    uint64
+	values []types.Value
+}
+
+// byValues sorts rows by their values, the later ones breaking ties between
+// the earlier ones, and then by UID. The rows missing a value come last for
+// that predicate. If last is set, the order is reversed.
+type byValues struct {
+	rows []sortRow
+	desc []bool
+	last bool
+}
+
+func (b byValues) Len() int      { return len(b.rows) }
+func (b byValues) Swap(i, j int) { b.rows[i], b.rows[j] = b.rows[j], b.rows[i] }
+func (b byValues) Less(i, j int) bool {
+	if b.last {
+		i, j = j, i
+	}
+	ri, rj := b.rows[i], b.rows[j]
+	for k, desc := range b.desc {
+		vi, vj := ri.values[k], rj.values[k]
+		if vi == nil || vj == nil {
+			if vi == nil && vj == nil {
+				continue
+			}
+			return vj == nil
+		}
+		if vi.Type().ID() != vj.Type().ID() {
+			return vi.Type().ID() < vj.Type().ID()
+		}
+		if types.LessValues(vi, vj) {
+			return !desc
+		}
+		if types.LessValues(vj, vi) {
+			return desc
+		}
+	}
+	return ri.uid < rj.uid
+}
+
+// sortBucket sorts the UIDs of a bucket by their values of ts.Attr, and the
+// ties by their values of the predicates in ts.ThenBy. Those are fetched from
+// the groups serving them.
+func sortBucket(ctx context.Context, ts *task.Sort, ul *task.List,
+	scalar types.Scalar, last bool) error {
+	b := byValues{
+		rows: make([]sortRow, len(ul.Uids)),
+		desc: []bool{ts.Desc},
+		last: last,
+	}
 	for i, uid := range ul.Uids {
-		val, err := fetchValue(uid, attr, scalar)
+		val, err := fetchValue(uid, ts.Attr, scalar)
 		if err != nil {
 			return err
 		}
-		values[i] = val
+		b.rows[i] = sortRow{uid: uid, values: []types.Value{val}}
+	}
+	if len(ul.Uids) > 1 {
+		for _, o := range ts.ThenBy {
+			vals, err := fetchValues(ctx, o.Attr, ul.Uids)
+			if err != nil {
+				return err
+			}
+			for i := range b.rows {
+				b.rows[i].values = append(b.rows[i].values, vals[i])
+			}
+			b.desc = append(b.desc, o.Desc)
+		}
+	}
+	sort.Sort(b)
+	for i, r := range b.rows {
+		ul.Uids[i] = r.uid
+	}
+	return nil
+}
+
+// fetchValues gets the values of attr for the given UIDs, converted to its
+// type in the schema. The UIDs without one get nil.
+func fetchValues(ctx context.Context, attr string, uids []uint64) ([]types.Value, error) {
+	result, err := ProcessTaskOverNetwork(ctx, &task.Query{
+		Attr: attr,
+		Uids: uids,
+	})
+	if err != nil {
+		return nil, err
+	}
+	scalar, isScalar := schema.TypeOf(attr).(types.Scalar)
+	out := make([]types.Value, len(uids))
+	for i, tv := range result.Values {
+		if i >= len(uids) || bytes.Equal(tv.Val, nil) {
+			continue
+		}
+		val := types.ValueForType(types.TypeID(tv.ValType))
+		if val == nil {
+			return nil, x.Errorf("Invalid type: %v", tv.ValType)
+		}
+		if err := val.UnmarshalBinary(tv.Val); err != nil {
+			return nil, err
+		}
+		if isScalar {
+			if cv, err := scalar.Convert(val); err == nil {
+				val = cv
+			}
+		}
+		out[i] = val
 	}
-	return scalar.Sort(values, ul)
+	return out, nil
 }
 
 // fetchValue gets the value for a given UID.
@@ -17,6 +17,7 @@
 package worker
 
 import (
+	"strconv"
 	"strings"
 
 	"golang.org/x/net/context"
@@ -25,6 +26,7 @@ import (
 	"github.com/dgraph-io/dgraph/geo"
 	"github.com/dgraph-io/dgraph/group"
 	"github.com/dgraph-io/dgraph/posting"
+	"github.com/dgraph-io/dgraph/schema"
 	"github.com/dgraph-io/dgraph/task"
 	"github.com/dgraph-io/dgraph/types"
 	"github.com/dgraph-io/dgraph/x"
@@ -78,9 +80,24 @@ func processTask(q *task.Query) (*task.Result, error) {
 	attr := q.Attr
 
 	useFunc := len(q.SrcFunc) != 0
+	if attr == posting.PredicateAttr && !useFunc {
+		return predicatesResult(q.Uids), nil
+	}
+	if useFunc && q.DoCount {
+		// A function on the number of edges, like gt(count(friend), 10).
+		return processCountFunc(q)
+	}
+	if useFunc && q.SrcFunc[0] == "has" {
+		return processHas(q)
+	}
+	if useFunc && q.SrcFunc[0] == "uid_in" {
+		return processUidIn(q)
+	}
 	var n int
 	var tokens []string
 	var geoQuery *geo.QueryData
+	var ineqQuery *inequalityQuery
+	var txtQuery *textQuery
 	var err error
 	var intersectDest bool
 	if useFunc {
@@ -91,6 +108,42 @@ func processTask(q *task.Query) (*task.Result, error) {
 			if err != nil {
 				return nil, err
 			}
+		} else if isInequalityFunc(q.SrcFunc[0]) {
+			// For inequality functions, we get the bounds to check the values of
+			// the boundary buckets.
+			tokens, ineqQuery, err = getInequalityTokens(attr, q.SrcFunc)
+			if err != nil {
+				return nil, err
+			}
+		} else if IsTextFunc(q.SrcFunc[0]) {
+			tokens, txtQuery, err = getTextTokens(attr, q.SrcFunc)
+			if err != nil {
+				return nil, err
+			}
+		} else if isStringFunc(q.SrcFunc[0]) {
+			strQuery, err := parseStringQuery(q.SrcFunc)
+			if err != nil {
+				return nil, err
+			}
+			if !schema.IsIndexed(attr) || strQuery.needsScan() {
+				// Without an index, or a literal prefix to seek to in it, we only
+				// check the values of the given UIDs, instead of walking every
+				// value of attr.
+				if len(q.Uids) == 0 && !schema.IsIndexed(attr) {
+					return nil, x.Errorf("Attribute %s is not indexed", attr)
+				}
+				if len(q.Uids) == 0 {
+					return nil, x.Errorf("Function %s without a literal prefix "+
+						"can only be used as a filter", strQuery.fn)
+				}
+				return &task.Result{
+					UidMatrix: []*task.List{strQuery.filterValues(attr, q.Uids)},
+				}, nil
+			}
+			tokens, err = strQuery.getTokens(attr)
+			if err != nil {
+				return nil, err
+			}
 		} else {
 			tokens, err = getTokens(q.SrcFunc)
 			if err != nil {
@@ -116,8 +169,9 @@ func processTask(q *task.Query) (*task.Result, error) {
 		defer decr()
 
 		// If a posting list contains a value, we store that or else we store a nil
-		// byte so that processing is consistent later.
-		vbytes, vtype, err := pl.Value()
+		// byte so that processing is consistent later. The value is taken in
+		// the first of the languages asked for which it has.
+		vbytes, vtype, err := pl.ValueFor(q.Langs)
 
 		newValue := &task.Value{ValType: uint32(vtype)}
 		if err == nil {
@@ -142,7 +196,23 @@ func processTask(q *task.Query) (*task.Result, error) {
 		if useFunc && len(q.Uids) > 0 {
 			opts.Intersect = &task.List{Uids: q.Uids}
 		}
-		out.UidMatrix = append(out.UidMatrix, pl.Uids(opts))
+		ul := pl.Uids(opts)
+		if ineqQuery != nil && ineqQuery.isBoundary(tokens[i]) {
+			ineqQuery.filterBoundary(attr, ul)
+		}
+		if txtQuery != nil {
+			txtQuery.docFreq[i] = pl.Length(0)
+		}
+		if q.FacetParam != nil && !useFunc {
+			out.FacetMatrix = append(out.FacetMatrix, facetsList(pl, ul.Uids, q.Langs, q.FacetParam))
+		}
+		out.UidMatrix = append(out.UidMatrix, ul)
+	}
+
+	if txtQuery != nil && !q.DoCount {
+		// Scores are only needed to rank the results at the root, not when
+		// filtering the given UIDs.
+		txtQuery.apply(attr, &out, len(q.Uids) == 0)
 	}
 
 	// If geo filter, do value check for correctness.
@@ -173,6 +243,62 @@ func processTask(q *task.Query) (*task.Result, error) {
 	return &out, nil
 }
 
+// processHas returns the UIDs which have a non-empty posting list for q.Attr,
+// for has(attr). As a filter, only q.Uids are checked.
+func processHas(q *task.Query) (*task.Result, error) {
+	if len(q.SrcFunc) != 1 {
+		return nil, x.Errorf("Function has requires 1 argument, but got %d",
+			len(q.SrcFunc))
+	}
+	if len(q.Uids) == 0 {
+		ul, err := posting.UidsWithAttr(q.Attr)
+		if err != nil {
+			return nil, err
+		}
+		return &task.Result{UidMatrix: []*task.List{ul}}, nil
+	}
+	ul := new(task.List)
+	for _, uid := range q.Uids {
+		pl, decr := posting.GetOrCreate(posting.Key(uid, q.Attr))
+		if pl.Length(0) > 0 {
+			ul.Uids = append(ul.Uids, uid)
+		}
+		decr()
+	}
+	return &task.Result{UidMatrix: []*task.List{ul}}, nil
+}
+
+// processUidIn returns the UIDs among q.Uids with a q.Attr edge pointing to
+// any of the UIDs given in q.SrcFunc, for uid_in(attr, 0x01). The posting
+// list of each UID is intersected with them, by seeking to each of them.
+func processUidIn(q *task.Query) (*task.Result, error) {
+	if len(q.Uids) == 0 {
+		return nil, x.Errorf("Function uid_in can only be used as a filter")
+	}
+	targets := new(task.List)
+	for _, arg := range q.SrcFunc[1:] {
+		uid, err := strconv.ParseUint(arg, 0, 64)
+		if err != nil {
+			return nil, x.Wrapf(err, "Invalid UID %q for uid_in", arg)
+		}
+		targets.Uids = append(targets.Uids, uid)
+	}
+	algo.Sort(targets)
+
+	ul := new(task.List)
+	if len(targets.Uids) == 0 {
+		return &task.Result{UidMatrix: []*task.List{ul}}, nil
+	}
+	for _, uid := range q.Uids {
+		pl, decr := posting.GetOrCreate(posting.Key(uid, q.Attr))
+		if pl.HasAnyUid(targets.Uids) {
+			ul.Uids = append(ul.Uids, uid)
+		}
+		decr()
+	}
+	return &task.Result{UidMatrix: []*task.List{ul}}, nil
+}
+
 // ServeTask is used to respond to a query.
 func (w *grpcWorker) ServeTask(ctx context.Context, q *task.Query) (*task.Result, error) {
 	if ctx.Err() != nil {
@@ -183,7 +309,8 @@ func (w *grpcWorker) ServeTask(ctx context.Context, q *task.Query) (*task.Result
 	x.Trace(ctx, "Attribute: %q NumUids: %v groupId: %v ServeTask", q.Attr, len(q.Uids), gid)
 
 	var reply *task.Result
-	x.AssertTruef(groups().ServesGroup(gid),
+	// Every group records the predicates it serves.
+	x.AssertTruef(groups().ServesGroup(gid) || q.Attr == posting.PredicateAttr,
 		"attr: %q groupId: %v Request sent to wrong server.", q.Attr, gid)
 
 	c := make(chan error, 1)
@@ -0,0 +1,159 @@
+/*
+ * Copyright 2016 Dgraph Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * 		http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package worker
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/dgraph-io/dgraph/task"
+	"github.com/dgraph-io/dgraph/x"
+)
+
+// The timestamps of the transactions are handed out by the leader of group 0,
+// so that they are ordered across the cluster. The leader leases a range of
+// them at a time through RAFT, and a new leader only hands out the ones above
+// the last lease, so they keep growing even if the leader changes. They
+// follow the wall clock of the leader in nanoseconds, so that the age of a
+// transaction can be told from its start timestamp: txnTimeoutTs relies on it.
+
+// tsLease is how many timestamps are leased at a time.
+const tsLease = uint64(time.Second)
+
+var errLeaseTaken = fmt.Errorf("Timestamps were leased by another leader")
+
+type tsOracle struct {
+	sync.Mutex
+	leased uint64 // The timestamps below it may have been handed out. Set by RAFT.
+	next   uint64 // The next one to hand out, on the leader.
+	end    uint64 // The end of the range leased by this node.
+}
+
+// applyLease raises the leased timestamps, if no other lease was taken since
+// the one l starts from.
+func (o *tsOracle) applyLease(l *task.Lease) error {
+	if atomic.LoadUint64(&o.leased) != l.From || l.To <= l.From {
+		return errLeaseTaken
+	}
+	atomic.StoreUint64(&o.leased, l.To)
+	return nil
+}
+
+// timestamps returns num timestamps, which are greater than the ones handed
+// out before by any leader of group 0.
+func (n *node) timestamps(ctx context.Context, num uint64) (*task.List, error) {
+	if !n.AmLeader() {
+		return &emptyUIDList, x.Errorf("Timestamps are only handed out by the leader of group 0.")
+	}
+	if num == 0 {
+		return &emptyUIDList, x.Errorf("Nothing to be handed out")
+	}
+
+	o := &n.oracle
+	o.Lock()
+	defer o.Unlock()
+	if now := uint64(time.Now().UnixNano()); now > o.next {
+		o.next = now
+	}
+	for o.next+num > o.end {
+		from := atomic.LoadUint64(&o.leased)
+		to := from
+		if o.next > to {
+			to = o.next
+		}
+		to += num + tsLease
+		err := n.ProposeAndWait(ctx, &task.Proposal{Lease: &task.Lease{From: from, To: to}})
+		if err == errLeaseTaken {
+			continue
+		}
+		if err != nil {
+			return &emptyUIDList, err
+		}
+		if o.next < from {
+			o.next = from
+		}
+		o.end = to
+	}
+
+	out := new(task.List)
+	for i := uint64(0); i < num; i++ {
+		out.Uids = append(out.Uids, o.next)
+		o.next++
+	}
+	return out, nil
+}
+
+// timestamp returns a timestamp for a transaction, from the leader of group 0.
+func timestamp(ctx context.Context) (uint64, error) {
+	var ts *task.List
+	var err error
+	if groups().ServesGroup(0) && groups().Node(0).AmLeader() {
+		ts, err = groups().Node(0).timestamps(ctx, 1)
+	} else {
+		_, addr := groups().Leader(0)
+		p := pools().get(addr)
+		conn, cerr := p.Get()
+		if cerr != nil {
+			x.TraceError(ctx, x.Wrapf(cerr, "Error while retrieving connection"))
+			return 0, cerr
+		}
+		defer p.Put(conn)
+
+		c := NewWorkerClient(conn)
+		ts, err = c.Timestamps(ctx, &task.Num{Val: 1})
+	}
+	if err != nil {
+		x.TraceError(ctx, x.Wrapf(err, "Error while getting a timestamp"))
+		return 0, err
+	}
+	x.AssertTruef(len(ts.Uids) == 1, "Requested one timestamp, got %d", len(ts.Uids))
+	return ts.Uids[0], nil
+}
+
+// Timestamps is used to get timestamps from the leader of group 0.
+func (w *grpcWorker) Timestamps(ctx context.Context, num *task.Num) (*task.List, error) {
+	if ctx.Err() != nil {
+		return &emptyUIDList, ctx.Err()
+	}
+
+	if !groups().ServesGroup(0) {
+		return &emptyUIDList, x.Errorf("This server doesn't serve group id: 0")
+	}
+	if num.Val <= 0 {
+		return &emptyUIDList, x.Errorf("Nothing to be handed out")
+	}
+	type result struct {
+		ts  *task.List
+		err error
+	}
+	c := make(chan result, 1)
+	go func() {
+		ts, err := groups().Node(0).timestamps(ctx, uint64(num.Val))
+		c <- result{ts, err}
+	}()
+
+	select {
+	case <-ctx.Done():
+		return &emptyUIDList, ctx.Err()
+	case r := <-c:
+		return r.ts, r.err
+	}
+}
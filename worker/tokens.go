@@ -1,7 +1,16 @@
 package worker
 
 import (
+	"regexp"
+	"regexp/syntax"
+	"strings"
+
+	"github.com/dgraph-io/dgraph/algo"
+	"github.com/dgraph-io/dgraph/posting"
+	"github.com/dgraph-io/dgraph/schema"
+	"github.com/dgraph-io/dgraph/task"
 	"github.com/dgraph-io/dgraph/tok"
+	"github.com/dgraph-io/dgraph/types"
 	"github.com/dgraph-io/dgraph/x"
 )
 
@@ -22,3 +31,308 @@ func getStringTokens(term string) ([]string, error) {
 	defer tokenizer.Destroy()
 	return tokenizer.Tokens(), nil
 }
+
+// isInequalityFunc returns true if name is one of the functions comparing
+// values using the sortable index, like ge(age, 20).
+func isInequalityFunc(name string) bool {
+	switch name {
+	case "ge", "gt", "le", "lt", "between":
+		return true
+	}
+	return false
+}
+
+// inequalityQuery holds the bounds of an inequality function. A nil bound
+// means that side of the range is open.
+type inequalityQuery struct {
+	fn     string
+	scalar types.Scalar
+	lo, hi types.Value
+	// Tokens for the bounds, which are the buckets holding the bound values.
+	loToken, hiToken string
+}
+
+// parseBound converts the function argument to the schema type of attr, and
+// returns the index token for it.
+func parseBound(attr string, scalar types.Scalar, arg string) (types.Value, string, error) {
+	s := types.String(arg)
+	v, err := scalar.Convert(&s)
+	if err != nil {
+		return nil, "", x.Wrapf(err, "Cannot convert %q to type %s", arg, scalar)
+	}
+	tokens, err := posting.IndexTokens(attr, "", v)
+	if err != nil {
+		return nil, "", err
+	}
+	if len(tokens) != 1 {
+		return nil, "", x.Errorf("Expected one index token for %q, got %d",
+			arg, len(tokens))
+	}
+	return v, tokens[0], nil
+}
+
+// getInequalityTokens returns the index tokens in the range given by an
+// inequality function like ge(age, 20) or between(dob, 1910-01-01, 1920-01-01).
+func getInequalityTokens(attr string, funcArgs []string) ([]string,
+	*inequalityQuery, error) {
+	fn := funcArgs[0]
+	nargs := 2
+	if fn == "between" {
+		nargs = 3
+	}
+	if len(funcArgs) != nargs {
+		return nil, nil, x.Errorf("Function %s requires %d arguments, but got %d",
+			fn, nargs, len(funcArgs)-1)
+	}
+	if !schema.IsIndexed(attr) {
+		return nil, nil, x.Errorf("Attribute %s is not indexed", attr)
+	}
+	scalar, ok := schema.TypeOf(attr).(types.Scalar)
+	if !ok {
+		return nil, nil, x.Errorf("Attribute %s is not a scalar", attr)
+	}
+	switch scalar.ID() {
+	case types.Int32ID, types.FloatID, types.DateID, types.DateTimeID:
+	default:
+		return nil, nil, x.Errorf("Function %s is not supported for type %s",
+			fn, scalar)
+	}
+
+	q := &inequalityQuery{fn: fn, scalar: scalar}
+	var err error
+	switch fn {
+	case "ge", "gt":
+		q.lo, q.loToken, err = parseBound(attr, scalar, funcArgs[1])
+	case "le", "lt":
+		q.hi, q.hiToken, err = parseBound(attr, scalar, funcArgs[1])
+	case "between":
+		if q.lo, q.loToken, err = parseBound(attr, scalar, funcArgs[1]); err != nil {
+			return nil, nil, err
+		}
+		q.hi, q.hiToken, err = parseBound(attr, scalar, funcArgs[2])
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// The tokens are order preserving, so we can walk them in order, starting
+	// from the bucket of the lower bound.
+	t := posting.GetTokensTable(attr)
+	if t == nil {
+		return nil, nil, x.Errorf("Attribute %s is not indexed", attr)
+	}
+	var tokens []string
+	token := t.GetFirst()
+	if q.lo != nil {
+		token = q.loToken
+		if t.Get(token) < 0 {
+			token = t.GetNext(token)
+		}
+	}
+	for ; len(token) > 0; token = t.GetNext(token) {
+		if q.hi != nil && token > q.hiToken {
+			break
+		}
+		tokens = append(tokens, token)
+	}
+	return tokens, q, nil
+}
+
+// isBoundary returns true if the bucket for token can have values outside of
+// the range, in which case the values need to be checked.
+func (q *inequalityQuery) isBoundary(token string) bool {
+	return (q.lo != nil && token == q.loToken) ||
+		(q.hi != nil && token == q.hiToken)
+}
+
+// matches returns true if v satisfies the inequality.
+func (q *inequalityQuery) matches(v types.Value) bool {
+	if q.lo != nil {
+		less, err := q.scalar.Less(v, q.lo)
+		if err != nil || less {
+			return false
+		}
+		if q.fn == "gt" {
+			if less, err = q.scalar.Less(q.lo, v); err != nil || !less {
+				return false
+			}
+		}
+	}
+	if q.hi != nil {
+		less, err := q.scalar.Less(q.hi, v)
+		if err != nil || less {
+			return false
+		}
+		if q.fn == "lt" {
+			if less, err = q.scalar.Less(v, q.hi); err != nil || !less {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// filterBoundary removes the UIDs in ul whose values for attr don't satisfy
+// the inequality.
+func (q *inequalityQuery) filterBoundary(attr string, ul *task.List) {
+	algo.ApplyFilter(ul, func(uid uint64, i int) bool {
+		v, err := fetchValue(uid, attr, q.scalar)
+		return err == nil && q.matches(v)
+	})
+}
+
+// isStringFunc returns true if name is one of the functions matching whole
+// string values, like eq(name, "Steven").
+func isStringFunc(name string) bool {
+	switch name {
+	case "eq", "prefix", "regexp":
+		return true
+	}
+	return false
+}
+
+// stringQuery holds a string matching function.
+type stringQuery struct {
+	fn  string
+	arg string
+	re  *regexp.Regexp
+}
+
+// parseRegex compiles a regular expression given as /pattern/flags. The only
+// flag supported is i, for case insensitive matching.
+func parseRegex(arg string) (*regexp.Regexp, error) {
+	end := strings.LastIndex(arg, "/")
+	if len(arg) < 2 || arg[0] != '/' || end == 0 {
+		return nil, x.Errorf("Regular expression should look like /pattern/: %s", arg)
+	}
+	pattern := arg[1:end]
+	switch flags := arg[end+1:]; flags {
+	case "":
+	case "i":
+		pattern = "(?i)" + pattern
+	default:
+		return nil, x.Errorf("Invalid flags %q for regular expression %s", flags, arg)
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, x.Wrapf(err, "Invalid regular expression %s", arg)
+	}
+	return re, nil
+}
+
+// regexPrefix returns the literal prefix of all the strings matched by re.
+// Unless re is anchored at the start of the text, a match can begin anywhere,
+// and there is no such prefix.
+func regexPrefix(re *regexp.Regexp) string {
+	parsed, err := syntax.Parse(re.String(), syntax.Perl)
+	if err != nil {
+		return ""
+	}
+	if parsed.Op != syntax.OpConcat || len(parsed.Sub) < 2 ||
+		parsed.Sub[0].Op != syntax.OpBeginText {
+		return ""
+	}
+	lit := parsed.Sub[1]
+	if lit.Op != syntax.OpLiteral || lit.Flags&syntax.FoldCase != 0 {
+		return ""
+	}
+	return string(lit.Rune)
+}
+
+// parseStringQuery parses the arguments of a string matching function.
+func parseStringQuery(funcArgs []string) (*stringQuery, error) {
+	fn := funcArgs[0]
+	if len(funcArgs) != 2 {
+		return nil, x.Errorf("Function %s requires 2 arguments, but got %d",
+			fn, len(funcArgs))
+	}
+	q := &stringQuery{fn: fn, arg: funcArgs[1]}
+	if fn == "regexp" {
+		var err error
+		if q.re, err = parseRegex(q.arg); err != nil {
+			return nil, err
+		}
+	}
+	return q, nil
+}
+
+// matches returns true if s satisfies the function.
+func (q *stringQuery) matches(s string) bool {
+	switch q.fn {
+	case "eq":
+		return s == q.arg
+	case "prefix":
+		return strings.HasPrefix(s, q.arg)
+	case "regexp":
+		return q.re.MatchString(s)
+	}
+	return false
+}
+
+// prefix returns the literal prefix of all the values satisfying the function.
+func (q *stringQuery) prefix() string {
+	switch q.fn {
+	case "prefix":
+		return q.arg
+	case "regexp":
+		return regexPrefix(q.re)
+	}
+	return q.arg
+}
+
+// needsScan returns true if the function would have to check every exact
+// token of the index, as it has no literal prefix to seek to.
+func (q *stringQuery) needsScan() bool {
+	return q.fn != "eq" && len(q.prefix()) == 0
+}
+
+// getTokens returns the exact tokens of attr whose values satisfy the
+// function. The tokens are sorted, so all the values starting with the
+// literal prefix of the function are found with one range scan.
+func (q *stringQuery) getTokens(attr string) ([]string, error) {
+	if scalar, ok := schema.TypeOf(attr).(types.Scalar); !ok ||
+		scalar.ID() != types.StringID {
+		return nil, x.Errorf("Function %s is only supported for string attributes",
+			q.fn)
+	}
+	if q.fn == "eq" {
+		return []string{types.ExactToken(q.arg)}, nil
+	}
+	t := posting.GetTokensTable(attr)
+	if t == nil {
+		return nil, x.Errorf("Attribute %s is not indexed", attr)
+	}
+	prefix := q.prefix()
+	var tokens []string
+	token := types.ExactToken(prefix)
+	if t.Get(token) < 0 {
+		token = t.GetNext(token)
+	}
+	for ; len(token) > 0; token = t.GetNext(token) {
+		val, ok := types.ExactValue(token)
+		if !ok || !strings.HasPrefix(val, prefix) {
+			break
+		}
+		if q.matches(val) {
+			tokens = append(tokens, token)
+		}
+	}
+	return tokens, nil
+}
+
+// filterValues returns the UIDs in uids whose stored values for attr satisfy
+// the function. This is used when attr doesn't have an index.
+func (q *stringQuery) filterValues(attr string, uids []uint64) *task.List {
+	out := new(task.List)
+	stype, _ := types.TypeForName("string")
+	for _, uid := range uids {
+		v, err := fetchValue(uid, attr, stype.(types.Scalar))
+		if err != nil {
+			continue
+		}
+		if q.matches(string(*v.(*types.String))) {
+			out.Uids = append(out.Uids, uid)
+		}
+	}
+	return out
+}
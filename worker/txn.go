@@ -0,0 +1,645 @@
+/*
+ * Copyright 2016 Dgraph Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * 		http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package worker
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/dgraph-io/dgraph/algo"
+	"github.com/dgraph-io/dgraph/group"
+	"github.com/dgraph-io/dgraph/posting"
+	"github.com/dgraph-io/dgraph/schema"
+	"github.com/dgraph-io/dgraph/task"
+	"github.com/dgraph-io/dgraph/types"
+	"github.com/dgraph-io/dgraph/x"
+)
+
+// The mutations sent over the network are applied in transactions, in two
+// phases. A transaction gets a start timestamp from group 0, and its
+// mutations are first prewritten in each of the groups they belong to: the
+// keys they touch are locked, after checking that no other transaction holds
+// them or has committed them since the start, and the mutations are put
+// aside, in the posting lists of pendingAttr too so that they outlive a
+// restart. Once all the groups have prewritten, the transaction gets a commit
+// timestamp, and the decision is written to its primary record, in the group
+// of txnAttr. That is the point at which it is committed. Each group then
+// applies the mutations and releases the locks. If any of them fails, the
+// transaction is aborted instead. All of it goes through RAFT, so that all
+// the replicas of a group agree on the locks and the records.
+//
+// A transaction whose coordinator went away before deciding it in all the
+// groups is resolved by the leaders of the groups it is pending in, once it
+// is older than txnTimeout: they abort it in its primary record unless it
+// was decided there already, and then follow the record.
+//
+// A transaction can also carry the lookups it read before writing, like the
+// query of an upsert. Their keys are checked against the writes of the others
+// on prewrite: the transaction is aborted if any of them was written since it
+// started, or is about to be written by a pending transaction. The writers of
+// those keys are aborted in turn while it is pending. As the keys read stand
+// for all the values of a term, the writes are tracked by the index keys of
+// their values, and by the attribute as a whole.
+
+// txnTimeout is how long a transaction can be pending before it is resolved
+// from its primary record by the groups it is pending in.
+const txnTimeout = time.Minute
+
+// txnTimeoutTs is txnTimeout as a difference of timestamps, which the oracle
+// hands out in wall-clock nanoseconds.
+const txnTimeoutTs = uint64(txnTimeout / time.Nanosecond)
+
+// txnAttr holds the primary records of the transactions: the commit
+// timestamp of each one under its start timestamp, or zero if it was aborted.
+const txnAttr = "_txn_"
+
+var (
+	errConflict   = fmt.Errorf("Transaction aborted because of a conflict with another one")
+	errTxnOld     = fmt.Errorf("Transaction aborted because it started too long ago")
+	errTxnDecided = fmt.Errorf("Transaction was decided otherwise already")
+)
+
+// pendingAttr holds the mutations prewritten in group gid, under the start
+// timestamp of their transaction, until it is decided.
+func pendingAttr(gid uint32) string {
+	return fmt.Sprintf("%s%d", txnAttr, gid)
+}
+
+// txnKeys returns the keys of the posting lists the mutations write to, for
+// detecting conflicts.
+func txnKeys(m *task.Mutations) []string {
+	seen := make(map[string]bool)
+	var keys []string
+	for _, edges := range [][]*task.DirectedEdge{m.Set, m.Del} {
+		for _, edge := range edges {
+			key := string(posting.Key(edge.Entity, edge.Attr))
+			if !seen[key] {
+				seen[key] = true
+				keys = append(keys, key)
+			}
+		}
+	}
+	return keys
+}
+
+// attrKey stands for all the keys of attr, for the lookups whose keys can't
+// be told up front, like has() or inequalities.
+func attrKey(attr string) string {
+	return attr + "|"
+}
+
+// starKey stands for all the index keys of attr, for the writes whose tokens
+// can't be told up front, like S P * deletes.
+func starKey(attr string) string {
+	return attr + "|*"
+}
+
+// lookupKeys returns the keys read by a lookup of a transaction.
+func lookupKeys(q *task.Query) []string {
+	var tokens []string
+	if len(q.SrcFunc) == 2 {
+		switch strings.ToLower(q.SrcFunc[0]) {
+		case "anyof", "allof":
+			tokens, _ = getTokens(q.SrcFunc)
+		case "eq":
+			tokens = []string{types.ExactToken(q.SrcFunc[1])}
+		}
+	}
+	if len(tokens) == 0 {
+		return []string{attrKey(q.Attr)}
+	}
+	keys := []string{starKey(q.Attr)}
+	for _, token := range tokens {
+		keys = append(keys, string(types.IndexKey(q.Attr, token)))
+	}
+	return keys
+}
+
+// readKeys returns the keys read by the lookups of the transaction.
+func readKeys(m *task.Mutations) []string {
+	var keys []string
+	for _, q := range m.Lookups {
+		keys = append(keys, lookupKeys(q)...)
+	}
+	return keys
+}
+
+// writtenKeys returns the keys of the lookups which the mutations change the
+// results of: attrKey for each attribute written, and the index keys of the
+// values written to the indexed ones.
+func writtenKeys(m *task.Mutations) []string {
+	seen := make(map[string]bool)
+	var keys []string
+	add := func(key string) {
+		if !seen[key] {
+			seen[key] = true
+			keys = append(keys, key)
+		}
+	}
+	for _, edges := range [][]*task.DirectedEdge{m.Set, m.Del} {
+		for _, edge := range edges {
+			add(attrKey(edge.Attr))
+			if edge.Value == nil || !schema.IsIndexed(edge.Attr) {
+				continue
+			}
+			var tokens []string
+			if string(edge.Value) != x.Star {
+				p := types.ValueForType(types.TypeID(edge.ValueType))
+				if p != nil && p.UnmarshalBinary(edge.Value) == nil {
+					tokens, _ = posting.IndexTokens(edge.Attr, edge.Lang, p)
+				}
+			}
+			if len(tokens) == 0 {
+				add(starKey(edge.Attr))
+			}
+			for _, token := range tokens {
+				add(string(types.IndexKey(edge.Attr, token)))
+			}
+		}
+	}
+	return keys
+}
+
+type pendingTxn struct {
+	m      *task.Mutations
+	keys   []string
+	reads  []string
+	writes []string
+}
+
+// txnTable holds the state of the transactions in a group: the locked keys,
+// the keys read and written by the pending ones, the prewritten mutations,
+// and the commit timestamps of the keys written lately. It is only changed
+// while applying the entries of the RAFT log, one at a time, so it is the same
+// on all the replicas.
+type txnTable struct {
+	sync.RWMutex
+	locks      map[string]uint64 // Key to the start timestamp of the transaction holding it.
+	commits    map[string]uint64 // Key to the commit timestamp of the last write to it.
+	readers    map[string]map[uint64]bool
+	writers    map[string]map[uint64]bool
+	pending    map[uint64]*pendingTxn
+	lastCommit uint64
+	lastPrune  uint64
+}
+
+func newTxnTable() *txnTable {
+	return &txnTable{
+		locks:   make(map[string]uint64),
+		commits: make(map[string]uint64),
+		readers: make(map[string]map[uint64]bool),
+		writers: make(map[string]map[uint64]bool),
+		pending: make(map[uint64]*pendingTxn),
+	}
+}
+
+// prewrite locks the keys touched by the mutations of the transaction
+// starting at m.StartTs, and keeps them until it is committed or aborted.
+// Prewriting it again does nothing.
+func (t *txnTable) prewrite(m *task.Mutations) error {
+	x.AssertTrue(m.StartTs > 0)
+	t.Lock()
+	defer t.Unlock()
+	if _, ok := t.pending[m.StartTs]; ok {
+		return nil
+	}
+	if m.StartTs+txnTimeoutTs < t.lastCommit {
+		// The commits it might conflict with may have been pruned.
+		return errTxnOld
+	}
+	keys := txnKeys(m)
+	for _, key := range keys {
+		if _, ok := t.locks[key]; ok || t.commits[key] > m.StartTs {
+			return errConflict
+		}
+	}
+	reads := readKeys(m)
+	for _, key := range reads {
+		if t.held(t.writers, key, m.StartTs) || t.commits[key] > m.StartTs {
+			return errConflict
+		}
+	}
+	writes := writtenKeys(m)
+	for _, key := range writes {
+		if t.held(t.readers, key, m.StartTs) {
+			return errConflict
+		}
+	}
+	t.hold(m, keys, reads, writes)
+	return nil
+}
+
+// restore puts back the mutations of a transaction prewritten before a
+// restart, which were checked for conflicts then.
+func (t *txnTable) restore(m *task.Mutations) {
+	t.Lock()
+	defer t.Unlock()
+	if _, ok := t.pending[m.StartTs]; !ok {
+		t.hold(m, txnKeys(m), readKeys(m), writtenKeys(m))
+	}
+}
+
+func (t *txnTable) hold(m *task.Mutations, keys, reads, writes []string) {
+	for _, key := range keys {
+		t.locks[key] = m.StartTs
+	}
+	hold(t.readers, reads, m.StartTs)
+	hold(t.writers, writes, m.StartTs)
+	t.pending[m.StartTs] = &pendingTxn{m: m, keys: keys, reads: reads, writes: writes}
+}
+
+// held returns true if a transaction other than the one starting at startTs
+// holds key in holders.
+func (t *txnTable) held(holders map[string]map[uint64]bool, key string,
+	startTs uint64) bool {
+	for ts := range holders[key] {
+		if ts != startTs {
+			return true
+		}
+	}
+	return false
+}
+
+func hold(holders map[string]map[uint64]bool, keys []string, startTs uint64) {
+	for _, key := range keys {
+		if holders[key] == nil {
+			holders[key] = make(map[uint64]bool)
+		}
+		holders[key][startTs] = true
+	}
+}
+
+func unhold(holders map[string]map[uint64]bool, keys []string, startTs uint64) {
+	for _, key := range keys {
+		delete(holders[key], startTs)
+		if len(holders[key]) == 0 {
+			delete(holders, key)
+		}
+	}
+}
+
+// checkUnlocked returns an error if the mutations, which aren't part of a
+// transaction, touch any of the keys locked by one.
+func (t *txnTable) checkUnlocked(m *task.Mutations) error {
+	t.RLock()
+	defer t.RUnlock()
+	for _, key := range txnKeys(m) {
+		if _, ok := t.locks[key]; ok {
+			return errConflict
+		}
+	}
+	return nil
+}
+
+// release drops the prewritten mutations of the transaction starting at
+// startTs, and the locks it holds.
+func (t *txnTable) release(startTs uint64) *pendingTxn {
+	p, ok := t.pending[startTs]
+	if !ok {
+		return nil
+	}
+	delete(t.pending, startTs)
+	for _, key := range p.keys {
+		delete(t.locks, key)
+	}
+	unhold(t.readers, p.reads, startTs)
+	unhold(t.writers, p.writes, startTs)
+	return p
+}
+
+// decide commits or aborts a prewritten transaction. The mutations to apply
+// are returned on a commit. Deciding a transaction which isn't pending does
+// nothing, as it was decided already, or not prewritten in this group.
+func (t *txnTable) decide(txn *task.Txn) (*task.Mutations, error) {
+	t.Lock()
+	defer t.Unlock()
+	p := t.release(txn.StartTs)
+	if txn.CommitTs == 0 || p == nil {
+		return nil, nil
+	}
+	for _, key := range p.keys {
+		t.commits[key] = txn.CommitTs
+	}
+	for _, key := range p.writes {
+		t.commits[key] = txn.CommitTs
+	}
+	if txn.CommitTs > t.lastCommit {
+		t.lastCommit = txn.CommitTs
+	}
+	if t.lastCommit-t.lastPrune > txnTimeoutTs {
+		// Only the commits after the start of a transaction can conflict
+		// with it, and the older transactions are aborted anyway.
+		for key, ts := range t.commits {
+			if ts+txnTimeoutTs < t.lastCommit {
+				delete(t.commits, key)
+			}
+		}
+		t.lastPrune = t.lastCommit
+	}
+	return p.m, nil
+}
+
+// orphans returns the start timestamps of the transactions pending for longer
+// than txnTimeout, as of the timestamp now.
+func (t *txnTable) orphans(now uint64) []uint64 {
+	t.RLock()
+	defer t.RUnlock()
+	out := new(task.List)
+	for startTs := range t.pending {
+		if startTs+txnTimeoutTs < now {
+			out.Uids = append(out.Uids, startTs)
+		}
+	}
+	algo.Sort(out)
+	return out.Uids
+}
+
+// savePending keeps the mutations prewritten in group gid in the posting
+// lists of pendingAttr, until dropPending is called for their transaction.
+func savePending(ctx context.Context, gid uint32, m *task.Mutations) error {
+	val, err := m.Marshal()
+	if err != nil {
+		return err
+	}
+	pl, decr := posting.GetOrCreate(posting.Key(m.StartTs, pendingAttr(gid)))
+	defer decr()
+	_, err = pl.AddMutation(ctx, &task.DirectedEdge{
+		Entity: m.StartTs,
+		Attr:   pendingAttr(gid),
+		Value:  val,
+	}, posting.Set)
+	return err
+}
+
+// dropPending drops the mutations kept by savePending for the transaction
+// starting at startTs, if there are any.
+func dropPending(ctx context.Context, gid uint32, startTs uint64) error {
+	pl, decr := posting.GetOrCreate(posting.Key(startTs, pendingAttr(gid)))
+	defer decr()
+	val, _, err := pl.Value()
+	if err == posting.ErrNoValue {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	_, err = pl.AddMutation(ctx, &task.DirectedEdge{
+		Entity: startTs,
+		Attr:   pendingAttr(gid),
+		Value:  val,
+	}, posting.Del)
+	return err
+}
+
+// restorePending puts back in t the mutations kept by savePending in group
+// gid, on start.
+func restorePending(gid uint32, t *txnTable) error {
+	pending, err := posting.UidsWithAttr(pendingAttr(gid))
+	if err != nil {
+		return err
+	}
+	for _, startTs := range pending.Uids {
+		pl, decr := posting.GetOrCreate(posting.Key(startTs, pendingAttr(gid)))
+		val, _, err := pl.Value()
+		decr()
+		if err == posting.ErrNoValue {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		m := new(task.Mutations)
+		if err := m.Unmarshal(val); err != nil {
+			return err
+		}
+		t.restore(m)
+	}
+	return nil
+}
+
+// recordDecision writes the decision on a transaction to its primary record.
+// Only the first decision is kept: errTxnDecided is returned for another one.
+func recordDecision(ctx context.Context, txn *task.Txn) error {
+	pl, decr := posting.GetOrCreate(posting.Key(txn.StartTs, txnAttr))
+	defer decr()
+	if val, _, err := pl.Value(); err != posting.ErrNoValue {
+		if err != nil {
+			return err
+		}
+		if binary.BigEndian.Uint64(val) != txn.CommitTs {
+			return errTxnDecided
+		}
+		return nil
+	}
+	val := make([]byte, 8)
+	binary.BigEndian.PutUint64(val, txn.CommitTs)
+	_, err := pl.AddMutation(ctx, &task.DirectedEdge{
+		Entity: txn.StartTs,
+		Attr:   txnAttr,
+		Value:  val,
+	}, posting.Set)
+	return err
+}
+
+// decisionOverNetwork reads the primary record of the transaction starting at
+// startTs. It returns false if there's none yet.
+func decisionOverNetwork(ctx context.Context, startTs uint64) (*task.Txn, bool, error) {
+	res, err := ProcessTaskOverNetwork(ctx, &task.Query{
+		Attr: txnAttr,
+		Uids: []uint64{startTs},
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	if len(res.Values) == 0 || len(res.Values[0].Val) != 8 {
+		return nil, false, nil
+	}
+	return &task.Txn{
+		StartTs:  startTs,
+		CommitTs: binary.BigEndian.Uint64(res.Values[0].Val),
+	}, true, nil
+}
+
+// decidePrimary proposes the decision on a transaction to its primary record,
+// and returns the one kept there, which is another one if it was decided
+// already. An error is returned if the outcome isn't known.
+func decidePrimary(ctx context.Context, txn *task.Txn) (*task.Txn, error) {
+	primary := *txn
+	primary.GroupId = group.BelongsTo(txnAttr)
+	primary.Primary = true
+	che := make(chan error, 1)
+	decideOrSend(ctx, &primary, che)
+	err := <-che
+	if err == nil {
+		return &task.Txn{StartTs: txn.StartTs, CommitTs: txn.CommitTs}, nil
+	}
+
+	decided, ok, rerr := decisionOverNetwork(ctx, txn.StartTs)
+	if rerr == nil && !ok {
+		// The proposal might still be applied.
+		rerr = err
+	}
+	if rerr != nil {
+		return nil, x.Wrapf(rerr, "Transaction %d may or may not be committed", txn.StartTs)
+	}
+	return decided, nil
+}
+
+// decideOverNetwork sends the decision on a transaction to each of the
+// groups, once it's kept in its primary record. Each one is retried until it
+// is applied, or txnTimeout goes by, in which case the group resolves the
+// transaction from the record later on. It doesn't use the context of the
+// request, so that a transaction is decided in all the groups even if the
+// request is cancelled midway.
+func decideOverNetwork(txn *task.Txn, gids []uint32) error {
+	ctx, cancel := context.WithTimeout(context.Background(), txnTimeout)
+	defer cancel()
+
+	errors := make(chan error, len(gids))
+	for _, gid := range gids {
+		gtxn := *txn
+		gtxn.GroupId = gid
+		go decideWithRetries(ctx, &gtxn, errors)
+	}
+	var rerr error
+	for range gids {
+		if err := <-errors; err != nil && rerr == nil {
+			x.TraceError(ctx, x.Wrapf(err, "Error while deciding transaction %d", txn.StartTs))
+			rerr = err
+		}
+	}
+	return rerr
+}
+
+func decideWithRetries(ctx context.Context, txn *task.Txn, che chan error) {
+	wait := 10 * time.Millisecond
+	for {
+		c := make(chan error, 1)
+		decideOrSend(ctx, txn, c)
+		err := <-c
+		if err == nil {
+			che <- nil
+			return
+		}
+		select {
+		case <-ctx.Done():
+			che <- err
+			return
+		case <-time.After(wait):
+		}
+		if wait < time.Second {
+			wait *= 2
+		}
+	}
+}
+
+// resolveTxn decides the transaction starting at startTs, left pending in
+// group gid, from its primary record. One without a record yet is aborted
+// there first, so that its coordinator can't commit it any more.
+func resolveTxn(ctx context.Context, gid uint32, startTs uint64) error {
+	txn, err := decidePrimary(ctx, &task.Txn{StartTs: startTs})
+	if err != nil {
+		return err
+	}
+	txn.GroupId = gid
+	che := make(chan error, 1)
+	decideOrSend(ctx, txn, che)
+	return <-che
+}
+
+// resolvePeriodically resolves the orphaned transactions of the group, while
+// this node is the leader of it.
+func (n *node) resolvePeriodically() {
+	ticker := time.NewTicker(txnTimeout / 4)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if n.AmLeader() {
+				n.resolveOrphans()
+			}
+		case <-n.done:
+			return
+		}
+	}
+}
+
+func (n *node) resolveOrphans() {
+	ctx, cancel := context.WithTimeout(context.Background(), txnTimeout)
+	defer cancel()
+
+	now, err := timestamp(ctx)
+	if err != nil {
+		return
+	}
+	for _, startTs := range n.txns.orphans(now) {
+		if err := resolveTxn(ctx, n.gid, startTs); err != nil {
+			x.TraceError(ctx, x.Wrapf(err, "Error while resolving transaction %d", startTs))
+		}
+	}
+}
+
+// decideOrSend proposes the decision on a transaction to the group of txn if
+// this instance serves it, or sends it to the leader of the group otherwise.
+func decideOrSend(ctx context.Context, txn *task.Txn, che chan error) {
+	if groups().ServesGroup(txn.GroupId) {
+		node := groups().Node(txn.GroupId)
+		che <- node.ProposeAndWait(ctx, &task.Proposal{Txn: txn})
+		return
+	}
+
+	_, addr := groups().Leader(txn.GroupId)
+	pl := pools().get(addr)
+	conn, err := pl.Get()
+	if err != nil {
+		x.TraceError(ctx, err)
+		che <- err
+		return
+	}
+	defer pl.Put(conn)
+
+	c := NewWorkerClient(conn)
+	_, err = c.CommitOrAbort(ctx, txn)
+	che <- err
+}
+
+// CommitOrAbort is used to decide on transactions over the network, for the
+// groups served by other instances.
+func (w *grpcWorker) CommitOrAbort(ctx context.Context, txn *task.Txn) (*Payload, error) {
+	if ctx.Err() != nil {
+		return &Payload{}, ctx.Err()
+	}
+
+	if !groups().ServesGroup(txn.GroupId) {
+		return &Payload{}, x.Errorf("This server doesn't serve group id: %v", txn.GroupId)
+	}
+	c := make(chan error, 1)
+	node := groups().Node(txn.GroupId)
+	go func() { c <- node.ProposeAndWait(ctx, &task.Proposal{Txn: txn}) }()
+
+	select {
+	case <-ctx.Done():
+		return &Payload{}, ctx.Err()
+	case err := <-c:
+		return &Payload{}, err
+	}
+}
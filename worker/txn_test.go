@@ -0,0 +1,237 @@
+/*
+ * Copyright 2016 Dgraph Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * 		http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package worker
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/coreos/etcd/raft/raftpb"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dgraph-io/dgraph/posting"
+	"github.com/dgraph-io/dgraph/schema"
+	"github.com/dgraph-io/dgraph/task"
+	"github.com/dgraph-io/dgraph/types"
+	"github.com/dgraph-io/dgraph/x"
+)
+
+func txnMutations(startTs uint64, entity uint64, attr string) *task.Mutations {
+	return &task.Mutations{
+		StartTs: startTs,
+		Set: []*task.DirectedEdge{
+			{Entity: entity, Attr: attr, Value: []byte("value")},
+		},
+	}
+}
+
+func TestLease(t *testing.T) {
+	var o tsOracle
+	require.NoError(t, o.applyLease(&task.Lease{From: 0, To: 100}))
+
+	// A lease taken from a stale view is refused.
+	require.Equal(t, errLeaseTaken, o.applyLease(&task.Lease{From: 0, To: 200}))
+	require.Equal(t, errLeaseTaken, o.applyLease(&task.Lease{From: 100, To: 100}))
+	require.NoError(t, o.applyLease(&task.Lease{From: 100, To: 200}))
+	require.EqualValues(t, 200, o.leased)
+}
+
+func TestTxnCommit(t *testing.T) {
+	tt := newTxnTable()
+	m := txnMutations(10, 1, "name")
+	require.NoError(t, tt.prewrite(m))
+	require.Len(t, tt.locks, 1)
+
+	// Writes to other keys of the entity don't conflict.
+	require.NoError(t, tt.prewrite(txnMutations(11, 1, "age")))
+	require.NoError(t, tt.checkUnlocked(txnMutations(0, 2, "name")))
+	require.Equal(t, errConflict, tt.checkUnlocked(txnMutations(0, 1, "name")))
+
+	// A concurrent writer of the same key is aborted.
+	require.Equal(t, errConflict, tt.prewrite(txnMutations(12, 1, "name")))
+
+	out, err := tt.decide(&task.Txn{StartTs: 10, CommitTs: 20})
+	require.NoError(t, err)
+	require.Equal(t, m, out)
+	require.Empty(t, tt.pending[10])
+
+	// So is one which started before the commit.
+	require.Equal(t, errConflict, tt.prewrite(txnMutations(15, 1, "name")))
+	require.NoError(t, tt.prewrite(txnMutations(21, 1, "name")))
+
+	// Deciding it again does nothing.
+	out, err = tt.decide(&task.Txn{StartTs: 10, CommitTs: 22})
+	require.NoError(t, err)
+	require.Nil(t, out)
+}
+
+func TestTxnAbort(t *testing.T) {
+	tt := newTxnTable()
+	require.NoError(t, tt.prewrite(txnMutations(10, 1, "name")))
+	out, err := tt.decide(&task.Txn{StartTs: 10})
+	require.NoError(t, err)
+	require.Nil(t, out)
+	require.Empty(t, tt.locks)
+
+	// The key is free again, and aborting again does nothing.
+	require.NoError(t, tt.prewrite(txnMutations(11, 1, "name")))
+	_, err = tt.decide(&task.Txn{StartTs: 10})
+	require.NoError(t, err)
+	require.Len(t, tt.locks, 1)
+}
+
+func TestTxnTimeout(t *testing.T) {
+	tt := newTxnTable()
+	require.NoError(t, tt.prewrite(txnMutations(10, 1, "name")))
+
+	// The locks of a transaction which has timed out are kept, until it is
+	// resolved from its primary record.
+	start := 11 + txnTimeoutTs
+	require.Empty(t, tt.orphans(start-1))
+	require.Equal(t, []uint64{10}, tt.orphans(start))
+	require.Equal(t, errConflict, tt.prewrite(txnMutations(start, 1, "name")))
+	_, err := tt.decide(&task.Txn{StartTs: 10, CommitTs: start})
+	require.NoError(t, err)
+
+	require.NoError(t, tt.prewrite(txnMutations(start, 2, "name")))
+	_, err = tt.decide(&task.Txn{StartTs: start, CommitTs: start + 1})
+	require.NoError(t, err)
+	require.Equal(t, errTxnOld, tt.prewrite(txnMutations(10, 3, "name")))
+
+	// Old commits are pruned.
+	require.NoError(t, tt.prewrite(txnMutations(start+2, 4, "name")))
+	_, err = tt.decide(&task.Txn{StartTs: start + 2, CommitTs: start + 2*txnTimeoutTs})
+	require.NoError(t, err)
+	require.Contains(t, tt.commits, string(posting.Key(4, "name")))
+	for _, ts := range tt.commits {
+		require.Equal(t, start+2*txnTimeoutTs, ts)
+	}
+}
+
+func TestTxnLookups(t *testing.T) {
+	tt := newTxnTable()
+	has := &task.Query{Attr: "name", SrcFunc: []string{"has"}}
+	require.NoError(t, tt.prewrite(txnMutations(10, 1, "name")))
+
+	// A lookup conflicts with the pending writes to its attribute.
+	reader := txnMutations(11, 2, "age")
+	reader.Lookups = []*task.Query{has}
+	require.Equal(t, errConflict, tt.prewrite(reader))
+
+	// And with the ones committed since it started.
+	_, err := tt.decide(&task.Txn{StartTs: 10, CommitTs: 12})
+	require.NoError(t, err)
+	require.Equal(t, errConflict, tt.prewrite(reader))
+	reader.StartTs = 13
+	require.NoError(t, tt.prewrite(reader))
+
+	// The writers of the attribute are aborted while it is pending.
+	require.Equal(t, errConflict, tt.prewrite(txnMutations(14, 3, "name")))
+	require.NoError(t, tt.prewrite(txnMutations(15, 3, "friend")))
+	_, err = tt.decide(&task.Txn{StartTs: 13, CommitTs: 16})
+	require.NoError(t, err)
+	require.Empty(t, tt.readers)
+	require.NoError(t, tt.prewrite(txnMutations(17, 3, "name")))
+}
+
+func TestTxnLookupsIndexed(t *testing.T) {
+	require.NoError(t, schema.ParseBytes([]byte(`scalar email:string @index`)))
+	write := func(startTs uint64, email string) *task.Mutations {
+		return &task.Mutations{
+			StartTs: startTs,
+			Set: []*task.DirectedEdge{{Entity: startTs, Attr: "email",
+				Value: []byte(email), ValueType: uint32(types.StringID)}},
+		}
+	}
+	tt := newTxnTable()
+	reader := write(10, "alice@example.com")
+	reader.Lookups = []*task.Query{{Attr: "email",
+		SrcFunc: []string{"eq", "alice@example.com"}}}
+	require.NoError(t, tt.prewrite(reader))
+
+	// Only the writes of the value looked up conflict.
+	require.NoError(t, tt.prewrite(write(11, "bob@example.com")))
+	require.Equal(t, errConflict, tt.prewrite(write(12, "alice@example.com")))
+
+	// Deleting all the values may delete it too.
+	del := &task.Mutations{
+		StartTs: 13,
+		Del:     []*task.DirectedEdge{{Entity: 1, Attr: "email", Value: []byte(x.Star)}},
+	}
+	require.Equal(t, errConflict, tt.prewrite(del))
+}
+
+func TestTxnKilledGroup(t *testing.T) {
+	dir, ps := initTest(t, `scalar name:string`)
+	defer os.RemoveAll(dir)
+	defer ps.Close()
+	serveGroupZero()
+
+	// A transaction is prewritten in two groups, committed in its primary
+	// record and in the first group, and then the second one goes down.
+	ctx := context.Background()
+	n1, n2 := newNode(1, 1, ""), newNode(2, 1, "")
+	startTs := uint64(1000)
+	require.NoError(t, n1.processMutation(raftpb.Entry{}, txnMutations(startTs, 301, "name")))
+	require.NoError(t, n2.processMutation(raftpb.Entry{}, txnMutations(startTs, 302, "name")))
+	commit := &task.Txn{StartTs: startTs, CommitTs: startTs + 1}
+	require.NoError(t, recordDecision(ctx, commit))
+	require.NoError(t, n1.processTxn(raftpb.Entry{}, commit))
+
+	// Once it's back, the transaction is pending still.
+	n2 = newNode(2, 1, "")
+	require.NoError(t, restorePending(2, n2.txns))
+	require.Equal(t, []uint64{startTs}, n2.txns.orphans(startTs+txnTimeoutTs+1))
+	require.Equal(t, errConflict, n2.txns.prewrite(txnMutations(startTs+2, 302, "name")))
+
+	// It can't be aborted any more, and is committed from its record instead.
+	require.Equal(t, errTxnDecided, recordDecision(ctx, &task.Txn{StartTs: startTs}))
+	decided, ok, err := decisionOverNetwork(ctx, startTs)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, commit.CommitTs, decided.CommitTs)
+	require.NoError(t, n2.processTxn(raftpb.Entry{}, decided))
+	require.Empty(t, n2.txns.pending)
+	pending, err := posting.UidsWithAttr(pendingAttr(2))
+	require.NoError(t, err)
+	require.Empty(t, pending.Uids)
+	for _, uid := range []uint64{301, 302} {
+		val, _, err := getOrCreate(posting.Key(uid, "name")).Value()
+		require.NoError(t, err)
+		require.Equal(t, "value", string(val))
+	}
+
+	// One whose coordinator went away before writing the record is aborted.
+	startTs += 10
+	require.NoError(t, n1.processMutation(raftpb.Entry{}, txnMutations(startTs, 303, "name")))
+	n1 = newNode(1, 1, "")
+	require.NoError(t, restorePending(1, n1.txns))
+	_, ok, err = decisionOverNetwork(ctx, startTs)
+	require.NoError(t, err)
+	require.False(t, ok)
+	abort := &task.Txn{StartTs: startTs}
+	require.NoError(t, recordDecision(ctx, abort))
+	require.NoError(t, n1.processTxn(raftpb.Entry{}, abort))
+	require.Empty(t, n1.txns.locks)
+
+	// So the coordinator can't commit it any more.
+	require.Equal(t, errTxnDecided, recordDecision(ctx, &task.Txn{StartTs: startTs,
+		CommitTs: startTs + 1}))
+	_, _, err = getOrCreate(posting.Key(303, "name")).Value()
+	require.Equal(t, posting.ErrNoValue, err)
+}
@@ -26,6 +26,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/dgraph-io/dgraph/algo"
+	"github.com/dgraph-io/dgraph/group"
 	"github.com/dgraph-io/dgraph/posting"
 	"github.com/dgraph-io/dgraph/schema"
 	"github.com/dgraph-io/dgraph/store"
@@ -357,7 +358,7 @@ func TestProcessSort(t *testing.T) {
 		{10, 11, 12, 13, 14, 21},
 		{16, 17, 18, 19, 20, 21},
 	}, 0, 1000)
-	r, err := processSort(sort)
+	r, err := processSort(context.Background(), sort)
 	require.NoError(t, err)
 
 	// The sorted UIDs are: (17 16 15) (20 21 19 18) (13 14 12) (11 10)
@@ -381,7 +382,7 @@ func TestProcessSortOffset(t *testing.T) {
 
 	// Offset 1.
 	sort := newSort(input, 1, 1000)
-	r, err := processSort(sort)
+	r, err := processSort(context.Background(), sort)
 	require.NoError(t, err)
 	require.EqualValues(t, [][]uint64{
 		{16, 15, 20, 21, 19, 18, 13, 14, 12, 11, 10},
@@ -391,7 +392,7 @@ func TestProcessSortOffset(t *testing.T) {
 
 	// Offset 2.
 	sort = newSort(input, 2, 1000)
-	r, err = processSort(sort)
+	r, err = processSort(context.Background(), sort)
 	require.NoError(t, err)
 	require.EqualValues(t, [][]uint64{
 		{15, 20, 21, 19, 18, 13, 14, 12, 11, 10},
@@ -401,7 +402,7 @@ func TestProcessSortOffset(t *testing.T) {
 
 	// Offset 5.
 	sort = newSort(input, 5, 1000)
-	r, err = processSort(sort)
+	r, err = processSort(context.Background(), sort)
 	require.NoError(t, err)
 	require.EqualValues(t, [][]uint64{
 		{19, 18, 13, 14, 12, 11, 10},
@@ -411,7 +412,7 @@ func TestProcessSortOffset(t *testing.T) {
 
 	// Offset 6.
 	sort = newSort(input, 6, 1000)
-	r, err = processSort(sort)
+	r, err = processSort(context.Background(), sort)
 	require.NoError(t, err)
 	require.EqualValues(t, [][]uint64{
 		{18, 13, 14, 12, 11, 10},
@@ -421,7 +422,7 @@ func TestProcessSortOffset(t *testing.T) {
 
 	// Offset 7.
 	sort = newSort(input, 7, 1000)
-	r, err = processSort(sort)
+	r, err = processSort(context.Background(), sort)
 	require.NoError(t, err)
 	require.EqualValues(t, [][]uint64{
 		{13, 14, 12, 11, 10},
@@ -443,7 +444,7 @@ func TestProcessSortCount(t *testing.T) {
 
 	// Count 1.
 	sort := newSort(input, 0, 1)
-	r, err := processSort(sort)
+	r, err := processSort(context.Background(), sort)
 	require.NoError(t, err)
 	require.EqualValues(t, [][]uint64{
 		{17},
@@ -453,7 +454,7 @@ func TestProcessSortCount(t *testing.T) {
 
 	// Count 2.
 	sort = newSort(input, 0, 2)
-	r, err = processSort(sort)
+	r, err = processSort(context.Background(), sort)
 	require.NoError(t, err)
 
 	require.NotNil(t, r)
@@ -465,7 +466,7 @@ func TestProcessSortCount(t *testing.T) {
 
 	// Count 5.
 	sort = newSort(input, 0, 5)
-	r, err = processSort(sort)
+	r, err = processSort(context.Background(), sort)
 	require.NoError(t, err)
 
 	require.NotNil(t, r)
@@ -477,7 +478,7 @@ func TestProcessSortCount(t *testing.T) {
 
 	// Count 6.
 	sort = newSort(input, 0, 6)
-	r, err = processSort(sort)
+	r, err = processSort(context.Background(), sort)
 	require.NoError(t, err)
 
 	require.NotNil(t, r)
@@ -489,7 +490,7 @@ func TestProcessSortCount(t *testing.T) {
 
 	// Count 7.
 	sort = newSort(input, 0, 7)
-	r, err = processSort(sort)
+	r, err = processSort(context.Background(), sort)
 	require.NoError(t, err)
 
 	require.NotNil(t, r)
@@ -513,7 +514,7 @@ func TestProcessSortOffsetCount(t *testing.T) {
 
 	// Offset 1. Count 1.
 	sort := newSort(input, 1, 1)
-	r, err := processSort(sort)
+	r, err := processSort(context.Background(), sort)
 	require.NoError(t, err)
 	require.EqualValues(t, [][]uint64{
 		{16},
@@ -523,7 +524,7 @@ func TestProcessSortOffsetCount(t *testing.T) {
 
 	// Offset 1. Count 2.
 	sort = newSort(input, 1, 2)
-	r, err = processSort(sort)
+	r, err = processSort(context.Background(), sort)
 	require.NoError(t, err)
 
 	require.EqualValues(t, [][]uint64{
@@ -534,7 +535,7 @@ func TestProcessSortOffsetCount(t *testing.T) {
 
 	// Offset 1. Count 3.
 	sort = newSort(input, 1, 3)
-	r, err = processSort(sort)
+	r, err = processSort(context.Background(), sort)
 	require.NoError(t, err)
 
 	require.EqualValues(t, [][]uint64{
@@ -545,7 +546,7 @@ func TestProcessSortOffsetCount(t *testing.T) {
 
 	// Offset 1. Count 1000.
 	sort = newSort(input, 1, 1000)
-	r, err = processSort(sort)
+	r, err = processSort(context.Background(), sort)
 	require.NoError(t, err)
 
 	require.EqualValues(t, [][]uint64{
@@ -556,7 +557,7 @@ func TestProcessSortOffsetCount(t *testing.T) {
 
 	// Offset 5. Count 1.
 	sort = newSort(input, 5, 1)
-	r, err = processSort(sort)
+	r, err = processSort(context.Background(), sort)
 	require.NoError(t, err)
 
 	require.EqualValues(t, [][]uint64{
@@ -567,7 +568,7 @@ func TestProcessSortOffsetCount(t *testing.T) {
 
 	// Offset 5. Count 2.
 	sort = newSort(input, 5, 2)
-	r, err = processSort(sort)
+	r, err = processSort(context.Background(), sort)
 	require.NoError(t, err)
 
 	require.EqualValues(t, [][]uint64{
@@ -578,7 +579,7 @@ func TestProcessSortOffsetCount(t *testing.T) {
 
 	// Offset 5. Count 3.
 	sort = newSort(input, 5, 3)
-	r, err = processSort(sort)
+	r, err = processSort(context.Background(), sort)
 	require.NoError(t, err)
 
 	require.EqualValues(t, [][]uint64{
@@ -589,7 +590,7 @@ func TestProcessSortOffsetCount(t *testing.T) {
 
 	// Offset 100. Count 100.
 	sort = newSort(input, 100, 100)
-	r, err = processSort(sort)
+	r, err = processSort(context.Background(), sort)
 	require.NoError(t, err)
 
 	require.EqualValues(t, [][]uint64{
@@ -599,6 +600,171 @@ func TestProcessSortOffsetCount(t *testing.T) {
 		algo.ToUintsListForTest(r.UidMatrix))
 }
 
+func TestProcessSortDesc(t *testing.T) {
+	dir, ps := initTest(t, `scalar dob:date @index`)
+	defer os.RemoveAll(dir)
+	defer ps.Close()
+	populateGraphForSort(t, ps)
+
+	input := [][]uint64{
+		{10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20, 21},
+		{10, 11, 12, 13, 14, 21},
+		{16, 17, 18, 19, 20, 21}}
+
+	sort := newSort(input, 1, 4)
+	sort.Desc = true
+	r, err := processSort(context.Background(), sort)
+	require.NoError(t, err)
+	require.EqualValues(t, [][]uint64{
+		{11, 12, 14, 13},
+		{11, 12, 14, 13},
+		{19, 21, 20, 16}},
+		algo.ToUintsListForTest(r.UidMatrix))
+}
+
+func TestProcessSortNoCount(t *testing.T) {
+	dir, ps := initTest(t, `scalar dob:date @index`)
+	defer os.RemoveAll(dir)
+	defer ps.Close()
+	populateGraphForSort(t, ps)
+
+	// No count means all of the UIDs, after the offset.
+	sort := newSort([][]uint64{{10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20, 21}}, 2, 0)
+	sort.Desc = true
+	r, err := processSort(context.Background(), sort)
+	require.NoError(t, err)
+	require.EqualValues(t, [][]uint64{
+		{12, 14, 13, 18, 19, 21, 20, 15, 16, 17}},
+		algo.ToUintsListForTest(r.UidMatrix))
+}
+
+// serveGroupZero makes this instance serve group zero, which all the
+// predicates belong to without a group config.
+func serveGroupZero() {
+	group.ParseGroupConfig("")
+	if gr == nil {
+		gr = new(groupi)
+	}
+	if gr.Node(0) == nil {
+		gr.newNode(0, 1, "")
+	}
+}
+
+func populateGraphForTies(t *testing.T) {
+	ages := []string{"30", "20", "30", "20", "30", "25"}
+	names := map[uint64]string{10: "b", 11: "a", 12: "a", 14: "c", 15: "a"}
+	for i, age := range ages {
+		uid := uint64(i + 10)
+		edge := &task.DirectedEdge{Entity: uid, Attr: "age", Value: []byte(age), Label: "ties"}
+		addEdge(t, edge, getOrCreate(posting.Key(uid, edge.Attr)))
+		if name, ok := names[uid]; ok {
+			edge := &task.DirectedEdge{Entity: uid, Attr: "name", Value: []byte(name), Label: "ties"}
+			addEdge(t, edge, getOrCreate(posting.Key(uid, edge.Attr)))
+		}
+	}
+	time.Sleep(200 * time.Millisecond) // Let indexing finish.
+}
+
+func TestProcessSortThenBy(t *testing.T) {
+	dir, ps := initTest(t, "scalar age:int @index\nscalar name:string")
+	defer os.RemoveAll(dir)
+	defer ps.Close()
+	serveGroupZero()
+	populateGraphForTies(t)
+
+	input := [][]uint64{{10, 11, 12, 13, 14, 15}}
+	sort := newSort(input, 1, 4)
+	sort.Attr = "age"
+	sort.ThenBy = []*task.Order{{Attr: "name", Desc: true}}
+	r, err := processSort(context.Background(), sort)
+	require.NoError(t, err)
+	// The sorted UIDs are: (11 13) (15) (14 10 12), 13 having no name.
+	require.EqualValues(t, [][]uint64{{13, 15, 14, 10}},
+		algo.ToUintsListForTest(r.UidMatrix))
+
+	// A negative count asks for the last UIDs.
+	sort = newSort(input, 0, -4)
+	sort.Attr = "age"
+	sort.ThenBy = []*task.Order{{Attr: "name", Desc: true}}
+	r, err = processSort(context.Background(), sort)
+	require.NoError(t, err)
+	require.EqualValues(t, [][]uint64{{15, 14, 10, 12}},
+		algo.ToUintsListForTest(r.UidMatrix))
+
+	// The order is (12 10 14) (15) (11 13).
+	sort = newSort(input, 0, -3)
+	sort.Attr = "age"
+	sort.Desc = true
+	sort.ThenBy = []*task.Order{{Attr: "name"}}
+	r, err = processSort(context.Background(), sort)
+	require.NoError(t, err)
+	require.EqualValues(t, [][]uint64{{15, 11, 13}},
+		algo.ToUintsListForTest(r.UidMatrix))
+}
+
+func populateGraphForCount(t *testing.T, attr string) {
+	edges := map[uint64][]uint64{
+		1: {2, 3, 4},
+		2: {3},
+		3: {4, 5},
+	}
+	for src, dsts := range edges {
+		for _, dst := range dsts {
+			edge := &task.DirectedEdge{
+				Entity:  src,
+				ValueId: dst,
+				Label:   "count",
+				Attr:    attr,
+			}
+			addEdge(t, edge, getOrCreate(posting.Key(src, attr)))
+		}
+	}
+	// Moves 1 from the token for 3 edges to the one for 2.
+	delEdge(t, &task.DirectedEdge{Entity: 1, ValueId: 4, Label: "count", Attr: attr},
+		getOrCreate(posting.Key(1, attr)))
+}
+
+func TestProcessCountFunc(t *testing.T) {
+	dir, ps := initTest(t, `type Person { follows: Person @count }`)
+	defer os.RemoveAll(dir)
+	defer ps.Close()
+	populateGraphForCount(t, "follows")
+	populateGraphForCount(t, "knows")
+
+	for _, tc := range []struct {
+		attr    string
+		uids    []uint64
+		srcFunc []string
+		out     []uint64
+	}{
+		{"follows", []uint64{1, 2, 3, 5}, []string{"gt", "1"}, []uint64{1, 3}},
+		{"follows", nil, []string{"ge", "2"}, []uint64{1, 3}},
+		{"follows", nil, []string{"eq", "1"}, []uint64{2}},
+		{"follows", []uint64{1, 2, 3, 5}, []string{"le", "1"}, []uint64{2, 5}},
+		{"follows", []uint64{1, 2, 5}, []string{"between", "0", "2"}, []uint64{1, 2, 5}},
+		{"knows", []uint64{1, 2, 3, 5}, []string{"gt", "1"}, []uint64{1, 3}},
+		{"knows", []uint64{1, 2, 3, 5}, []string{"lt", "1"}, []uint64{5}},
+	} {
+		q := &task.Query{Attr: tc.attr, Uids: tc.uids, SrcFunc: tc.srcFunc, DoCount: true}
+		r, err := processTask(q)
+		require.NoError(t, err, "%+v", tc)
+		require.EqualValues(t, [][]uint64{tc.out}, algo.ToUintsListForTest(r.UidMatrix),
+			"%+v", tc)
+	}
+
+	for _, q := range []*task.Query{
+		// Zero counts aren't in the index, so can't be found at the root.
+		{Attr: "follows", SrcFunc: []string{"lt", "2"}, DoCount: true},
+		// Without the index, the root can't be looked up at all.
+		{Attr: "knows", SrcFunc: []string{"gt", "1"}, DoCount: true},
+		{Attr: "follows", Uids: []uint64{1}, SrcFunc: []string{"gt", "x"}, DoCount: true},
+		{Attr: "follows", Uids: []uint64{1}, SrcFunc: []string{"anyof", "1"}, DoCount: true},
+	} {
+		_, err := processTask(q)
+		require.Error(t, err, "%+v", q)
+	}
+}
+
 func TestMain(m *testing.M) {
 	x.Init()
 	os.Exit(m.Run())
@@ -41,6 +41,11 @@ const (
 	ErrorInvalidMutation = "ErrorInvalidMutation"
 )
 
+// Star stands for the * of a deletion like S P * or S * *, which deletes all
+// the values of a predicate, or all the predicates of an entity. It is used
+// as the value, and as the predicate for the latter.
+const Star = "_STAR_ALL"
+
 var (
 	debugMode = flag.Bool("debugmode", false,
 		"enable debug mode for more debug information")